@@ -0,0 +1,41 @@
+package intasend
+
+import "context"
+
+// Actor identifies the person or service on whose behalf a call moves
+// money, for audit trails. IntaSend's payout, approval, and chargeback
+// endpoints have no field for it, so it is recorded on the client side:
+// in PayoutInitiated, PayoutApproved, and RefundCreated events, in
+// PendingApproval, and in TrackedPayout records.
+type Actor struct {
+	// ID is your identifier for the actor, such as a user ID or a service
+	// name like "payroll-cron".
+	ID string
+
+	// Email is the actor's email address, if it is a person.
+	Email string
+}
+
+// actorKey is the context key for ContextWithActor.
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx identifying actor as the one
+// making its calls.
+//
+// Example:
+//
+//	ctx = intasend.ContextWithActor(r.Context(), intasend.Actor{ID: user.ID, Email: user.Email})
+//	approved, err := client.Payout().Approve(ctx, req)
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set with ContextWithActor, and
+// whether one was set.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
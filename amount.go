@@ -0,0 +1,312 @@
+package intasend
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxAmountDecimals is the number of decimal places the API accepts.
+const maxAmountDecimals = 2
+
+// formatAmount renders v as a plain decimal with at most two decimal
+// places, never in scientific notation. It rounds the shortest decimal
+// form of v half away from zero, so 1.005 becomes "1.01" rather than
+// following the float's binary value down, and drops trailing zeros. It is
+// the one place amounts are turned into request text; NaN and infinities
+// are the caller's to reject.
+func formatAmount(v float64) string {
+	s := strconv.FormatFloat(math.Abs(v), 'f', -1, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > maxAmountDecimals {
+		roundUp := frac[maxAmountDecimals] >= '5'
+		frac = frac[:maxAmountDecimals]
+		if roundUp {
+			digits := incrementDigits(whole + frac)
+			whole, frac = digits[:len(digits)-maxAmountDecimals], digits[len(digits)-maxAmountDecimals:]
+		}
+	}
+	frac = strings.TrimRight(frac, "0")
+
+	s = whole
+	if frac != "" {
+		s += "." + frac
+	}
+	if v < 0 && s != "0" {
+		s = "-" + s
+	}
+	return s
+}
+
+// incrementDigits adds one to a string of decimal digits.
+func incrementDigits(digits string) string {
+	b := []byte(digits)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '9' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b)
+}
+
+// Amount is an exact decimal amount of money, such as "1500.50", for use
+// where float64 rounding is not acceptable: summing ledgers, comparing
+// balances, or sending an amount exactly as a customer entered it.
+//
+// It encodes as a JSON number and decodes from numbers and strings alike.
+// The float64 fields on requests and responses remain for compatibility;
+// requests have an AmountDecimal field sent in place of Amount when set,
+// and responses have accessors such as Invoice.ValueDecimal. Methods treat
+// an empty or invalid Amount as zero; use ParseAmount to validate input.
+//
+// Example:
+//
+//	var total intasend.Amount
+//	for _, inv := range invoices {
+//	    total = total.Add(inv.ValueDecimal())
+//	}
+//	fmt.Println(total) // 1500.50, not 1500.4999999999998
+type Amount string
+
+// maxAmountExponent bounds the exponents accepted when decoding amounts,
+// so "1e1000000" cannot expand into a megabyte of zeros.
+const maxAmountExponent = 100
+
+// ParseAmount parses a decimal amount such as "1500.50" or "-20". Leading
+// and trailing spaces are ignored. It returns ErrInvalidAmount for
+// anything else, including exponents.
+func ParseAmount(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, "eE") {
+		return "", fmt.Errorf("%w: got %q", ErrInvalidAmount, s)
+	}
+	a, ok := normalizeDecimal(s)
+	if !ok {
+		return "", fmt.Errorf("%w: got %q", ErrInvalidAmount, s)
+	}
+	return a, nil
+}
+
+// MustAmount is like ParseAmount but panics on invalid input. It is meant
+// for constants in code and tests.
+func MustAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// AmountFromFloat converts v using the shortest decimal that reads back as
+// v, so amounts decoded from the API, which have at most 15 significant
+// digits, convert exactly: 100.1 becomes "100.1". NaN and infinities give
+// an invalid Amount that fails to encode.
+func AmountFromFloat(v float64) Amount {
+	return Amount(strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// AmountFromCents converts an amount in cents, such as 150050 for
+// "1500.50".
+func AmountFromCents(cents int64) Amount {
+	return Amount(new(big.Rat).SetFrac64(cents, 100).FloatString(maxAmountDecimals))
+}
+
+// String returns the amount as a decimal string.
+func (a Amount) String() string { return string(a) }
+
+// IsZero reports whether the amount is zero or empty.
+func (a Amount) IsZero() bool { return a.rat().Sign() == 0 }
+
+// Float64 returns the nearest float64 to the amount.
+func (a Amount) Float64() float64 {
+	v, _ := a.rat().Float64()
+	return v
+}
+
+// Cents returns the amount in cents. It returns ErrInvalidAmount if the
+// amount has a fraction of a cent or does not fit in an int64.
+func (a Amount) Cents() (int64, error) {
+	r := new(big.Rat).Mul(a.rat(), big.NewRat(100, 1))
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %s is not a whole number of cents", ErrInvalidAmount, a)
+	}
+	return r.Num().Int64(), nil
+}
+
+// Cmp compares a and b, returning -1, 0, or +1.
+func (a Amount) Cmp(b Amount) int { return a.rat().Cmp(b.rat()) }
+
+// Add returns a+b, with as many decimal places as the more precise of the
+// two.
+func (a Amount) Add(b Amount) Amount {
+	return Amount(new(big.Rat).Add(a.rat(), b.rat()).FloatString(maxInt(a.scale(), b.scale())))
+}
+
+// Sub returns a-b, with as many decimal places as the more precise of the
+// two.
+func (a Amount) Sub(b Amount) Amount {
+	return Amount(new(big.Rat).Sub(a.rat(), b.rat()).FloatString(maxInt(a.scale(), b.scale())))
+}
+
+// MarshalJSON implements json.Marshaler, encoding the amount as a JSON
+// number, or null when empty.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	if a == "" {
+		return []byte("null"), nil
+	}
+	n, ok := normalizeDecimal(string(a))
+	if !ok {
+		return nil, fmt.Errorf("%w: got %q", ErrInvalidAmount, string(a))
+	}
+	return []byte(n), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting numbers and
+// strings such as "100.00". Null and empty strings leave the amount
+// unchanged.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if len(s) >= 2 && s[0] == '"' {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+		if s == "" {
+			return nil
+		}
+	}
+	n, ok := normalizeDecimal(s)
+	if !ok {
+		return fmt.Errorf("intasend: invalid amount %s", data)
+	}
+	*a = n
+	return nil
+}
+
+// rat returns the amount as a rational, or zero if it is empty or invalid.
+func (a Amount) rat() *big.Rat {
+	r := new(big.Rat)
+	if n, ok := normalizeDecimal(string(a)); ok {
+		r.SetString(string(n))
+	}
+	return r
+}
+
+// scale returns the number of decimal places in the amount.
+func (a Amount) scale() int {
+	n, ok := normalizeDecimal(string(a))
+	if !ok {
+		return 0
+	}
+	if i := strings.IndexByte(string(n), '.'); i >= 0 {
+		return len(n) - i - 1
+	}
+	return 0
+}
+
+// normalizeDecimal parses a decimal with an optional sign, fraction, and
+// exponent, and returns it without exponent, plus sign, or leading zeros.
+// Trailing zeros in the fraction are kept, so "100.50" stays as written.
+func normalizeDecimal(s string) (Amount, bool) {
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > maxAmountExponent || e < -maxAmountExponent {
+			return "", false
+		}
+		s, exp = s[:i], e
+	}
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || !isDigits(whole) || (hasFrac && (frac == "" || !isDigits(frac))) {
+		return "", false
+	}
+
+	digits, point := whole+frac, len(whole)+exp
+	switch {
+	case point <= 0:
+		whole, frac = "0", strings.Repeat("0", -point)+digits
+	case point >= len(digits):
+		whole, frac = digits+strings.Repeat("0", point-len(digits)), ""
+	default:
+		whole, frac = digits[:point], digits[point:]
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+
+	n := whole
+	if frac != "" {
+		n += "." + frac
+	}
+	if neg && strings.Trim(whole+frac, "0") != "" {
+		n = "-" + n
+	}
+	return Amount(n), true
+}
+
+// requestAmount returns the amount to send for a request: exact if set,
+// else v rounded to cents with formatAmount.
+func requestAmount(exact Amount, v float64) Amount {
+	if exact != "" {
+		return exact
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return AmountFromFloat(v)
+	}
+	return Amount(formatAmount(v))
+}
+
+// maxInt returns the larger of a and b.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// FormatAmount formats a payout amount as a plain decimal string rounded to
+// at most 2 decimal places, e.g. 1e6 becomes "1000000" and 12.5 becomes "12.5".
+// It returns ErrInvalidAmount for zero, negative, or non-finite amounts.
+func FormatAmount(amount float64) (string, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return "", fmt.Errorf("%w: got %v", ErrInvalidAmount, amount)
+	}
+	s := formatAmount(amount)
+	if amount <= 0 || s == "0" {
+		return "", fmt.Errorf("%w: got %v", ErrInvalidAmount, amount)
+	}
+	return s, nil
+}
+
+// ValidateAmount checks that a string payout amount is a positive plain
+// decimal with at most 2 decimal places.
+func ValidateAmount(amount string) error {
+	whole, frac, hasFrac := strings.Cut(amount, ".")
+	if whole == "" || !isDigits(whole) || (hasFrac && (frac == "" || len(frac) > 2 || !isDigits(frac))) {
+		return fmt.Errorf("%w: got %q", ErrInvalidAmount, amount)
+	}
+	if strings.Trim(whole+frac, "0") == "" {
+		return fmt.Errorf("%w: got %q", ErrInvalidAmount, amount)
+	}
+	return nil
+}
+
+// isDigits reports whether s consists only of ASCII digits.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
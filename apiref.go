@@ -0,0 +1,161 @@
+package intasend
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxAPIRefLength is the longest api_ref the metadata helpers produce. The
+// API does not document a limit, so it is kept conservative; longer
+// references are rejected rather than truncated, which would make them
+// unparseable.
+const MaxAPIRefLength = 100
+
+// Reserved api_ref metadata keys.
+const (
+	apiRefOrderKey    = "order"
+	apiRefCustomerKey = "customer"
+	apiRefAttemptKey  = "attempt"
+)
+
+// APIRefMetadata is structured metadata carried in a payment's api_ref.
+// IntaSend has no metadata field, so the api_ref, which is echoed back on
+// invoices and webhooks, is the only place to keep it.
+type APIRefMetadata struct {
+	// OrderID identifies the order being paid for.
+	OrderID string
+
+	// CustomerID identifies the paying customer in your system.
+	CustomerID string
+
+	// Attempt numbers retries of the same order, starting at 1. Zero omits it.
+	Attempt int
+
+	// Extra holds any other keys. It must not use the keys "order",
+	// "customer", or "attempt".
+	Extra map[string]string
+}
+
+// APIRefCodec packs metadata into an api_ref and unpacks it again.
+// Implement it to use a different scheme with the same helpers.
+type APIRefCodec interface {
+	EncodeAPIRef(m APIRefMetadata) (string, error)
+	DecodeAPIRef(ref string) (APIRefMetadata, error)
+}
+
+// DefaultAPIRefCodec encodes metadata as a URL query string with sorted
+// keys, such as "attempt=2&customer=C-9&order=ORD-1", which is stable,
+// readable in the dashboard, and safe for any key or value.
+var DefaultAPIRefCodec APIRefCodec = queryAPIRefCodec{}
+
+// EncodeAPIRef packs m into an api_ref using DefaultAPIRefCodec.
+//
+// Example:
+//
+//	ref, err := intasend.EncodeAPIRef(intasend.APIRefMetadata{
+//	    OrderID:    "ORD-1",
+//	    CustomerID: "C-9",
+//	    Attempt:    2,
+//	})
+//	push, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{
+//	    PhoneNumber: "254712345678",
+//	    Amount:      500,
+//	    APIRef:      ref,
+//	})
+func EncodeAPIRef(m APIRefMetadata) (string, error) {
+	return DefaultAPIRefCodec.EncodeAPIRef(m)
+}
+
+// DecodeAPIRef unpacks an api_ref produced by EncodeAPIRef. References not
+// in that format return ErrInvalidAPIRef.
+//
+// Example:
+//
+//	meta, err := intasend.DecodeAPIRef(invoice.APIRef)
+//	if err == nil {
+//	    markPaid(meta.OrderID)
+//	}
+func DecodeAPIRef(ref string) (APIRefMetadata, error) {
+	return DefaultAPIRefCodec.DecodeAPIRef(ref)
+}
+
+type queryAPIRefCodec struct{}
+
+func (queryAPIRefCodec) EncodeAPIRef(m APIRefMetadata) (string, error) {
+	if m.Attempt < 0 {
+		return "", fmt.Errorf("%w: negative attempt", ErrInvalidAPIRef)
+	}
+	q := url.Values{}
+	for k, v := range m.Extra {
+		switch k {
+		case "":
+			return "", fmt.Errorf("%w: empty key", ErrInvalidAPIRef)
+		case apiRefOrderKey, apiRefCustomerKey, apiRefAttemptKey:
+			return "", fmt.Errorf("%w: reserved key %q in Extra", ErrInvalidAPIRef, k)
+		}
+		q.Set(k, v)
+	}
+	if m.OrderID != "" {
+		q.Set(apiRefOrderKey, m.OrderID)
+	}
+	if m.CustomerID != "" {
+		q.Set(apiRefCustomerKey, m.CustomerID)
+	}
+	if m.Attempt > 0 {
+		q.Set(apiRefAttemptKey, strconv.Itoa(m.Attempt))
+	}
+	if len(q) == 0 {
+		return "", fmt.Errorf("%w: no metadata", ErrInvalidAPIRef)
+	}
+
+	ref := q.Encode()
+	if len(ref) > MaxAPIRefLength {
+		return "", fmt.Errorf("%w: encoded length %d exceeds %d", ErrInvalidAPIRef, len(ref), MaxAPIRefLength)
+	}
+	return ref, nil
+}
+
+func (queryAPIRefCodec) DecodeAPIRef(ref string) (APIRefMetadata, error) {
+	var m APIRefMetadata
+	if !strings.Contains(ref, "=") {
+		return m, fmt.Errorf("%w: %q is not encoded metadata", ErrInvalidAPIRef, ref)
+	}
+	q, err := url.ParseQuery(ref)
+	if err != nil {
+		return m, fmt.Errorf("%w: %v", ErrInvalidAPIRef, err)
+	}
+
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		vs := q[k]
+		if len(vs) != 1 {
+			return m, fmt.Errorf("%w: duplicate key %q", ErrInvalidAPIRef, k)
+		}
+		v := vs[0]
+		switch k {
+		case apiRefOrderKey:
+			m.OrderID = v
+		case apiRefCustomerKey:
+			m.CustomerID = v
+		case apiRefAttemptKey:
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return m, fmt.Errorf("%w: invalid attempt %q", ErrInvalidAPIRef, v)
+			}
+			m.Attempt = n
+		default:
+			if m.Extra == nil {
+				m.Extra = make(map[string]string)
+			}
+			m.Extra[k] = v
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,76 @@
+package intasend
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PendingApproval describes a payout batch that was initiated and is
+// waiting for approval.
+type PendingApproval struct {
+	TrackingID string
+	Nonce      string
+	Provider   Provider
+	Currency   string
+	WalletID   string
+
+	// Count is the number of transactions in the batch.
+	Count int
+
+	// Total is the sum of the transaction amounts.
+	Total float64
+
+	// Link is the approval URL built from the template passed to
+	// WithApprovalNotifier, or empty if none was given.
+	Link string
+
+	// Fields holds the context fields of the initiating request; see
+	// ContextWithFields.
+	Fields map[string]interface{}
+
+	// Actor is who initiated the batch; see ContextWithActor.
+	Actor Actor
+
+	CreatedAt time.Time
+}
+
+// ApprovalNotifier is called for every payout batch initiated that needs
+// approval: RequiresApproval is "YES", unset, or forced by
+// WithEnforceApproval. It runs synchronously before Initiate returns, so slow deliveries
+// such as chat messages should be handed to a goroutine or queue.
+type ApprovalNotifier func(ctx context.Context, p *PendingApproval)
+
+// notifyApproval calls the approval notifier for a batch that needs
+// approval.
+func (s *PayoutService) notifyApproval(ctx context.Context, req *InitiateRequest, resp *InitiateResponse) {
+	notify := s.client.approvalNotifier
+	if notify == nil || req.RequiresApproval == ApprovalNotRequired {
+		return
+	}
+	p := &PendingApproval{
+		TrackingID: resp.TrackingID,
+		Nonce:      resp.Nonce,
+		Provider:   req.Provider,
+		Currency:   req.Currency,
+		WalletID:   req.WalletID,
+		Count:      len(req.Transactions),
+		Fields:     FieldsFromContext(ctx),
+		CreatedAt:  resp.CreatedAt,
+	}
+	p.Actor, _ = ActorFromContext(ctx)
+	var cents int64
+	for _, tx := range req.Transactions {
+		if amount, err := strconv.ParseFloat(tx.Amount, 64); err == nil {
+			cents += int64(math.Round(amount * 100))
+		}
+	}
+	p.Total = float64(cents) / 100
+	if s.client.approvalLink != "" {
+		p.Link = strings.ReplaceAll(s.client.approvalLink, "{tracking_id}", url.PathEscape(resp.TrackingID))
+	}
+	notify(ctx, p)
+}
@@ -0,0 +1,117 @@
+package intasend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BankPayBill is the M-Pesa PayBill number a bank accepts deposits on,
+// crediting the bank account given as the account reference.
+type BankPayBill string
+
+// PayBill numbers of Kenyan banks. Other banks' PayBill numbers can be
+// used as BankPayBill values directly.
+const (
+	BankPayBillAbsa              BankPayBill = "303030"
+	BankPayBillCoop              BankPayBill = "400200"
+	BankPayBillDTB               BankPayBill = "516600"
+	BankPayBillEquity            BankPayBill = "247247"
+	BankPayBillFamily            BankPayBill = "222111"
+	BankPayBillIM                BankPayBill = "542542"
+	BankPayBillKCB               BankPayBill = "522522"
+	BankPayBillNCBA              BankPayBill = "880100"
+	BankPayBillStanbic           BankPayBill = "600100"
+	BankPayBillStandardChartered BankPayBill = "329329"
+)
+
+// B2BBankTransaction is an M-Pesa B2B payment into a bank account through
+// the bank's PayBill.
+type B2BBankTransaction struct {
+	// Name is the account holder's name.
+	Name string
+
+	PayBill BankPayBill
+
+	// AccountNumber is the bank account to credit. Spaces and hyphens are
+	// removed before it is sent.
+	AccountNumber string
+
+	Amount    string
+	Narrative string
+}
+
+// MPesaB2BBankRequest is a request for M-Pesa B2B payouts into bank
+// accounts.
+type MPesaB2BBankRequest struct {
+	Currency         string
+	Transactions     []B2BBankTransaction
+	CallbackURL      string
+	WalletID         string
+	RequiresApproval ApprovalStatus
+}
+
+// MPesaB2BBank initiates M-Pesa B2B payouts into bank accounts, paying
+// each bank's PayBill with the account number as the account reference.
+// Unlike Bank, which uses PesaLink, the money moves over M-Pesa. PayBill
+// numbers must be 5 to 7 digits and account numbers 6 to 20 digits, or
+// the request is rejected with ErrInvalidBankAccount before it is sent.
+//
+// Example:
+//
+//	resp, err := client.Payout().MPesaB2BBank(ctx, &intasend.MPesaB2BBankRequest{
+//	    Currency: "KES",
+//	    Transactions: []intasend.B2BBankTransaction{
+//	        {
+//	            Name:          "Jane Wanjiku",
+//	            PayBill:       intasend.BankPayBillEquity,
+//	            AccountNumber: "0170 2998 12345",
+//	            Amount:        "15000",
+//	            Narrative:     "Supplier payment",
+//	        },
+//	    },
+//	})
+func (s *PayoutService) MPesaB2BBank(ctx context.Context, req *MPesaB2BBankRequest, reqOpts ...RequestOption) (*InitiateResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	transactions := make([]Transaction, len(req.Transactions))
+	for i, t := range req.Transactions {
+		account, err := normalizeBankAccount(t.PayBill, t.AccountNumber)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		transactions[i] = Transaction{
+			Name:             t.Name,
+			Account:          string(t.PayBill),
+			AccountType:      string(AccountTypePayBill),
+			AccountReference: account,
+			Amount:           t.Amount,
+			Narrative:        t.Narrative,
+		}
+	}
+
+	initReq := &InitiateRequest{
+		Provider:         ProviderMPesaB2B,
+		Currency:         req.Currency,
+		Transactions:     transactions,
+		CallbackURL:      req.CallbackURL,
+		WalletID:         req.WalletID,
+		RequiresApproval: req.RequiresApproval,
+	}
+	return s.Initiate(ctx, initReq)
+}
+
+// normalizeBankAccount checks a bank PayBill and account number, returning
+// the account number without spaces and hyphens.
+func normalizeBankAccount(payBill BankPayBill, account string) (string, error) {
+	if n := len(payBill); n < 5 || n > 7 || !isDigits(string(payBill)) {
+		return "", fmt.Errorf("%w: pay bill %q", ErrInvalidBankAccount, payBill)
+	}
+	normalized := strings.NewReplacer(" ", "", "-", "").Replace(account)
+	if n := len(normalized); n < 6 || n > 20 || !isDigits(normalized) {
+		return "", fmt.Errorf("%w: account number %q", ErrInvalidBankAccount, account)
+	}
+	return normalized, nil
+}
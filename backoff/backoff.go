@@ -0,0 +1,158 @@
+// Package backoff computes retry delays. The SDK uses it for its own
+// retries, and applications can use it for waits around their IntaSend
+// integration, such as waiting for a database write a webhook depends on,
+// so retry behaviour stays consistent.
+//
+//	err := backoff.Retry(ctx, backoff.Policy{Base: 200 * time.Millisecond, Max: 5 * time.Second, MaxAttempts: 6},
+//	    func(ctx context.Context) error {
+//	        order, err := orders.Get(ctx, invoice.APIRef)
+//	        if errors.Is(err, sql.ErrNoRows) {
+//	            return err // not written yet, retry
+//	        }
+//	        if err != nil {
+//	            return backoff.Permanent(err)
+//	        }
+//	        return order.MarkPaid(ctx)
+//	    })
+package backoff
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Exponential returns the wait before retry number attempt (starting at 1):
+// base doubled for each earlier retry, capped at max. A max of zero or less
+// means no cap.
+func Exponential(base, max time.Duration, attempt int) time.Duration {
+	if attempt < 1 || base <= 0 {
+		return 0
+	}
+	wait := base
+	for i := 1; i < attempt; i++ {
+		if max > 0 && wait >= max/2 {
+			return max
+		}
+		if wait > math.MaxInt64/2 {
+			return math.MaxInt64
+		}
+		wait *= 2
+	}
+	if max > 0 && wait > max {
+		return max
+	}
+	return wait
+}
+
+// Decorrelated produces randomized delays using "decorrelated jitter":
+// each delay is drawn between Base and three times the previous delay,
+// capped at Max. It spreads out retries from many clients that failed at
+// the same moment. The zero value is not usable; set Base. A Decorrelated
+// is not safe for concurrent use.
+type Decorrelated struct {
+	// Base is the smallest delay.
+	Base time.Duration
+
+	// Max caps delays. Zero or less means no cap.
+	Max time.Duration
+
+	prev time.Duration
+}
+
+// Next returns the next delay.
+func (d *Decorrelated) Next() time.Duration {
+	if d.Base <= 0 {
+		return 0
+	}
+	prev := d.prev
+	if prev < d.Base {
+		prev = d.Base
+	}
+	upper := prev * 3
+	if prev > math.MaxInt64/3 {
+		upper = math.MaxInt64
+	}
+	if d.Max > 0 && upper > d.Max {
+		upper = d.Max
+	}
+	next := d.Base
+	if upper > d.Base {
+		next += time.Duration(rand.Int63n(int64(upper - d.Base + 1))) // #nosec G404 -- jitter needs no cryptographic randomness
+	}
+	d.prev = next
+	return next
+}
+
+// Reset starts the sequence again from Base.
+func (d *Decorrelated) Reset() {
+	d.prev = 0
+}
+
+// Policy configures Retry.
+type Policy struct {
+	// Base is the wait before the first retry.
+	Base time.Duration
+
+	// Max caps each wait. Zero or less means no cap.
+	Max time.Duration
+
+	// MaxAttempts bounds the calls to fn, including the first. Zero or
+	// less means retry until ctx ends.
+	MaxAttempts int
+
+	// Jitter uses Decorrelated delays instead of Exponential ones.
+	Jitter bool
+}
+
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Retry returns it without retrying. A nil err
+// returns nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retry calls fn until it returns nil, returns an error wrapped with
+// Permanent, MaxAttempts is reached, or ctx ends, waiting between calls as
+// p describes. It returns fn's last error, unwrapped from Permanent, or
+// ctx's error joined with it.
+func Retry(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	jitter := &Decorrelated{Base: p.Base, Max: p.Max}
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return err
+		}
+
+		wait := Exponential(p.Base, p.Max, attempt)
+		if p.Jitter {
+			wait = jitter.Next()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package intasend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the default number of concurrent requests
+// issued by batch helpers.
+const DefaultBatchConcurrency = 8
+
+// BatchOptions configures batch helpers.
+type BatchOptions struct {
+	// Concurrency bounds the number of in-flight requests.
+	// Defaults to DefaultBatchConcurrency.
+	Concurrency int
+}
+
+// concurrency returns the effective concurrency limit.
+func (o *BatchOptions) concurrency() int {
+	if o == nil || o.Concurrency <= 0 {
+		return DefaultBatchConcurrency
+	}
+	return o.Concurrency
+}
+
+// BatchError reports the items of a batch call that failed.
+// Successful items are still returned alongside it.
+type BatchError struct {
+	// Total is the number of items in the batch.
+	Total int
+
+	// Errors maps each failed item key to its error.
+	Errors map[string]error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	keys := make([]string, 0, len(e.Errors))
+	for k := range e.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %v", k, e.Errors[k]))
+	}
+	return fmt.Sprintf("intasend: %d of %d batch items failed: %s", len(e.Errors), e.Total, strings.Join(parts, "; "))
+}
+
+// Unwrap returns the individual item errors.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// runBatch calls fn for each index below n with at most limit calls in
+// flight.
+func runBatch(n, limit int, fn func(i int)) {
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,71 @@
+package benchmarks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// staticTransport answers every request with the same body without touching
+// the network, isolating the client's own allocations in benchmarks.
+type staticTransport struct {
+	body []byte
+}
+
+func (t *staticTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Body != nil {
+		io.Copy(io.Discard, r.Body)
+		r.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(t.body)),
+		Request:    r,
+	}, nil
+}
+
+func newStaticClient(b *testing.B, body string) *intasend.Client {
+	b.Helper()
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL("http://intasend.invalid"),
+		intasend.WithHTTPClient(&http.Client{Transport: &staticTransport{body: []byte(body)}}),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		b.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func BenchmarkStatusAllocs(b *testing.B) {
+	client := newStaticClient(b, `{"invoice":{"invoice_id":"INV-456","state":"PROCESSING","provider":"M-PESA","value":500,"account":"254712345678","api_ref":"order-1"}}`)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Collection().Status(ctx, "INV-456", nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPayoutStatusAllocs(b *testing.B) {
+	client := newStaticClient(b, `{"tracking_id":"TRK-1","status":"Processing","transactions":[]}`)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Payout().Status(ctx, "TRK-1"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
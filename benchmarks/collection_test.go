@@ -0,0 +1,111 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func BenchmarkSTKPush(b *testing.B) {
+	server := newMockServer(b)
+	client := server.client(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{
+			PhoneNumber: "254712345678",
+			Amount:      100,
+			APIRef:      "bench",
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSTKPushParallel(b *testing.B) {
+	server := newMockServer(b)
+	client := server.client(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{
+				PhoneNumber: "254712345678",
+				Amount:      100,
+				APIRef:      "bench",
+			}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkStatusParallel(b *testing.B) {
+	server := newMockServer(b)
+	client := server.client(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.Collection().Status(ctx, "INV-1", nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// TestConcurrentPushAndStatus drives one shared client from many goroutines.
+// Run with -race to detect data races in shared client state.
+func TestConcurrentPushAndStatus(t *testing.T) {
+	server := newMockServer(t)
+	client := server.client(t)
+	ctx := context.Background()
+
+	const workers = 64
+	const perWorker = 10
+
+	var failures int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				push, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{
+					PhoneNumber: fmt.Sprintf("2547%08d", w),
+					Amount:      float64(i + 1),
+					APIRef:      fmt.Sprintf("order-%d-%d", w, i),
+				})
+				if err != nil {
+					atomic.AddInt64(&failures, 1)
+					continue
+				}
+				status, err := client.Collection().Status(ctx, push.Invoice.InvoiceID, nil)
+				if err != nil || status.Invoice.InvoiceID != push.Invoice.InvoiceID {
+					atomic.AddInt64(&failures, 1)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if failures != 0 {
+		t.Errorf("expected no failures, got %d", failures)
+	}
+	if got := atomic.LoadInt64(&server.pushes); got != workers*perWorker {
+		t.Errorf("expected %d pushes, got %d", workers*perWorker, got)
+	}
+	if got := atomic.LoadInt64(&server.statuses); got != workers*perWorker {
+		t.Errorf("expected %d status checks, got %d", workers*perWorker, got)
+	}
+}
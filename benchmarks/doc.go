@@ -0,0 +1,12 @@
+// Package benchmarks contains throughput benchmarks and concurrency stress
+// tests for the IntaSend client, run against an in-process mock server,
+// and allocation benchmarks that skip the network entirely.
+//
+// Run the benchmarks with:
+//
+//	go test ./benchmarks -run '^$' -bench . -benchmem
+//
+// Run the stress tests under the race detector with:
+//
+//	go test -race ./benchmarks
+package benchmarks
@@ -0,0 +1,81 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// mockServer emulates the collection endpoints of the IntaSend API.
+type mockServer struct {
+	*httptest.Server
+	invoices int64
+	pushes   int64
+	statuses int64
+}
+
+func newMockServer(tb testing.TB) *mockServer {
+	tb.Helper()
+	m := &mockServer{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/payment/mpesa-stk-push/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&m.pushes, 1)
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		id := atomic.AddInt64(&m.invoices, 1)
+		json.NewEncoder(w).Encode(intasend.STKPushResponse{
+			Invoice: &intasend.Invoice{
+				InvoiceID: fmt.Sprintf("INV-%d", id),
+				State:     intasend.StatePending,
+				Provider:  "M-PESA",
+				Value:     body["amount"].(float64),
+				Account:   body["phone_number"].(string),
+			},
+		})
+	})
+	mux.HandleFunc("/payment/status/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&m.statuses, 1)
+		var body struct {
+			InvoiceID string `json:"invoice_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(intasend.StatusResponse{
+			Invoice: &intasend.Invoice{InvoiceID: body.InvoiceID, State: intasend.StateComplete},
+		})
+	})
+
+	m.Server = httptest.NewServer(mux)
+	tb.Cleanup(m.Close)
+	return m
+}
+
+// client returns a client configured against the mock server.
+func (m *mockServer) client(tb testing.TB) *intasend.Client {
+	tb.Helper()
+	transport := m.Client().Transport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 256
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_bench"),
+		intasend.WithSecretKey("ISSecretKey_test_bench"),
+		intasend.WithBaseURL(m.URL),
+		intasend.WithHTTPClient(&http.Client{Transport: transport}),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		tb.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
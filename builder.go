@@ -0,0 +1,200 @@
+package intasend
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CheckoutBuilder builds a CreateCheckoutRequest, checking it when Build is
+// called instead of leaving the API to reject the request.
+// Each method returns the builder so calls can be chained.
+type CheckoutBuilder struct {
+	req CreateCheckoutRequest
+}
+
+// NewCheckout starts a checkout request for amount in currency.
+//
+// Example:
+//
+//	req, err := intasend.NewCheckout(1000, "KES").
+//	    WithCustomer(intasend.CheckoutCustomer{Email: "john@example.com"}).
+//	    WithRedirect("https://yoursite.com/callback").
+//	    WithAPIRef("order-123").
+//	    Build()
+//	if err != nil {
+//	    return err
+//	}
+//	session, err := client.Checkout().Create(ctx, req)
+func NewCheckout(amount float64, currency string) *CheckoutBuilder {
+	return &CheckoutBuilder{req: CreateCheckoutRequest{Amount: amount, Currency: currency}}
+}
+
+// WithCustomer sets the customer's details.
+func (b *CheckoutBuilder) WithCustomer(c CheckoutCustomer) *CheckoutBuilder {
+	b.req.Customer = c
+	return b
+}
+
+// WithHost sets the website base URL the checkout is opened from.
+func (b *CheckoutBuilder) WithHost(host string) *CheckoutBuilder {
+	b.req.Host = host
+	return b
+}
+
+// WithRedirect sets the URL the customer returns to after paying. If no
+// host is set, the redirect URL's scheme and host are used.
+func (b *CheckoutBuilder) WithRedirect(redirectURL string) *CheckoutBuilder {
+	b.req.RedirectURL = redirectURL
+	return b
+}
+
+// WithAPIRef sets your reference for the payment.
+func (b *CheckoutBuilder) WithAPIRef(ref string) *CheckoutBuilder {
+	b.req.APIRef = ref
+	return b
+}
+
+// WithComment sets a comment shown with the payment.
+func (b *CheckoutBuilder) WithComment(comment string) *CheckoutBuilder {
+	b.req.Comment = comment
+	return b
+}
+
+// WithMethods limits the checkout page to the given payment methods.
+func (b *CheckoutBuilder) WithMethods(methods ...PaymentMethod) *CheckoutBuilder {
+	b.req.Methods = methods
+	return b
+}
+
+// WithWallet settles the payment into a specific wallet.
+func (b *CheckoutBuilder) WithWallet(walletID string) *CheckoutBuilder {
+	b.req.WalletID = walletID
+	return b
+}
+
+// WithLocale sets the checkout page language.
+func (b *CheckoutBuilder) WithLocale(l Locale) *CheckoutBuilder {
+	b.req.Locale = l
+	return b
+}
+
+// Build returns the request, or a *ValidationError listing every invalid
+// field, including a missing host or customer contact. Invalid method
+// selections and locales are also reported here rather than by Create.
+func (b *CheckoutBuilder) Build() (*CreateCheckoutRequest, error) {
+	req := b.req
+	req.Methods = append([]PaymentMethod(nil), b.req.Methods...)
+	if req.Host == "" && req.RedirectURL != "" {
+		if u, err := url.Parse(req.RedirectURL); err == nil && u.Scheme != "" && u.Host != "" {
+			req.Host = u.Scheme + "://" + u.Host
+		}
+	}
+
+	if _, err := checkoutMethod(req.Method, req.Methods, req.Currency); err != nil {
+		return nil, err
+	}
+	if err := validateLocale(req.Locale); err != nil {
+		return nil, err
+	}
+	v := validator{request: "checkout"}
+	req.check(&v, req.Currency)
+	if strings.TrimSpace(req.Host) == "" {
+		v.add("host", "is required; set WithHost or WithRedirect", ErrIncompleteRequest)
+	}
+	if req.Customer.Email == "" && req.Customer.PhoneNumber == "" {
+		v.add("customer", "must have an email or phone number", ErrIncompleteRequest)
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// PayoutBuilder builds an InitiateRequest, checking provider-specific
+// required fields when Build is called. Each method returns the builder so
+// calls can be chained.
+type PayoutBuilder struct {
+	req InitiateRequest
+}
+
+// NewPayout starts a payout batch through provider in currency.
+//
+// Example:
+//
+//	req, err := intasend.NewPayout(intasend.ProviderPesaLink, "KES").
+//	    AddTransaction(intasend.Transaction{Name: "Jane", Account: "0123456789", BankCode: "2", Amount: "5000"}).
+//	    WithCallbackURL("https://yoursite.com/payouts").
+//	    Build()
+//	if err != nil {
+//	    return err
+//	}
+//	resp, err := client.Payout().Initiate(ctx, req)
+func NewPayout(provider Provider, currency string) *PayoutBuilder {
+	return &PayoutBuilder{req: InitiateRequest{Provider: provider, Currency: currency}}
+}
+
+// Add adds a transaction paying amount to account, formatting the amount
+// with FormatAmount. An invalid amount is kept as given and reported by
+// Build.
+func (b *PayoutBuilder) Add(account string, amount float64, narrative string) *PayoutBuilder {
+	txn, err := NewTransaction(account, amount, narrative)
+	if err != nil {
+		txn = Transaction{Account: account, Amount: strconv.FormatFloat(amount, 'f', -1, 64), Narrative: narrative}
+	}
+	return b.AddTransaction(txn)
+}
+
+// AddTransaction adds a fully specified transaction, such as a bank or
+// M-Pesa B2B transaction.
+func (b *PayoutBuilder) AddTransaction(t Transaction) *PayoutBuilder {
+	b.req.Transactions = append(b.req.Transactions, t)
+	return b
+}
+
+// WithCallbackURL sets the URL that receives payout status updates.
+func (b *PayoutBuilder) WithCallbackURL(callbackURL string) *PayoutBuilder {
+	b.req.CallbackURL = callbackURL
+	return b
+}
+
+// WithWallet pays out from a specific wallet.
+func (b *PayoutBuilder) WithWallet(walletID string) *PayoutBuilder {
+	b.req.WalletID = walletID
+	return b
+}
+
+// RequireApproval sets whether the batch waits for approval.
+func (b *PayoutBuilder) RequireApproval(required bool) *PayoutBuilder {
+	b.req.RequiresApproval = ApprovalNotRequired
+	if required {
+		b.req.RequiresApproval = ApprovalRequired
+	}
+	return b
+}
+
+// Build returns the request, or a *ValidationError listing every invalid
+// field, including bank codes for PesaLink and account types for M-Pesa
+// B2B.
+func (b *PayoutBuilder) Build() (*InitiateRequest, error) {
+	req := b.req
+	req.Transactions = append([]Transaction(nil), b.req.Transactions...)
+
+	v := validator{request: "payout"}
+	v.required("provider", string(req.Provider))
+	req.check(&v)
+	for i, t := range req.Transactions {
+		prefix := fmt.Sprintf("transactions[%d].", i)
+		switch req.Provider {
+		case ProviderPesaLink:
+			v.required(prefix+"bank_code", t.BankCode)
+		case ProviderMPesaB2B:
+			v.required(prefix+"account_type", t.AccountType)
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
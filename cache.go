@@ -0,0 +1,176 @@
+package intasend
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults for PaymentLink().GetCached.
+const (
+	// DefaultPaymentLinkCacheTTL is how long a cached payment link is
+	// served without contacting the API.
+	DefaultPaymentLinkCacheTTL = time.Minute
+
+	// DefaultPaymentLinkCacheMaxStale is how long after the TTL a stale
+	// link is still served while it is refreshed in the background.
+	DefaultPaymentLinkCacheMaxStale = 5 * time.Minute
+)
+
+// linkCacheMaxEntries is the number of links above which the cache drops
+// expired entries, and then the oldest, to make room.
+const linkCacheMaxEntries = 1024
+
+// linkCache is a read-through payment link cache with
+// stale-while-revalidate semantics. Concurrent misses for the same link
+// share one API request. Links are cached per environment and keys, so a
+// ContextWithEnvironment caller never sees another account's link.
+type linkCache struct {
+	ttl      time.Duration
+	maxStale time.Duration
+
+	mu       sync.Mutex
+	entries  map[linkKey]linkEntry
+	inflight map[linkKey]*linkFetch
+}
+
+// linkKey identifies a cached link: its ID and the environment override,
+// if any, it was fetched with.
+type linkKey struct {
+	scope string
+	id    string
+}
+
+type linkEntry struct {
+	link    PaymentLink
+	fetched time.Time
+}
+
+// linkFetch is an in-flight API request shared by concurrent callers.
+type linkFetch struct {
+	done chan struct{}
+	link *PaymentLink
+	err  error
+}
+
+func newLinkCache(ttl, maxStale time.Duration) *linkCache {
+	return &linkCache{
+		ttl:      ttl,
+		maxStale: maxStale,
+		entries:  make(map[linkKey]linkEntry),
+		inflight: make(map[linkKey]*linkFetch),
+	}
+}
+
+// get returns the cached link for id, fetching it with fetch when missing
+// or too stale, and refreshing it in the background when merely stale.
+func (lc *linkCache) get(ctx context.Context, id string, fetch func(context.Context, string) (*PaymentLink, error)) (*PaymentLink, error) {
+	key := linkKey{id: id}
+	if ov := environmentFromContext(ctx); ov != nil {
+		key.scope = string(ov.env) + "\x00" + ov.publishableKey + "\x00" + ov.secretKey
+	}
+
+	lc.mu.Lock()
+	if e, ok := lc.entries[key]; ok {
+		age := time.Since(e.fetched)
+		if age < lc.ttl+lc.maxStale {
+			if age >= lc.ttl {
+				lc.start(ctx, key, fetch)
+			}
+			lc.mu.Unlock()
+			link := e.link
+			return &link, nil
+		}
+	}
+	f := lc.start(ctx, key, fetch)
+	lc.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-f.done:
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	link := *f.link
+	return &link, nil
+}
+
+// start begins fetching key unless a fetch is already in flight. The fetch
+// keeps the values of ctx, such as its environment and request options,
+// but not its cancellation, so that one caller giving up does not fail the
+// others; the request is still bounded by the request timeout. lc.mu must
+// be held.
+func (lc *linkCache) start(ctx context.Context, key linkKey, fetch func(context.Context, string) (*PaymentLink, error)) *linkFetch {
+	if f, ok := lc.inflight[key]; ok {
+		return f
+	}
+	f := &linkFetch{done: make(chan struct{})}
+	lc.inflight[key] = f
+
+	go func() {
+		f.link, f.err = fetch(detachedContext{ctx}, key.id)
+
+		lc.mu.Lock()
+		switch {
+		case f.err == nil:
+			lc.store(key, *f.link)
+		case isNotFound(f.err):
+			delete(lc.entries, key)
+		}
+		delete(lc.inflight, key)
+		lc.mu.Unlock()
+		close(f.done)
+	}()
+	return f
+}
+
+// store caches link, first dropping expired entries, and then the oldest,
+// once the cache holds linkCacheMaxEntries. lc.mu must be held.
+func (lc *linkCache) store(key linkKey, link PaymentLink) {
+	if _, ok := lc.entries[key]; !ok && len(lc.entries) >= linkCacheMaxEntries {
+		var oldest linkKey
+		var oldestAt time.Time
+		for k, e := range lc.entries {
+			if time.Since(e.fetched) >= lc.ttl+lc.maxStale {
+				delete(lc.entries, k)
+				continue
+			}
+			if oldestAt.IsZero() || e.fetched.Before(oldestAt) {
+				oldest, oldestAt = k, e.fetched
+			}
+		}
+		if len(lc.entries) >= linkCacheMaxEntries {
+			delete(lc.entries, oldest)
+		}
+	}
+	lc.entries[key] = linkEntry{link: link, fetched: time.Now()}
+}
+
+// invalidate drops id from the cache in every environment.
+func (lc *linkCache) invalidate(id string) {
+	lc.mu.Lock()
+	for k := range lc.entries {
+		if k.id == id {
+			delete(lc.entries, k)
+		}
+	}
+	lc.mu.Unlock()
+}
+
+// detachedContext carries the values of a context without its deadline or
+// cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// isNotFound reports whether err is an API 404.
+func isNotFound(err error) bool {
+	apiErr := AsAPIError(err)
+	return apiErr != nil && apiErr.IsNotFound()
+}
@@ -2,11 +2,89 @@ package intasend
 
 import (
 	"context"
+	"fmt"
+	"strings"
 )
 
 // CheckoutService handles checkout operations.
 type CheckoutService struct {
 	client *Client
+
+	// sessions holds CreateAndWait calls waiting for Notify.
+	sessions sessionWaiters
+}
+
+// PaymentMethod is a payment channel: an option offered on the hosted
+// checkout page, or the channel an invoice was paid through.
+type PaymentMethod string
+
+const (
+	// PaymentMethodMPesa offers M-Pesa mobile money (KES only).
+	PaymentMethodMPesa PaymentMethod = "M-PESA"
+
+	// PaymentMethodCard offers card payments.
+	PaymentMethodCard PaymentMethod = "CARD-PAYMENT"
+
+	// PaymentMethodBankACH offers bank (ACH) payments.
+	PaymentMethodBankACH PaymentMethod = "BANK-ACH"
+
+	// PaymentMethodBitcoin offers Bitcoin payments.
+	PaymentMethodBitcoin PaymentMethod = "BITCOIN"
+
+	// PaymentMethodIntaSend is a payment from an IntaSend wallet. It is
+	// reported on invoices but cannot be offered on the checkout page.
+	PaymentMethodIntaSend PaymentMethod = "INTASEND"
+)
+
+// checkoutMethod validates a method selection and encodes it into the
+// checkout "method" field. Multiple methods are sent comma-separated.
+func checkoutMethod(method string, methods []PaymentMethod, currency string) (string, error) {
+	if len(methods) == 0 {
+		return method, nil
+	}
+	if method != "" {
+		return "", fmt.Errorf("%w: set either Method or Methods, not both", ErrInvalidPaymentMethods)
+	}
+
+	seen := make(map[PaymentMethod]bool, len(methods))
+	parts := make([]string, 0, len(methods))
+	for _, m := range methods {
+		switch m {
+		case PaymentMethodMPesa, PaymentMethodCard, PaymentMethodBankACH, PaymentMethodBitcoin:
+		default:
+			return "", fmt.Errorf("%w: unknown method %q", ErrInvalidPaymentMethods, m)
+		}
+		if seen[m] {
+			return "", fmt.Errorf("%w: duplicate method %q", ErrInvalidPaymentMethods, m)
+		}
+		if m == PaymentMethodMPesa && currency != "" && !strings.EqualFold(currency, "KES") {
+			return "", fmt.Errorf("%w: %s requires KES, got %s", ErrInvalidPaymentMethods, m, currency)
+		}
+		seen[m] = true
+		parts = append(parts, string(m))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Locale is a language hint for IntaSend's hosted payment pages.
+type Locale string
+
+const (
+	// LocaleEnglish renders hosted pages in English.
+	LocaleEnglish Locale = "en"
+
+	// LocaleSwahili renders hosted pages in Swahili.
+	LocaleSwahili Locale = "sw"
+)
+
+// validateLocale returns ErrUnsupportedLocale for locales the hosted pages
+// cannot render. An empty locale uses the account default.
+func validateLocale(l Locale) error {
+	switch l {
+	case "", LocaleEnglish, LocaleSwahili:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnsupportedLocale, l)
 }
 
 // CheckoutCustomer represents customer information for checkout.
@@ -23,42 +101,49 @@ type CheckoutCustomer struct {
 }
 
 // CreateCheckoutRequest represents a request to create a checkout session.
+// Methods limits the checkout page to several payment methods and cannot be
+// combined with Method. Locale selects the checkout page language.
+// AmountDecimal, if set, is sent instead of Amount, exactly as given.
 type CreateCheckoutRequest struct {
-	Amount       float64
-	Currency     string
-	Customer     CheckoutCustomer
-	Host         string
-	RedirectURL  string
-	APIRef       string
-	Comment      string
-	Method       string
-	CardTariff   string
-	MobileTariff string
-	WalletID     string
+	Amount        float64
+	AmountDecimal Amount
+	Currency      string
+	Customer      CheckoutCustomer
+	Host          string
+	RedirectURL   string
+	APIRef        string
+	Comment       string
+	Method        string
+	Methods       []PaymentMethod
+	CardTariff    string
+	MobileTariff  string
+	WalletID      string
+	Locale        Locale
 }
 
 // createCheckoutBody is the internal request body.
 type createCheckoutBody struct {
-	PublicKey    string  `json:"public_key,omitempty"`
-	Amount       float64 `json:"amount"`
-	Currency     string  `json:"currency"`
-	Email        string  `json:"email"`
-	FirstName    string  `json:"first_name,omitempty"`
-	LastName     string  `json:"last_name,omitempty"`
-	PhoneNumber  string  `json:"phone_number,omitempty"`
-	Country      string  `json:"country,omitempty"`
-	Address      string  `json:"address,omitempty"`
-	City         string  `json:"city,omitempty"`
-	State        string  `json:"state,omitempty"`
-	Zipcode      string  `json:"zipcode,omitempty"`
-	Host         string  `json:"host"`
-	RedirectURL  string  `json:"redirect_url,omitempty"`
-	APIRef       string  `json:"api_ref,omitempty"`
-	Comment      string  `json:"comment,omitempty"`
-	Method       string  `json:"method,omitempty"`
-	CardTariff   string  `json:"card_tarrif,omitempty"`
-	MobileTariff string  `json:"mobile_tarrif,omitempty"`
-	WalletID     string  `json:"wallet_id,omitempty"`
+	PublicKey    string `json:"public_key,omitempty"`
+	Amount       Amount `json:"amount"`
+	Currency     string `json:"currency"`
+	Email        string `json:"email"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	PhoneNumber  string `json:"phone_number,omitempty"`
+	Country      string `json:"country,omitempty"`
+	Address      string `json:"address,omitempty"`
+	City         string `json:"city,omitempty"`
+	State        string `json:"state,omitempty"`
+	Zipcode      string `json:"zipcode,omitempty"`
+	Host         string `json:"host"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+	APIRef       string `json:"api_ref,omitempty"`
+	Comment      string `json:"comment,omitempty"`
+	Method       string `json:"method,omitempty"`
+	CardTariff   string `json:"card_tarrif,omitempty"`
+	MobileTariff string `json:"mobile_tarrif,omitempty"`
+	WalletID     string `json:"wallet_id,omitempty"`
+	Locale       Locale `json:"locale,omitempty"`
 }
 
 // CreateCheckoutResponse represents the response from creating a checkout.
@@ -97,12 +182,29 @@ type CheckoutStatusResponse struct {
 //	    Host:        "https://yoursite.com",
 //	    RedirectURL: "https://yoursite.com/callback",
 //	    APIRef:      "order-123",
+//	    Methods:     []intasend.PaymentMethod{intasend.PaymentMethodMPesa, intasend.PaymentMethodCard},
 //	})
-func (s *CheckoutService) Create(ctx context.Context, req *CreateCheckoutRequest) (*CreateCheckoutResponse, error) {
+func (s *CheckoutService) Create(ctx context.Context, req *CreateCheckoutRequest, reqOpts ...RequestOption) (*CreateCheckoutResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	currency := s.client.currency(req.Currency)
+	method, err := checkoutMethod(req.Method, req.Methods, currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateLocale(req.Locale); err != nil {
+		return nil, err
+	}
+	if err := req.validate(currency); err != nil {
+		return nil, err
+	}
+
 	body := &createCheckoutBody{
-		PublicKey:    s.client.publishableKey,
-		Amount:       req.Amount,
-		Currency:     req.Currency,
+		PublicKey:    s.client.publicKey(ctx),
+		Amount:       requestAmount(req.AmountDecimal, req.Amount),
+		Currency:     currency,
 		Email:        req.Customer.Email,
 		FirstName:    req.Customer.FirstName,
 		LastName:     req.Customer.LastName,
@@ -116,10 +218,11 @@ func (s *CheckoutService) Create(ctx context.Context, req *CreateCheckoutRequest
 		RedirectURL:  req.RedirectURL,
 		APIRef:       req.APIRef,
 		Comment:      req.Comment,
-		Method:       req.Method,
+		Method:       method,
 		CardTariff:   req.CardTariff,
 		MobileTariff: req.MobileTariff,
 		WalletID:     req.WalletID,
+		Locale:       req.Locale,
 	}
 
 	var resp CreateCheckoutResponse
@@ -138,7 +241,11 @@ func (s *CheckoutService) Create(ctx context.Context, req *CreateCheckoutRequest
 //	    CheckoutID: "CHK-123",
 //	    InvoiceID:  "INV-456",
 //	})
-func (s *CheckoutService) CheckStatus(ctx context.Context, req *CheckoutStatusRequest) (*CheckoutStatusResponse, error) {
+func (s *CheckoutService) CheckStatus(ctx context.Context, req *CheckoutStatusRequest, reqOpts ...RequestOption) (*CheckoutStatusResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
 	var resp CheckoutStatusResponse
 	if err := s.client.postPublic(ctx, "/payment/status/", req, &resp); err != nil {
 		return nil, err
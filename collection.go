@@ -2,6 +2,11 @@ package intasend
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -30,6 +35,9 @@ type ChargeRequest struct {
 	// Amount is the payment amount.
 	Amount float64 `json:"amount"`
 
+	// AmountDecimal, if set, is sent instead of Amount, exactly as given.
+	AmountDecimal Amount `json:"-"`
+
 	// Currency is the payment currency (e.g., "KES", "USD").
 	Currency string `json:"currency"`
 
@@ -45,6 +53,13 @@ type ChargeRequest struct {
 	// Method limits the payment to a specific method.
 	Method string `json:"method,omitempty"`
 
+	// Methods limits the checkout page to the given payment methods.
+	// It cannot be combined with Method.
+	Methods []PaymentMethod `json:"-"`
+
+	// Locale selects the checkout page language.
+	Locale Locale `json:"locale,omitempty"`
+
 	// WalletID directs the payment to a specific wallet.
 	WalletID string `json:"wallet_id,omitempty"`
 
@@ -64,26 +79,27 @@ type ChargeRequest struct {
 
 // chargeRequestBody is the internal request body with public_key.
 type chargeRequestBody struct {
-	PublicKey    string  `json:"public_key,omitempty"`
-	FirstName    string  `json:"first_name,omitempty"`
-	LastName     string  `json:"last_name,omitempty"`
-	Email        string  `json:"email"`
-	PhoneNumber  string  `json:"phone_number,omitempty"`
-	Host         string  `json:"host"`
-	Amount       float64 `json:"amount"`
-	Currency     string  `json:"currency"`
-	APIRef       string  `json:"api_ref,omitempty"`
-	RedirectURL  string  `json:"redirect_url,omitempty"`
-	Comment      string  `json:"comment,omitempty"`
-	Method       string  `json:"method,omitempty"`
-	WalletID     string  `json:"wallet_id,omitempty"`
-	CardTariff   string  `json:"card_tarrif,omitempty"`
-	MobileTariff string  `json:"mobile_tarrif,omitempty"`
-	Country      string  `json:"country,omitempty"`
-	Address      string  `json:"address,omitempty"`
-	City         string  `json:"city,omitempty"`
-	State        string  `json:"state,omitempty"`
-	Zipcode      string  `json:"zipcode,omitempty"`
+	PublicKey    string `json:"public_key,omitempty"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	Email        string `json:"email"`
+	PhoneNumber  string `json:"phone_number,omitempty"`
+	Host         string `json:"host"`
+	Amount       Amount `json:"amount"`
+	Currency     string `json:"currency"`
+	APIRef       string `json:"api_ref,omitempty"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+	Comment      string `json:"comment,omitempty"`
+	Method       string `json:"method,omitempty"`
+	WalletID     string `json:"wallet_id,omitempty"`
+	CardTariff   string `json:"card_tarrif,omitempty"`
+	MobileTariff string `json:"mobile_tarrif,omitempty"`
+	Country      string `json:"country,omitempty"`
+	Address      string `json:"address,omitempty"`
+	City         string `json:"city,omitempty"`
+	State        string `json:"state,omitempty"`
+	Zipcode      string `json:"zipcode,omitempty"`
+	Locale       Locale `json:"locale,omitempty"`
 }
 
 // ChargeResponse represents the response from creating a checkout.
@@ -106,6 +122,9 @@ type STKPushRequest struct {
 	// Amount is the payment amount in KES.
 	Amount float64 `json:"amount"`
 
+	// AmountDecimal, if set, is sent instead of Amount, exactly as given.
+	AmountDecimal Amount `json:"-"`
+
 	// APIRef is your unique reference for this transaction.
 	APIRef string `json:"api_ref,omitempty"`
 
@@ -117,19 +136,25 @@ type STKPushRequest struct {
 
 	// WalletID directs the payment to a specific wallet.
 	WalletID string `json:"wallet_id,omitempty"`
+
+	// Narrative describes the payment, such as "ACME Order 1234". It is
+	// sent to M-Pesa with the prompt so customers can tell what they are
+	// paying for.
+	Narrative string `json:"narrative,omitempty"`
 }
 
 // stkPushRequestBody is the internal request body.
 type stkPushRequestBody struct {
-	PublicKey   string  `json:"public_key,omitempty"`
-	PhoneNumber string  `json:"phone_number"`
-	Amount      float64 `json:"amount"`
-	APIRef      string  `json:"api_ref,omitempty"`
-	Name        string  `json:"name,omitempty"`
-	Email       string  `json:"email,omitempty"`
-	WalletID    string  `json:"wallet_id,omitempty"`
-	Method      string  `json:"method"`
-	Currency    string  `json:"currency"`
+	PublicKey   string `json:"public_key,omitempty"`
+	PhoneNumber string `json:"phone_number"`
+	Amount      Amount `json:"amount"`
+	APIRef      string `json:"api_ref,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	WalletID    string `json:"wallet_id,omitempty"`
+	Narrative   string `json:"narrative,omitempty"`
+	Method      string `json:"method"`
+	Currency    string `json:"currency"`
 }
 
 // STKPushResponse represents the response from an STK Push request.
@@ -152,6 +177,163 @@ type Invoice struct {
 	FailedReason string    `json:"failed_reason,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Currency is the invoice currency, such as "KES".
+	Currency string `json:"currency,omitempty"`
+
+	// Charges are the transaction fees IntaSend applied.
+	Charges float64 `json:"charges,omitempty"`
+
+	// NetAmount is the amount credited to the wallet after charges.
+	NetAmount float64 `json:"net_amount,omitempty"`
+
+	// MPesaReference is the M-Pesa receipt code, such as "QK71ABC2DE", for
+	// paid M-Pesa invoices.
+	MPesaReference string `json:"mpesa_reference,omitempty"`
+}
+
+// UnmarshalJSON decodes an invoice, accepting amounts sent as strings
+// ("100.00") as well as numbers.
+func (i *Invoice) UnmarshalJSON(data []byte) error {
+	type plain Invoice
+	aux := struct {
+		*plain
+		Value     Amount `json:"value"`
+		Charges   Amount `json:"charges"`
+		NetAmount Amount `json:"net_amount"`
+	}{
+		plain:     (*plain)(i),
+		Value:     AmountFromFloat(i.Value),
+		Charges:   AmountFromFloat(i.Charges),
+		NetAmount: AmountFromFloat(i.NetAmount),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	i.Value, i.Charges, i.NetAmount = aux.Value.Float64(), aux.Charges.Float64(), aux.NetAmount.Float64()
+	return nil
+}
+
+// ValueDecimal returns Value as an exact decimal.
+func (i *Invoice) ValueDecimal() Amount { return AmountFromFloat(i.Value) }
+
+// ChargesDecimal returns Charges as an exact decimal.
+func (i *Invoice) ChargesDecimal() Amount { return AmountFromFloat(i.Charges) }
+
+// NetAmountDecimal returns NetAmount as an exact decimal.
+func (i *Invoice) NetAmountDecimal() Amount { return AmountFromFloat(i.NetAmount) }
+
+// paymentMethodAliases maps normalized API variants to their method.
+var paymentMethodAliases = map[string]PaymentMethod{
+	"M-PESA":       PaymentMethodMPesa,
+	"MPESA":        PaymentMethodMPesa,
+	"MPESA-STK":    PaymentMethodMPesa,
+	"M-PESA-STK":   PaymentMethodMPesa,
+	"CARD-PAYMENT": PaymentMethodCard,
+	"CARD":         PaymentMethodCard,
+	"CARDS":        PaymentMethodCard,
+	"BANK-ACH":     PaymentMethodBankACH,
+	"BANK":         PaymentMethodBankACH,
+	"ACH":          PaymentMethodBankACH,
+	"BITCOIN":      PaymentMethodBitcoin,
+	"BTC":          PaymentMethodBitcoin,
+	"INTASEND":     PaymentMethodIntaSend,
+	"WALLET":       PaymentMethodIntaSend,
+}
+
+// ParsePaymentMethod normalizes a provider string as returned by the API
+// ("M-PESA", "mpesa", "Card Payment", ...) to a PaymentMethod. Unknown
+// values are returned upper-cased with spaces and underscores replaced by
+// hyphens, so they still group consistently.
+func ParsePaymentMethod(s string) PaymentMethod {
+	norm := strings.ToUpper(strings.TrimSpace(s))
+	norm = strings.NewReplacer(" ", "-", "_", "-").Replace(norm)
+	if m, ok := paymentMethodAliases[norm]; ok {
+		return m
+	}
+	return PaymentMethod(norm)
+}
+
+// ProviderType returns the channel the invoice was paid through as a
+// normalized PaymentMethod.
+func (i *Invoice) ProviderType() PaymentMethod {
+	return ParsePaymentMethod(i.Provider)
+}
+
+// FailureReason classifies an invoice's FailedReason text.
+type FailureReason string
+
+const (
+	// FailureReasonUserCancelled means the customer dismissed the prompt.
+	FailureReasonUserCancelled FailureReason = "USER_CANCELLED"
+
+	// FailureReasonTimeout means the customer did not respond before the
+	// prompt expired.
+	FailureReasonTimeout FailureReason = "TIMEOUT"
+
+	// FailureReasonInsufficientFunds means the customer's balance could not
+	// cover the payment.
+	FailureReasonInsufficientFunds FailureReason = "INSUFFICIENT_FUNDS"
+
+	// FailureReasonInvalidPIN means the customer entered a wrong PIN.
+	FailureReasonInvalidPIN FailureReason = "INVALID_PIN"
+
+	// FailureReasonUnknown is any reason not recognized above.
+	FailureReasonUnknown FailureReason = "UNKNOWN"
+)
+
+// failureReasonPatterns maps lowercase fragments of provider messages to
+// reasons, checked in order.
+var failureReasonPatterns = []struct {
+	fragment string
+	reason   FailureReason
+}{
+	{"cancelled by user", FailureReasonUserCancelled},
+	{"canceled by user", FailureReasonUserCancelled},
+	{"request cancelled", FailureReasonUserCancelled},
+	{"timeout", FailureReasonTimeout},
+	{"timed out", FailureReasonTimeout},
+	{"no response from user", FailureReasonTimeout},
+	{"insufficient", FailureReasonInsufficientFunds},
+	{"initiator information is invalid", FailureReasonInvalidPIN},
+	{"invalid pin", FailureReasonInvalidPIN},
+	{"wrong pin", FailureReasonInvalidPIN},
+}
+
+// ParseFailureReason classifies a provider failure message such as
+// "Request cancelled by user" or "DS timeout". It returns an empty reason
+// for an empty message and FailureReasonUnknown for unrecognized ones.
+func ParseFailureReason(s string) FailureReason {
+	msg := strings.ToLower(strings.TrimSpace(s))
+	if msg == "" {
+		return ""
+	}
+	for _, p := range failureReasonPatterns {
+		if strings.Contains(msg, p.fragment) {
+			return p.reason
+		}
+	}
+	return FailureReasonUnknown
+}
+
+// IsUserCancelled reports whether the customer dismissed the prompt.
+func (r FailureReason) IsUserCancelled() bool { return r == FailureReasonUserCancelled }
+
+// IsTimeout reports whether the prompt expired without a response.
+func (r FailureReason) IsTimeout() bool { return r == FailureReasonTimeout }
+
+// IsInsufficientFunds reports whether the customer lacked funds.
+func (r FailureReason) IsInsufficientFunds() bool { return r == FailureReasonInsufficientFunds }
+
+// FailureReason classifies FailedReason.
+//
+// Example:
+//
+//	if status.Invoice.FailureReason().IsInsufficientFunds() {
+//	    // suggest a smaller amount or another payment method
+//	}
+func (i *Invoice) FailureReason() FailureReason {
+	return ParseFailureReason(i.FailedReason)
 }
 
 // CustomerInfo represents a customer record.
@@ -206,20 +388,36 @@ const (
 //	    Currency:  "KES",
 //	    APIRef:    "order-123",
 //	})
-func (s *CollectionService) Charge(ctx context.Context, req *ChargeRequest) (*ChargeResponse, error) {
+func (s *CollectionService) Charge(ctx context.Context, req *ChargeRequest, reqOpts ...RequestOption) (*ChargeResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	currency := s.client.currency(req.Currency)
+	if err := req.validate(currency); err != nil {
+		return nil, err
+	}
+	method, err := checkoutMethod(req.Method, req.Methods, currency)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateLocale(req.Locale); err != nil {
+		return nil, err
+	}
+
 	body := &chargeRequestBody{
-		PublicKey:    s.client.publishableKey,
+		PublicKey:    s.client.publicKey(ctx),
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
 		Email:        req.Email,
 		PhoneNumber:  req.PhoneNumber,
 		Host:         req.Host,
-		Amount:       req.Amount,
-		Currency:     req.Currency,
+		Amount:       requestAmount(req.AmountDecimal, req.Amount),
+		Currency:     currency,
 		APIRef:       req.APIRef,
 		RedirectURL:  req.RedirectURL,
 		Comment:      req.Comment,
-		Method:       req.Method,
+		Method:       method,
 		WalletID:     req.WalletID,
 		CardTariff:   req.CardTariff,
 		MobileTariff: req.MobileTariff,
@@ -228,6 +426,7 @@ func (s *CollectionService) Charge(ctx context.Context, req *ChargeRequest) (*Ch
 		City:         req.City,
 		State:        req.State,
 		Zipcode:      req.Zipcode,
+		Locale:       req.Locale,
 	}
 
 	var resp ChargeResponse
@@ -248,37 +447,255 @@ func (s *CollectionService) Charge(ctx context.Context, req *ChargeRequest) (*Ch
 //	    APIRef:      "order-123",
 //	    Name:        "John Doe",
 //	    Email:       "john@example.com",
+//	    Narrative:   "ACME Order 1234",
 //	})
-func (s *CollectionService) MPesaSTKPush(ctx context.Context, req *STKPushRequest) (*STKPushResponse, error) {
+func (s *CollectionService) MPesaSTKPush(ctx context.Context, req *STKPushRequest, reqOpts ...RequestOption) (*STKPushResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
 	body := &stkPushRequestBody{
-		PublicKey:   s.client.publishableKey,
+		PublicKey:   s.client.publicKey(ctx),
 		PhoneNumber: req.PhoneNumber,
-		Amount:      req.Amount,
+		Amount:      requestAmount(req.AmountDecimal, req.Amount),
 		APIRef:      req.APIRef,
 		Name:        req.Name,
 		Email:       req.Email,
 		WalletID:    req.WalletID,
+		Narrative:   req.Narrative,
 		Method:      "M-PESA",
 		Currency:    "KES",
 	}
 
 	var resp STKPushResponse
-	if err := s.client.post(ctx, "/payment/mpesa-stk-push/", body, &resp); err != nil {
+	err := s.client.throttled([]string{req.PhoneNumber}, func() error {
+		return s.client.post(ctx, "/payment/mpesa-stk-push/", body, &resp)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
+// STKOutcome is the terminal result of waiting on an STK push.
+type STKOutcome string
+
+const (
+	// STKOutcomeComplete means the customer paid.
+	STKOutcomeComplete STKOutcome = "COMPLETE"
+
+	// STKOutcomeFailed means the payment failed, for example because the
+	// customer cancelled the prompt or had insufficient funds.
+	STKOutcomeFailed STKOutcome = "FAILED"
+
+	// STKOutcomePromptExpired means the prompt expired on the handset
+	// without a response. Offering to resend the prompt is usually the
+	// right next step.
+	STKOutcomePromptExpired STKOutcome = "PROMPT_EXPIRED"
+)
+
+// STKPushResult is returned by MPesaSTKPushAndWait.
+type STKPushResult struct {
+	// Outcome is the terminal result. It is empty when waiting stopped
+	// early with an error.
+	Outcome STKOutcome
+
+	// Push is the response to the initial STK push.
+	Push *STKPushResponse
+
+	// Status is the last status observed, if any.
+	Status *StatusResponse
+}
+
+// PromptExpired reports whether the prompt expired without a response.
+func (r *STKPushResult) PromptExpired() bool {
+	return r != nil && r.Outcome == STKOutcomePromptExpired
+}
+
+// MPesaSTKPushAndWait sends an STK push and polls its invoice until it
+// completes, fails, or the prompt's expiry window passes. An expired prompt,
+// whether still pending at the deadline or failed with a timeout reason, is
+// reported as STKOutcomePromptExpired rather than an error. The timeout
+// defaults to DefaultSTKPromptExpiry.
+//
+// Example:
+//
+//	result, err := client.Collection().MPesaSTKPushAndWait(ctx, &intasend.STKPushRequest{
+//	    PhoneNumber: "254712345678",
+//	    Amount:      100,
+//	}, nil)
+//	if err == nil && result.PromptExpired() {
+//	    // offer to resend the prompt
+//	}
+func (s *CollectionService) MPesaSTKPushAndWait(ctx context.Context, req *STKPushRequest, opts *PollOptions, reqOpts ...RequestOption) (*STKPushResult, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	push, err := s.MPesaSTKPush(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &STKPushResult{Push: push}
+	if push.Invoice == nil || push.Invoice.InvoiceID == "" {
+		return result, fmt.Errorf("intasend: STK push response has no invoice")
+	}
+
+	deadline := time.Now().Add(opts.timeout(DefaultSTKPromptExpiry))
+	state := push.Invoice.State
+	var header http.Header
+	pollCtx := withResponseHeader(ctx, &header)
+	timer := time.NewTimer(opts.next(state, 0))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-timer.C:
+		}
+
+		header = nil
+		status, err := s.Status(pollCtx, push.Invoice.InvoiceID, nil)
+		if err != nil {
+			return result, err
+		}
+		result.Status = status
+		if status.Invoice != nil {
+			state = status.Invoice.State
+			switch state {
+			case StateComplete:
+				result.Outcome = STKOutcomeComplete
+				return result, nil
+			case StateFailed:
+				result.Outcome = STKOutcomeFailed
+				if status.Invoice.FailureReason().IsTimeout() {
+					result.Outcome = STKOutcomePromptExpired
+				}
+				return result, nil
+			}
+		}
+		now := time.Now()
+		if !now.Before(deadline) {
+			result.Outcome = STKOutcomePromptExpired
+			return result, nil
+		}
+
+		wait := opts.next(state, parseRetryAfter(header.Get("Retry-After"), now))
+		if left := deadline.Sub(now); wait > left {
+			wait = left
+		}
+		timer.Reset(wait)
+	}
+}
+
+// ResendSTKPushResponse is returned by ResendSTKPush.
+type ResendSTKPushResponse struct {
+	// Previous is the invoice that was superseded.
+	Previous *Invoice
+
+	// STKPushResponse is the response to the new push.
+	*STKPushResponse
+}
+
+// ResendSTKPush sends a fresh STK prompt for an unpaid M-Pesa invoice, most
+// often because the customer never received the first one.
+//
+// IntaSend cannot re-trigger the prompt of an existing invoice, so a new
+// invoice is created for the same phone number, amount, and API reference,
+// superseding the previous one. To avoid charging the customer twice, the
+// previous invoice must have failed or its prompt must have expired;
+// otherwise ErrNotResendable is returned. The previous invoice's wallet is
+// not known to the status endpoint, so payments to a specific wallet should
+// be re-sent with MPesaSTKPush instead.
+//
+// Example:
+//
+//	resp, err := client.Collection().ResendSTKPush(ctx, "INV-12345")
+//	if errors.Is(err, intasend.ErrNotResendable) {
+//	    // the original prompt is still open or already paid
+//	}
+func (s *CollectionService) ResendSTKPush(ctx context.Context, invoiceID string, reqOpts ...RequestOption) (*ResendSTKPushResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(false); err != nil {
+		return nil, err
+	}
+	if invoiceID == "" {
+		return nil, fmt.Errorf("%w: invoice_id", ErrIncompleteRequest)
+	}
+	status, err := s.Status(ctx, invoiceID, nil)
+	if err != nil {
+		return nil, err
+	}
+	prev := status.Invoice
+	if prev == nil {
+		return nil, fmt.Errorf("%w: invoice %s not found in status response", ErrNotResendable, invoiceID)
+	}
+	if p := prev.ProviderType(); p != "" && p != PaymentMethodMPesa {
+		return nil, fmt.Errorf("%w: invoice %s was paid with %s, not M-Pesa", ErrNotResendable, invoiceID, p)
+	}
+
+	switch prev.State {
+	case StateFailed:
+	case StateNew, StatePending:
+		if left := promptRemaining(prev); left > 0 {
+			return nil, fmt.Errorf("%w: prompt for invoice %s may still be open for %s",
+				ErrNotResendable, invoiceID, left.Round(time.Second))
+		}
+	default:
+		return nil, fmt.Errorf("%w: invoice %s is %s", ErrNotResendable, invoiceID, prev.State)
+	}
+
+	req := &STKPushRequest{
+		PhoneNumber: prev.Account,
+		Amount:      prev.Value,
+		APIRef:      prev.APIRef,
+	}
+	if c := status.Customer; c != nil {
+		req.Name = strings.TrimSpace(c.FirstName + " " + c.LastName)
+		req.Email = c.Email
+		if req.PhoneNumber == "" {
+			req.PhoneNumber = c.PhoneNumber
+		}
+	}
+	if req.PhoneNumber == "" {
+		return nil, fmt.Errorf("%w: invoice %s has no phone number", ErrNotResendable, invoiceID)
+	}
+
+	resp, err := s.MPesaSTKPush(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &ResendSTKPushResponse{Previous: prev, STKPushResponse: resp}, nil
+}
+
+// promptRemaining returns how much longer the STK prompt of a NEW or
+// PENDING invoice may be open on the customer's handset, or a non-positive
+// duration once it has expired.
+func promptRemaining(inv *Invoice) time.Duration {
+	return DefaultSTKPromptExpiry - time.Since(inv.CreatedAt)
+}
+
 // Status checks the payment status for an invoice.
 // This method does not require the secret key.
 //
 // Example:
 //
 //	status, err := client.Collection().Status(ctx, "INV-12345", nil)
-func (s *CollectionService) Status(ctx context.Context, invoiceID string, opts *StatusOptions) (*StatusResponse, error) {
+func (s *CollectionService) Status(ctx context.Context, invoiceID string, opts *StatusOptions, reqOpts ...RequestOption) (*StatusResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(false); err != nil {
+		return nil, err
+	}
+	if invoiceID == "" {
+		return nil, fmt.Errorf("%w: invoice_id", ErrIncompleteRequest)
+	}
 	req := &statusRequest{
 		InvoiceID: invoiceID,
-		PublicKey: s.client.publishableKey,
+		PublicKey: s.client.publicKey(ctx),
 	}
 
 	if opts != nil {
@@ -290,5 +707,89 @@ func (s *CollectionService) Status(ctx context.Context, invoiceID string, opts *
 	if err := s.client.postPublic(ctx, "/payment/status/", req, &resp); err != nil {
 		return nil, err
 	}
+	if resp.Invoice != nil && resp.Invoice.State == StateComplete {
+		s.client.events.publish(ctx, PaymentCompleted{Invoice: resp.Invoice})
+	}
 	return &resp, nil
 }
+
+// WaitForComplete polls the invoice's status until it is COMPLETE or
+// FAILED and returns the final status; check Invoice.State for which. It
+// waits until ctx is done unless opts sets a Timeout, after which the last
+// status is returned with ErrWaitTimeout. Polling adapts to the invoice's
+// state as described by PollOptions.
+//
+// Example:
+//
+//	status, err := client.Collection().WaitForComplete(ctx, "INV-12345", &intasend.PollOptions{Timeout: 2 * time.Minute})
+//	if errors.Is(err, intasend.ErrWaitTimeout) {
+//	    // still pending; rely on the webhook
+//	}
+func (s *CollectionService) WaitForComplete(ctx context.Context, invoiceID string, opts *PollOptions, reqOpts ...RequestOption) (*StatusResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var last *StatusResponse
+	err := pollUntil(ctx, opts, func(ctx context.Context) (string, bool, error) {
+		status, err := s.Status(ctx, invoiceID, nil)
+		if err != nil {
+			return "", false, err
+		}
+		last = status
+		if status.Invoice == nil {
+			return "", false, nil
+		}
+		state := status.Invoice.State
+		return state, state == StateComplete || state == StateFailed, nil
+	})
+	return last, err
+}
+
+// StatusBatch checks the status of several invoices concurrently and returns
+// the results keyed by invoice ID. Duplicate IDs are checked once.
+//
+// If any lookups fail, the successful results are returned together with a
+// *BatchError describing the failures.
+//
+// Example:
+//
+//	statuses, err := client.Collection().StatusBatch(ctx, []string{"INV-1", "INV-2"}, nil)
+//	var batchErr *intasend.BatchError
+//	if errors.As(err, &batchErr) {
+//	    for id, err := range batchErr.Errors {
+//	        log.Printf("status %s: %v", id, err)
+//	    }
+//	}
+func (s *CollectionService) StatusBatch(ctx context.Context, invoiceIDs []string, opts *BatchOptions, reqOpts ...RequestOption) (map[string]*StatusResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(false); err != nil {
+		return nil, err
+	}
+	unique := make([]string, 0, len(invoiceIDs))
+	seen := make(map[string]bool, len(invoiceIDs))
+	for _, id := range invoiceIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	var mu sync.Mutex
+	results := make(map[string]*StatusResponse, len(unique))
+	errs := make(map[string]error)
+
+	runBatch(len(unique), opts.concurrency(), func(i int) {
+		id := unique[i]
+		resp, err := s.Status(ctx, id, nil)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[id] = err
+			return
+		}
+		results[id] = resp
+	})
+
+	if len(errs) > 0 {
+		return results, &BatchError{Total: len(unique), Errors: errs}
+	}
+	return results, nil
+}
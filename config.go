@@ -0,0 +1,208 @@
+package intasend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config is a file-based client configuration shared across services.
+//
+// Key and challenge values may reference environment variables
+// ("${INTASEND_SECRET_KEY}") so secrets stay out of the file itself. The
+// struct carries yaml tags, so YAML files can be decoded with any YAML
+// library and passed to Config.Options.
+//
+// Example JSON:
+//
+//	{
+//	    "publishable_key": "ISPubKey_test_xxx",
+//	    "secret_key": "${INTASEND_SECRET_KEY}",
+//	    "environment": "sandbox",
+//	    "timeout": "20s",
+//	    "retry": {"max_retries": 5, "wait": "500ms"},
+//	    "defaults": {"currency": "KES"},
+//	    "webhook": {"challenge": "${INTASEND_WEBHOOK_CHALLENGE}"}
+//	}
+type Config struct {
+	PublishableKey string `json:"publishable_key,omitempty" yaml:"publishable_key,omitempty"`
+	SecretKey      string `json:"secret_key,omitempty" yaml:"secret_key,omitempty"`
+
+	// Environment is "sandbox", "production", or empty to detect it from the keys.
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+
+	// BaseURL overrides the environment's base URL.
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+
+	Timeout          Duration       `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retry            *RetryConfig   `json:"retry,omitempty" yaml:"retry,omitempty"`
+	MaxResponseBytes *int64         `json:"max_response_bytes,omitempty" yaml:"max_response_bytes,omitempty"`
+	UserAgent        string         `json:"user_agent,omitempty" yaml:"user_agent,omitempty"`
+	Debug            bool           `json:"debug,omitempty" yaml:"debug,omitempty"`
+	Defaults         DefaultsConfig `json:"defaults" yaml:"defaults,omitempty"`
+	Webhook          WebhookConfig  `json:"webhook" yaml:"webhook,omitempty"`
+}
+
+// RetryConfig configures request retries. See WithRetry.
+type RetryConfig struct {
+	MaxRetries int      `json:"max_retries" yaml:"max_retries"`
+	Wait       Duration `json:"wait" yaml:"wait"`
+}
+
+// DefaultsConfig holds values applied to requests that leave them empty.
+type DefaultsConfig struct {
+	Currency string `json:"currency,omitempty" yaml:"currency,omitempty"`
+}
+
+// WebhookConfig holds webhook settings. See WithWebhookChallenge.
+type WebhookConfig struct {
+	Challenge string `json:"challenge,omitempty" yaml:"challenge,omitempty"`
+}
+
+// Duration is a time.Duration that decodes from a Go duration string
+// ("30s", "1m30s") or a number of seconds.
+type Duration time.Duration
+
+// UnmarshalJSON accepts a duration string or a number of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	var secs float64
+	if err := json.Unmarshal(data, &secs); err != nil {
+		return fmt.Errorf("intasend: invalid duration %s", data)
+	}
+	*d = Duration(secs * float64(time.Second))
+	return nil
+}
+
+// UnmarshalText parses a Go duration string.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("intasend: invalid duration %q: %w", text, err)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText formats the duration as a Go duration string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// LoadConfig decodes a JSON configuration. Unknown fields are rejected so
+// typos surface at startup.
+func LoadConfig(r io.Reader) (*Config, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("intasend: decoding config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// LoadConfigFile reads and decodes a JSON configuration file.
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("intasend: reading config: %w", err)
+	}
+	return LoadConfig(bytes.NewReader(data))
+}
+
+// Options converts the configuration into client options, expanding
+// environment variable references in keys and the webhook challenge.
+func (cfg *Config) Options() ([]Option, error) {
+	var opts []Option
+
+	if key := os.ExpandEnv(cfg.PublishableKey); key != "" {
+		opts = append(opts, WithPublishableKey(key))
+	}
+	if key := os.ExpandEnv(cfg.SecretKey); key != "" {
+		opts = append(opts, WithSecretKey(key))
+	}
+
+	switch strings.ToLower(cfg.Environment) {
+	case "":
+	case "sandbox":
+		opts = append(opts, WithSandbox())
+	case "production":
+		opts = append(opts, WithProduction())
+	default:
+		return nil, fmt.Errorf("intasend: unknown environment %q in config", cfg.Environment)
+	}
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(time.Duration(cfg.Timeout)))
+	}
+	if cfg.Retry != nil {
+		opts = append(opts, WithRetry(cfg.Retry.MaxRetries, time.Duration(cfg.Retry.Wait)))
+	}
+	if cfg.MaxResponseBytes != nil {
+		opts = append(opts, WithMaxResponseBytes(*cfg.MaxResponseBytes))
+	}
+	if cfg.UserAgent != "" {
+		opts = append(opts, WithUserAgent(cfg.UserAgent))
+	}
+	if cfg.Debug {
+		opts = append(opts, WithDebug(true))
+	}
+	if cfg.Defaults.Currency != "" {
+		opts = append(opts, WithDefaultCurrency(strings.ToUpper(cfg.Defaults.Currency)))
+	}
+	if challenge := os.ExpandEnv(cfg.Webhook.Challenge); challenge != "" {
+		opts = append(opts, WithWebhookChallenge(challenge))
+	}
+	return opts, nil
+}
+
+// NewFromConfig creates a client from a JSON configuration. Additional
+// options are applied after the configuration and take precedence.
+//
+// Example:
+//
+//	f, _ := os.Open("intasend.json")
+//	defer f.Close()
+//	client, err := intasend.NewFromConfig(f, intasend.WithHTTPClient(httpClient))
+func NewFromConfig(r io.Reader, opts ...Option) (*Client, error) {
+	cfg, err := LoadConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	return newFromConfig(cfg, opts)
+}
+
+// NewFromConfigFile creates a client from a JSON configuration file.
+//
+// Example:
+//
+//	client, err := intasend.NewFromConfigFile("/etc/payments/intasend.json")
+func NewFromConfigFile(path string, opts ...Option) (*Client, error) {
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newFromConfig(cfg, opts)
+}
+
+func newFromConfig(cfg *Config, extra []Option) (*Client, error) {
+	opts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(append(opts, extra...)...)
+}
@@ -0,0 +1,62 @@
+package intasend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldsKey is the context key for per-request log fields.
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying the given fields.
+// The fields are attached to debug log output for every request made
+// with the returned context, making it easy to correlate SDK traffic
+// with your own identifiers such as order IDs.
+//
+// Fields already present on ctx are kept; new values win on key conflicts.
+//
+// Example:
+//
+//	ctx = intasend.ContextWithFields(ctx, map[string]interface{}{
+//	    "order_id": "order-123",
+//	})
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	merged := make(map[string]interface{}, len(fields))
+	for k, v := range FieldsFromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the fields attached to ctx by ContextWithFields.
+// It returns nil if no fields are present. The returned map must not be modified.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// formatFields renders fields as a stable, space-prefixed key=value list.
+func formatFields(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+	return b.String()
+}
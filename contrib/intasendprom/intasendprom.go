@@ -0,0 +1,151 @@
+// Package intasendprom provides Prometheus collectors for the IntaSend Go SDK.
+//
+// The Collector implements both prometheus.Collector and
+// intasend.MetricsCollector, so a single value is registered with Prometheus
+// and passed to the client:
+//
+//	collector := intasendprom.NewCollector(intasendprom.Options{})
+//	prometheus.MustRegister(collector)
+//
+//	client, err := intasend.New(
+//	    intasend.WithSecretKey("ISSecretKey_test_..."),
+//	    intasend.WithMetricsCollector(collector),
+//	)
+package intasendprom
+
+import (
+	"strconv"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultNamespace is the metric namespace used when Options.Namespace is empty.
+const DefaultNamespace = "intasend"
+
+// Options configures a Collector.
+type Options struct {
+	// Namespace prefixes all metric names. Defaults to "intasend".
+	Namespace string
+
+	// Subsystem is an optional metric subsystem.
+	Subsystem string
+
+	// ConstLabels are added to every metric, e.g. {"service": "checkout"}.
+	ConstLabels prometheus.Labels
+
+	// Buckets are the duration histogram buckets in seconds.
+	// Defaults to prometheus.DefBuckets.
+	Buckets []float64
+}
+
+// Collector exposes IntaSend request metrics to Prometheus.
+type Collector struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	retries     *prometheus.CounterVec
+	rateLimited *prometheus.CounterVec
+	errors      *prometheus.CounterVec
+}
+
+var (
+	_ prometheus.Collector      = (*Collector)(nil)
+	_ intasend.MetricsCollector = (*Collector)(nil)
+)
+
+// NewCollector creates a Collector with the given options.
+func NewCollector(opts Options) *Collector {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	if opts.Buckets == nil {
+		opts.Buckets = prometheus.DefBuckets
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "requests_total",
+			Help:        "Total number of IntaSend API requests by endpoint and status code.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "endpoint", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of IntaSend API requests, including retries.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     opts.Buckets,
+		}, []string{"method", "endpoint", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "request_retries_total",
+			Help:        "Total number of retried IntaSend API request attempts.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "endpoint"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "rate_limited_total",
+			Help:        "Total number of IntaSend API attempts rejected with HTTP 429.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "endpoint"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        "request_errors_total",
+			Help:        "Total number of IntaSend API requests that returned an error.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"method", "endpoint", "kind"}),
+	}
+}
+
+// ObserveRequest implements intasend.MetricsCollector.
+func (c *Collector) ObserveRequest(m intasend.RequestMetrics) {
+	status := strconv.Itoa(m.StatusCode)
+
+	c.requests.WithLabelValues(m.Method, m.Endpoint, status).Inc()
+	c.duration.WithLabelValues(m.Method, m.Endpoint, status).Observe(m.Duration.Seconds())
+
+	if m.Retries > 0 {
+		c.retries.WithLabelValues(m.Method, m.Endpoint).Add(float64(m.Retries))
+	}
+	if m.RateLimited > 0 {
+		c.rateLimited.WithLabelValues(m.Method, m.Endpoint).Add(float64(m.RateLimited))
+	}
+	if m.Err != nil {
+		c.errors.WithLabelValues(m.Method, m.Endpoint, errorKind(m.Err)).Inc()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.retries.Describe(ch)
+	c.rateLimited.Describe(ch)
+	c.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.retries.Collect(ch)
+	c.rateLimited.Collect(ch)
+	c.errors.Collect(ch)
+}
+
+// errorKind classifies an error into a low-cardinality label value.
+func errorKind(err error) string {
+	switch {
+	case intasend.IsAPIError(err):
+		return "api"
+	case intasend.IsNetworkError(err):
+		return "network"
+	default:
+		return "other"
+	}
+}
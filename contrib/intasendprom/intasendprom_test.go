@@ -0,0 +1,73 @@
+package intasendprom_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/contrib/intasendprom"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector_ObserveRequest(t *testing.T) {
+	c := intasendprom.NewCollector(intasendprom.Options{})
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c)
+
+	c.ObserveRequest(intasend.RequestMetrics{
+		Method:      "POST",
+		Endpoint:    "/payment/mpesa-stk-push/",
+		StatusCode:  200,
+		Duration:    150 * time.Millisecond,
+		Retries:     2,
+		RateLimited: 1,
+	})
+	c.ObserveRequest(intasend.RequestMetrics{
+		Method:     "GET",
+		Endpoint:   "/wallets/:id/",
+		StatusCode: 404,
+		Err:        &intasend.APIError{HTTPStatusCode: 404},
+	})
+
+	want := `
+# HELP intasend_requests_total Total number of IntaSend API requests by endpoint and status code.
+# TYPE intasend_requests_total counter
+intasend_requests_total{endpoint="/payment/mpesa-stk-push/",method="POST",status="200"} 1
+intasend_requests_total{endpoint="/wallets/:id/",method="GET",status="404"} 1
+# HELP intasend_request_retries_total Total number of retried IntaSend API request attempts.
+# TYPE intasend_request_retries_total counter
+intasend_request_retries_total{endpoint="/payment/mpesa-stk-push/",method="POST"} 2
+# HELP intasend_rate_limited_total Total number of IntaSend API attempts rejected with HTTP 429.
+# TYPE intasend_rate_limited_total counter
+intasend_rate_limited_total{endpoint="/payment/mpesa-stk-push/",method="POST"} 1
+# HELP intasend_request_errors_total Total number of IntaSend API requests that returned an error.
+# TYPE intasend_request_errors_total counter
+intasend_request_errors_total{endpoint="/wallets/:id/",kind="api",method="GET"} 1
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(want),
+		"intasend_requests_total",
+		"intasend_request_retries_total",
+		"intasend_rate_limited_total",
+		"intasend_request_errors_total",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := testutil.CollectAndCount(c, "intasend_request_duration_seconds"); n != 2 {
+		t.Errorf("expected 2 duration series, got %d", n)
+	}
+}
+
+func TestCollector_Namespace(t *testing.T) {
+	c := intasendprom.NewCollector(intasendprom.Options{Namespace: "payments"})
+	c.ObserveRequest(intasend.RequestMetrics{Method: "GET", Endpoint: "/wallets/", StatusCode: 200})
+	c.ObserveRequest(intasend.RequestMetrics{Method: "GET", Endpoint: "/wallets/", Err: errors.New("boom")})
+
+	if n := testutil.CollectAndCount(c, "payments_requests_total"); n != 2 {
+		t.Errorf("expected 2 series under custom namespace, got %d", n)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/emilio-kariuki/intasend-go/contrib/intasendstore
+
+go 1.21
+
+require github.com/emilio-kariuki/intasend-go v1.0.0
+
+replace github.com/emilio-kariuki/intasend-go => ../..
@@ -0,0 +1,210 @@
+package intasendstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/webhook"
+)
+
+// ErrNoChallenge is returned by NewIngestor when neither Challenge nor
+// SecretProvider is set, as deliveries could not be verified.
+var ErrNoChallenge = errors.New("intasendstore: a webhook challenge or secret provider is required")
+
+// IngestOptions configures an Ingestor. Challenge or SecretProvider is
+// required.
+type IngestOptions struct {
+	// Store builds the statements. Required.
+	Store *Store
+
+	// DB is the database events are written to. Required.
+	DB *sql.DB
+
+	// Challenge is the webhook challenge configured in the IntaSend dashboard.
+	Challenge string
+
+	// SecretProvider, if set, supplies the challenge instead of Challenge,
+	// so it can be kept in a secrets manager and rotated without a
+	// redeploy.
+	SecretProvider webhook.SecretProvider
+
+	// Tokenizer, if set, replaces phone numbers, emails, names, and
+	// accounts in the stored payload and rows with tokens; see
+	// intasend.TokenizeJSON.
+	Tokenizer intasend.Tokenizer
+
+	// BeforeCommit runs inside the ingest transaction after the rows are
+	// upserted. Returning an error rolls back and fails the delivery, so
+	// IntaSend retries it. Use it to update your own tables atomically.
+	BeforeCommit func(ctx context.Context, tx *sql.Tx, event *webhook.Event) error
+
+	// AfterCommit runs once the transaction has committed, before the
+	// delivery is acknowledged. It is not called for duplicates.
+	AfterCommit func(ctx context.Context, event *webhook.Event)
+
+	// OnError, if set, is called by ServeHTTP with every delivery rejected
+	// or failed, for logging.
+	OnError func(ctx context.Context, err error)
+}
+
+// Ingestor writes webhook deliveries to the database with at-least-once
+// semantics: each delivery is recorded in the events table, deduplicated by
+// webhook.Event.ID, and its invoice, payout, or chargeback rows are upserted
+// in the same transaction before the delivery is acknowledged.
+//
+// Ingestor implements http.Handler and can be mounted directly:
+//
+//	ingestor, err := intasendstore.NewIngestor(intasendstore.IngestOptions{
+//	    Store:     store,
+//	    DB:        db,
+//	    Challenge: client.WebhookChallenge(),
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	http.Handle("/webhooks/intasend", ingestor)
+type Ingestor struct {
+	opts IngestOptions
+}
+
+// NewIngestor creates an Ingestor with the given options. It returns
+// ErrNoChallenge if neither Challenge nor SecretProvider is set.
+func NewIngestor(opts IngestOptions) (*Ingestor, error) {
+	if opts.Challenge == "" && opts.SecretProvider == nil {
+		return nil, ErrNoChallenge
+	}
+	if opts.SecretProvider == nil {
+		opts.SecretProvider = webhook.StaticSecret(opts.Challenge)
+	}
+	return &Ingestor{opts: opts}, nil
+}
+
+// Ingest verifies, deduplicates, and persists a webhook body. It reports
+// whether the delivery was a duplicate of one already ingested. Deliveries
+// are never accepted unverified: an Ingestor without a challenge fails
+// with webhook.ErrSecretUnavailable.
+func (i *Ingestor) Ingest(ctx context.Context, body []byte) (duplicate bool, err error) {
+	provider := i.opts.SecretProvider
+	if provider == nil {
+		// An Ingestor not built with NewIngestor.
+		provider = webhook.StaticSecret(i.opts.Challenge)
+	}
+	event, err := webhook.ParseWithProvider(ctx, body, provider)
+	if err != nil {
+		return false, err
+	}
+	if i.opts.Tokenizer != nil {
+		if event.Payload, err = intasend.TokenizeJSON(ctx, i.opts.Tokenizer, event.Payload); err != nil {
+			return false, err
+		}
+	}
+
+	tx, err := i.opts.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("intasendstore: begin ingest: %w", err)
+	}
+	defer tx.Rollback() // no-op after Commit
+
+	inserted, err := i.opts.Store.InsertEvent(ctx, tx, EventRow{
+		EventID:    event.ID(),
+		EventType:  string(event.Type),
+		ResourceID: event.ResourceID,
+		Payload:    event.Payload,
+		ReceivedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if !inserted {
+		return true, nil
+	}
+
+	if err := i.upsert(ctx, tx, event); err != nil {
+		return false, err
+	}
+	if i.opts.BeforeCommit != nil {
+		if err := i.opts.BeforeCommit(ctx, tx, event); err != nil {
+			return false, fmt.Errorf("intasendstore: before commit hook: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("intasendstore: commit ingest: %w", err)
+	}
+
+	if i.opts.AfterCommit != nil {
+		i.opts.AfterCommit(ctx, event)
+	}
+	return false, nil
+}
+
+// upsert writes the resource rows described by the event.
+func (i *Ingestor) upsert(ctx context.Context, tx *sql.Tx, event *webhook.Event) error {
+	switch event.Type {
+	case webhook.EventInvoice:
+		inv, err := event.Invoice()
+		if err != nil {
+			return err
+		}
+		return i.opts.Store.UpsertInvoice(ctx, tx, InvoiceRowFrom(inv))
+	case webhook.EventPayout:
+		payout, err := event.Payout()
+		if err != nil {
+			return err
+		}
+		for _, row := range PayoutRowsFrom(payout) {
+			if err := i.opts.Store.UpsertPayout(ctx, tx, row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case webhook.EventChargeback:
+		cb, err := event.Chargeback()
+		if err != nil {
+			return err
+		}
+		return i.opts.Store.UpsertChargeback(ctx, tx, ChargebackRowFrom(cb))
+	default:
+		// Unknown events are still recorded in the events table.
+		return nil
+	}
+}
+
+// ServeHTTP ingests a webhook delivery. It acknowledges with 200 once the
+// delivery is committed or recognised as a duplicate, and returns a 5xx
+// status on storage failures so IntaSend redelivers.
+func (i *Ingestor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := readBody(r)
+	if err == nil {
+		_, err = i.Ingest(r.Context(), body)
+	}
+	if err != nil {
+		if i.opts.OnError != nil {
+			i.opts.OnError(r.Context(), err)
+		}
+		status := webhook.StatusCode(err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// readBody reads a request body up to webhook.MaxBodyBytes.
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, webhook.MaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", webhook.ErrInvalidPayload, err)
+	}
+	return body, nil
+}
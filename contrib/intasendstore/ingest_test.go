@@ -0,0 +1,291 @@
+package intasendstore_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/contrib/intasendstore"
+	"github.com/emilio-kariuki/intasend-go/webhook"
+)
+
+// fakeDB is an in-memory database/sql driver that records committed
+// statements and enforces event ID uniqueness.
+type fakeDB struct {
+	mu        sync.Mutex
+	events    map[string]bool
+	committed []string
+}
+
+func newFakeDB() *fakeDB { return &fakeDB{events: map[string]bool{}} }
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (db *fakeDB) statements(prefix string) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	n := 0
+	for _, q := range db.committed {
+		if strings.HasPrefix(q, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeConn struct {
+	db      *fakeDB
+	pending []string
+	events  []string
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *fakeConn) Commit() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.committed = append(c.db.committed, c.pending...)
+	for _, id := range c.events {
+		c.db.events[id] = true
+	}
+	c.pending, c.events = nil, nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.pending, c.events = nil, nil
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.HasPrefix(s.query, "INSERT INTO intasend_events") {
+		id := args[0].(string)
+		s.conn.db.mu.Lock()
+		seen := s.conn.db.events[id]
+		s.conn.db.mu.Unlock()
+		if seen {
+			return driver.RowsAffected(0), nil
+		}
+		s.conn.events = append(s.conn.events, id)
+	}
+	s.conn.pending = append(s.conn.pending, s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("fakeStmt: queries are not supported")
+}
+
+const invoiceWebhook = `{
+	"invoice_id": "INV-1",
+	"state": "COMPLETE",
+	"provider": "M-PESA",
+	"value": "100.00",
+	"api_ref": "order-1",
+	"updated_at": "2024-01-01T10:00:00.000000+03:00",
+	"challenge": "secret"
+}`
+
+func deliver(t *testing.T, handler http.Handler, body string) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body)))
+	return rec.Code
+}
+
+func newIngestor(t *testing.T, opts intasendstore.IngestOptions) *intasendstore.Ingestor {
+	t.Helper()
+	ingestor, err := intasendstore.NewIngestor(opts)
+	if err != nil {
+		t.Fatalf("NewIngestor: %v", err)
+	}
+	return ingestor
+}
+
+func TestIngestor_DedupesRedeliveries(t *testing.T) {
+	db := newFakeDB()
+	var committed int
+	ingestor := newIngestor(t, intasendstore.IngestOptions{
+		Store:     intasendstore.New(intasendstore.Options{}),
+		DB:        sql.OpenDB(db),
+		Challenge: "secret",
+		AfterCommit: func(ctx context.Context, event *webhook.Event) {
+			committed++
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		if code := deliver(t, ingestor, invoiceWebhook); code != http.StatusOK {
+			t.Fatalf("delivery %d: expected 200, got %d", i, code)
+		}
+	}
+	if n := db.statements("INSERT INTO intasend_invoices"); n != 1 {
+		t.Errorf("expected 1 invoice upsert, got %d", n)
+	}
+	if committed != 1 {
+		t.Errorf("expected AfterCommit once, got %d", committed)
+	}
+
+	// A later state change for the same invoice is a new event.
+	failed := strings.Replace(invoiceWebhook, `"COMPLETE"`, `"FAILED"`, 1)
+	if code := deliver(t, ingestor, failed); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if n := db.statements("INSERT INTO intasend_invoices"); n != 2 {
+		t.Errorf("expected 2 invoice upserts, got %d", n)
+	}
+}
+
+func TestIngestor_RejectsBadChallenge(t *testing.T) {
+	db := newFakeDB()
+	ingestor := newIngestor(t, intasendstore.IngestOptions{
+		Store:     intasendstore.New(intasendstore.Options{}),
+		DB:        sql.OpenDB(db),
+		Challenge: "other",
+	})
+	if code := deliver(t, ingestor, invoiceWebhook); code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", code)
+	}
+	if code := deliver(t, ingestor, "{"); code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", code)
+	}
+	if len(db.committed) != 0 {
+		t.Errorf("expected nothing committed, got %v", db.committed)
+	}
+}
+
+func TestIngestor_RequiresChallenge(t *testing.T) {
+	db := newFakeDB()
+	opts := intasendstore.IngestOptions{Store: intasendstore.New(intasendstore.Options{}), DB: sql.OpenDB(db)}
+	if _, err := intasendstore.NewIngestor(opts); !errors.Is(err, intasendstore.ErrNoChallenge) {
+		t.Fatalf("expected ErrNoChallenge, got %v", err)
+	}
+
+	// An Ingestor built without NewIngestor fails closed too.
+	var zero intasendstore.Ingestor
+	if _, err := zero.Ingest(context.Background(), []byte(invoiceWebhook)); !errors.Is(err, webhook.ErrSecretUnavailable) {
+		t.Errorf("expected ErrSecretUnavailable, got %v", err)
+	}
+	if code := deliver(t, &zero, invoiceWebhook); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", code)
+	}
+}
+
+func TestIngestor_OnError(t *testing.T) {
+	var reported error
+	ingestor := newIngestor(t, intasendstore.IngestOptions{
+		Store:     intasendstore.New(intasendstore.Options{}),
+		DB:        sql.OpenDB(newFakeDB()),
+		Challenge: "other",
+		OnError:   func(ctx context.Context, err error) { reported = err },
+	})
+	deliver(t, ingestor, invoiceWebhook)
+	if !errors.Is(reported, webhook.ErrInvalidChallenge) {
+		t.Errorf("expected ErrInvalidChallenge reported, got %v", reported)
+	}
+}
+
+func TestIngestor_BeforeCommitFailureIsRetried(t *testing.T) {
+	db := newFakeDB()
+	fail := true
+	ingestor := newIngestor(t, intasendstore.IngestOptions{
+		Store:     intasendstore.New(intasendstore.Options{}),
+		DB:        sql.OpenDB(db),
+		Challenge: "secret",
+		BeforeCommit: func(ctx context.Context, tx *sql.Tx, event *webhook.Event) error {
+			if fail {
+				return errors.New("orders table locked")
+			}
+			_, err := tx.ExecContext(ctx, "UPDATE orders SET paid = true")
+			return err
+		},
+	})
+
+	if code := deliver(t, ingestor, invoiceWebhook); code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", code)
+	}
+	if len(db.committed) != 0 {
+		t.Fatalf("expected rollback, got %v", db.committed)
+	}
+
+	fail = false
+	if code := deliver(t, ingestor, invoiceWebhook); code != http.StatusOK {
+		t.Fatalf("expected 200 on redelivery, got %d", code)
+	}
+	if db.statements("UPDATE orders") != 1 || db.statements("INSERT INTO intasend_invoices") != 1 {
+		t.Errorf("expected hook and upsert in one commit, got %v", db.committed)
+	}
+}
+
+func TestIngestor_PayoutEvent(t *testing.T) {
+	db := newFakeDB()
+	ingestor := newIngestor(t, intasendstore.IngestOptions{
+		Store:     intasendstore.New(intasendstore.Options{}),
+		DB:        sql.OpenDB(db),
+		Challenge: "secret",
+	})
+
+	duplicate, err := ingestor.Ingest(context.Background(), []byte(`{
+		"challenge": "secret",
+		"tracking_id": "TRK-1",
+		"status": "Completed",
+		"transactions": [
+			{"request_ref_id": "REF-1", "status": "Successful", "amount": "100.00"},
+			{"request_ref_id": "REF-2", "status": "Failed", "amount": "50.00"}
+		]
+	}`))
+	if err != nil || duplicate {
+		t.Fatalf("unexpected result: %v, %v", duplicate, err)
+	}
+	if n := db.statements("INSERT INTO intasend_payouts"); n != 2 {
+		t.Errorf("expected 2 payout upserts, got %d", n)
+	}
+}
+
+func TestIngestor_Tokenizer(t *testing.T) {
+	db := newFakeDB()
+	var stored []byte
+	var account string
+	ingestor := newIngestor(t, intasendstore.IngestOptions{
+		Store:     intasendstore.New(intasendstore.Options{}),
+		DB:        sql.OpenDB(db),
+		Challenge: "secret",
+		Tokenizer: intasend.NewHMACTokenizer([]byte("key")),
+		BeforeCommit: func(ctx context.Context, tx *sql.Tx, event *webhook.Event) error {
+			stored = event.Payload
+			inv, err := event.Invoice()
+			if err == nil {
+				account = inv.Account
+			}
+			return err
+		},
+	})
+
+	body := strings.Replace(invoiceWebhook, `"api_ref"`, `"account": "254712345678", "api_ref"`, 1)
+	if _, err := ingestor.Ingest(context.Background(), []byte(body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(stored), "254712345678") || !strings.HasPrefix(account, "tok_account_") {
+		t.Errorf("expected account to be tokenized, got %q in %s", account, stored)
+	}
+}
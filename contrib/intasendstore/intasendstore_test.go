@@ -0,0 +1,161 @@
+package intasendstore_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/contrib/intasendstore"
+)
+
+// execCall is a recorded ExecContext call.
+type execCall struct {
+	query string
+	args  []interface{}
+}
+
+// recordingExecer records statements and reports a fixed number of affected rows.
+type recordingExecer struct {
+	calls    []execCall
+	affected int64
+	err      error
+}
+
+func (r *recordingExecer) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	r.calls = append(r.calls, execCall{query: query, args: args})
+	if r.err != nil {
+		return nil, r.err
+	}
+	return driverResult(r.affected), nil
+}
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func TestStore_Schema(t *testing.T) {
+	pg := intasendstore.New(intasendstore.Options{})
+	stmts := strings.Join(pg.Schema(), ";\n")
+	for _, want := range []string{
+		"CREATE TABLE IF NOT EXISTS intasend_invoices",
+		"CREATE TABLE IF NOT EXISTS intasend_payouts",
+		"PRIMARY KEY (tracking_id, request_ref_id)",
+		"CREATE INDEX IF NOT EXISTS intasend_chargebacks_invoice_id_idx",
+		"payload BYTEA",
+		"updated_at TIMESTAMPTZ",
+	} {
+		if !strings.Contains(stmts, want) {
+			t.Errorf("postgres schema missing %q", want)
+		}
+	}
+
+	lite := intasendstore.New(intasendstore.Options{Dialect: intasendstore.SQLite, TablePrefix: "pay_"})
+	stmts = strings.Join(lite.Schema(), ";\n")
+	if !strings.Contains(stmts, "CREATE TABLE IF NOT EXISTS pay_events") || !strings.Contains(stmts, "payload BLOB") {
+		t.Errorf("unexpected sqlite schema:\n%s", stmts)
+	}
+
+	db := &recordingExecer{}
+	if err := lite.Migrate(context.Background(), db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(db.calls) != len(lite.Schema()) {
+		t.Errorf("expected %d statements, got %d", len(lite.Schema()), len(db.calls))
+	}
+}
+
+func TestStore_UpsertInvoice(t *testing.T) {
+	now := time.Now()
+	row := intasendstore.InvoiceRowFrom(&intasend.Invoice{
+		InvoiceID: "INV-1", State: intasend.StateComplete, Provider: "M-PESA",
+		Value: 100, APIRef: "order-1", UpdatedAt: now,
+	})
+
+	db := &recordingExecer{affected: 1}
+	store := intasendstore.New(intasendstore.Options{})
+	if err := store.UpsertInvoice(context.Background(), db, row); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	call := db.calls[0]
+	for _, want := range []string{
+		"INSERT INTO intasend_invoices (invoice_id, state,",
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		"ON CONFLICT (invoice_id) DO UPDATE SET state = excluded.state",
+		"WHERE intasend_invoices.updated_at <= excluded.updated_at",
+	} {
+		if !strings.Contains(call.query, want) {
+			t.Errorf("query missing %q:\n%s", want, call.query)
+		}
+	}
+	if strings.Contains(call.query, "invoice_id = excluded") {
+		t.Error("primary key should not be updated")
+	}
+	if len(call.args) != 9 || call.args[0] != "INV-1" || call.args[5] != "order-1" {
+		t.Errorf("unexpected args: %v", call.args)
+	}
+}
+
+func TestStore_UpsertPayoutsAndChargeback(t *testing.T) {
+	rows := intasendstore.PayoutRowsFrom(&intasend.PayoutStatusResponse{
+		TrackingID: "TRK-1",
+		Transactions: []intasend.TransactionResult{
+			{RequestRefID: "REF-1", Status: "Successful", Amount: 150.5},
+			{RequestRefID: "REF-2", Status: "Failed", Amount: "200"},
+		},
+	})
+	if rows[0].Amount != "150.5" || rows[1].Amount != "200" {
+		t.Errorf("unexpected amounts: %q, %q", rows[0].Amount, rows[1].Amount)
+	}
+
+	db := &recordingExecer{affected: 1}
+	store := intasendstore.New(intasendstore.Options{Dialect: intasendstore.SQLite})
+	for _, row := range rows {
+		if err := store.UpsertPayout(context.Background(), db, row); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !strings.Contains(db.calls[0].query, "ON CONFLICT (tracking_id, request_ref_id)") ||
+		strings.Contains(db.calls[0].query, "$1") {
+		t.Errorf("unexpected payout query:\n%s", db.calls[0].query)
+	}
+
+	cb := intasendstore.ChargebackRowFrom(&intasend.Chargeback{
+		ChargebackID: "CHG-1", Invoice: "INV-1", Amount: 50, Reason: intasend.RefundReasonCustomerRequest,
+	})
+	if cb.InvoiceID != "INV-1" || cb.Reason != string(intasend.RefundReasonCustomerRequest) {
+		t.Errorf("unexpected chargeback row: %+v", cb)
+	}
+	if err := store.UpsertChargeback(context.Background(), db, cb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStore_InsertEventDedupe(t *testing.T) {
+	store := intasendstore.New(intasendstore.Options{})
+	row := intasendstore.EventRow{EventID: "EVT-1", EventType: "collection", ResourceID: "INV-1", Payload: []byte(`{}`)}
+
+	inserted, err := store.InsertEvent(context.Background(), &recordingExecer{affected: 1}, row)
+	if err != nil || !inserted {
+		t.Fatalf("expected first insert to succeed, got %v, %v", inserted, err)
+	}
+
+	db := &recordingExecer{affected: 0}
+	inserted, err = store.InsertEvent(context.Background(), db, row)
+	if err != nil || inserted {
+		t.Fatalf("expected duplicate to be skipped, got %v, %v", inserted, err)
+	}
+	if !strings.Contains(db.calls[0].query, "ON CONFLICT (event_id) DO NOTHING") {
+		t.Errorf("unexpected query:\n%s", db.calls[0].query)
+	}
+
+	boom := errors.New("boom")
+	if _, err := store.InsertEvent(context.Background(), &recordingExecer{err: boom}, row); !errors.Is(err, boom) {
+		t.Errorf("expected wrapped error, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package intasendstore
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// InvoiceRow is the persisted form of an intasend.Invoice.
+type InvoiceRow struct {
+	InvoiceID    string    `db:"invoice_id" gorm:"primaryKey"`
+	State        string    `db:"state"`
+	Provider     string    `db:"provider"`
+	Value        float64   `db:"value"`
+	Account      string    `db:"account"`
+	APIRef       string    `db:"api_ref" gorm:"index"`
+	FailedReason string    `db:"failed_reason"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// TableName returns the default table name, for GORM.
+func (InvoiceRow) TableName() string { return DefaultTablePrefix + "invoices" }
+
+// InvoiceRowFrom maps an invoice to a row.
+func InvoiceRowFrom(inv *intasend.Invoice) InvoiceRow {
+	return InvoiceRow{
+		InvoiceID:    inv.InvoiceID,
+		State:        inv.State,
+		Provider:     inv.Provider,
+		Value:        inv.Value,
+		Account:      inv.Account,
+		APIRef:       inv.APIRef,
+		FailedReason: inv.FailedReason,
+		CreatedAt:    inv.CreatedAt,
+		UpdatedAt:    inv.UpdatedAt,
+	}
+}
+
+// PayoutRow is the persisted form of a single payout transaction.
+// Rows are keyed by the batch tracking ID and the transaction reference.
+type PayoutRow struct {
+	TrackingID       string    `db:"tracking_id" gorm:"primaryKey"`
+	RequestRefID     string    `db:"request_ref_id" gorm:"primaryKey"`
+	Status           string    `db:"status"`
+	Name             string    `db:"name"`
+	Account          string    `db:"account"`
+	Amount           string    `db:"amount"`
+	Narrative        string    `db:"narrative"`
+	BankCode         string    `db:"bank_code"`
+	AccountType      string    `db:"account_type"`
+	AccountReference string    `db:"account_reference"`
+	FailedReason     string    `db:"failed_reason"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// TableName returns the default table name, for GORM.
+func (PayoutRow) TableName() string { return DefaultTablePrefix + "payouts" }
+
+// PayoutRowFrom maps a payout transaction result to a row.
+func PayoutRowFrom(trackingID string, tx *intasend.TransactionResult) PayoutRow {
+	return PayoutRow{
+		TrackingID:       trackingID,
+		RequestRefID:     tx.RequestRefID,
+		Status:           tx.Status,
+		Name:             tx.Name,
+		Account:          tx.Account,
+		Amount:           formatAmount(tx.Amount),
+		Narrative:        tx.Narrative,
+		BankCode:         tx.BankCode,
+		AccountType:      tx.AccountType,
+		AccountReference: tx.AccountReference,
+		FailedReason:     tx.FailedReason,
+		CreatedAt:        tx.CreatedAt,
+		UpdatedAt:        tx.UpdatedAt,
+	}
+}
+
+// PayoutRowsFrom maps every transaction in a payout status response to rows.
+func PayoutRowsFrom(resp *intasend.PayoutStatusResponse) []PayoutRow {
+	rows := make([]PayoutRow, 0, len(resp.Transactions))
+	for i := range resp.Transactions {
+		rows = append(rows, PayoutRowFrom(resp.TrackingID, &resp.Transactions[i]))
+	}
+	return rows
+}
+
+// ChargebackRow is the persisted form of an intasend.Chargeback.
+type ChargebackRow struct {
+	ChargebackID  string    `db:"chargeback_id" gorm:"primaryKey"`
+	InvoiceID     string    `db:"invoice_id" gorm:"index"`
+	Amount        float64   `db:"amount"`
+	Status        string    `db:"status"`
+	Reason        string    `db:"reason"`
+	ReasonDetails string    `db:"reason_details"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+// TableName returns the default table name, for GORM.
+func (ChargebackRow) TableName() string { return DefaultTablePrefix + "chargebacks" }
+
+// ChargebackRowFrom maps a chargeback to a row.
+func ChargebackRowFrom(cb *intasend.Chargeback) ChargebackRow {
+	return ChargebackRow{
+		ChargebackID:  cb.ChargebackID,
+		InvoiceID:     cb.Invoice,
+		Amount:        cb.Amount,
+		Status:        cb.Status,
+		Reason:        string(cb.Reason),
+		ReasonDetails: cb.ReasonDetails,
+		CreatedAt:     cb.CreatedAt,
+		UpdatedAt:     cb.UpdatedAt,
+	}
+}
+
+// EventRow records a received event, such as a webhook delivery. EventID
+// must be unique per delivery so duplicates can be detected on insert.
+type EventRow struct {
+	EventID    string    `db:"event_id" gorm:"primaryKey"`
+	EventType  string    `db:"event_type"`
+	ResourceID string    `db:"resource_id" gorm:"index"`
+	Payload    []byte    `db:"payload"`
+	ReceivedAt time.Time `db:"received_at"`
+}
+
+// TableName returns the default table name, for GORM.
+func (EventRow) TableName() string { return DefaultTablePrefix + "events" }
+
+// formatAmount normalizes the API's string-or-number amount to a string.
+func formatAmount(v interface{}) string {
+	switch a := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return a
+	case float64:
+		return strconv.FormatFloat(a, 'f', -1, 64)
+	default:
+		return fmt.Sprint(a)
+	}
+}
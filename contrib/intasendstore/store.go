@@ -0,0 +1,261 @@
+// Package intasendstore persists IntaSend invoices, payouts, chargebacks, and
+// events to a SQL database.
+//
+// It maps SDK types to row structs, creates the schema, and provides upsert
+// helpers that work with *sql.DB, *sql.Tx, or *sql.Conn:
+//
+//	store := intasendstore.New(intasendstore.Options{Dialect: intasendstore.Postgres})
+//	if err := store.Migrate(ctx, db); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	status, _ := client.Collection().Status(ctx, "INV-123", nil)
+//	err := store.UpsertInvoice(ctx, db, intasendstore.InvoiceRowFrom(status.Invoice))
+//
+// Upserts never overwrite a row with an older UpdatedAt, so replayed or
+// out-of-order deliveries are safe. The row structs also carry db and gorm
+// tags for use with sqlx or GORM.
+package intasendstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// DefaultTablePrefix is prepended to table names when Options.TablePrefix is empty.
+const DefaultTablePrefix = "intasend_"
+
+// Dialect selects the SQL syntax used for placeholders, column types, and upserts.
+type Dialect int
+
+const (
+	// Postgres uses $n placeholders and ON CONFLICT upserts.
+	Postgres Dialect = iota
+
+	// SQLite uses ? placeholders and ON CONFLICT upserts (SQLite 3.24+).
+	SQLite
+)
+
+// Execer is implemented by *sql.DB, *sql.Tx, and *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Options configures a Store.
+type Options struct {
+	// Dialect is the target database. Defaults to Postgres.
+	Dialect Dialect
+
+	// TablePrefix prefixes all table names. Defaults to "intasend_".
+	TablePrefix string
+}
+
+// Store builds and executes statements for a dialect and table prefix.
+// It holds no connection and is safe for concurrent use.
+type Store struct {
+	dialect Dialect
+	prefix  string
+}
+
+// New creates a Store with the given options.
+func New(opts Options) *Store {
+	if opts.TablePrefix == "" {
+		opts.TablePrefix = DefaultTablePrefix
+	}
+	return &Store{dialect: opts.Dialect, prefix: opts.TablePrefix}
+}
+
+// Table returns the full name of a table, e.g. Table("invoices").
+func (s *Store) Table(name string) string {
+	return s.prefix + name
+}
+
+// column is a column name and its abstract type.
+type column struct {
+	name string
+	typ  string // text, money, time, blob
+}
+
+var (
+	invoiceColumns = []column{
+		{"invoice_id", "text"}, {"state", "text"}, {"provider", "text"}, {"value", "money"},
+		{"account", "text"}, {"api_ref", "text"}, {"failed_reason", "text"},
+		{"created_at", "time"}, {"updated_at", "time"},
+	}
+	payoutColumns = []column{
+		{"tracking_id", "text"}, {"request_ref_id", "text"}, {"status", "text"}, {"name", "text"},
+		{"account", "text"}, {"amount", "money"}, {"narrative", "text"}, {"bank_code", "text"},
+		{"account_type", "text"}, {"account_reference", "text"}, {"failed_reason", "text"},
+		{"created_at", "time"}, {"updated_at", "time"},
+	}
+	chargebackColumns = []column{
+		{"chargeback_id", "text"}, {"invoice_id", "text"}, {"amount", "money"}, {"status", "text"},
+		{"reason", "text"}, {"reason_details", "text"}, {"created_at", "time"}, {"updated_at", "time"},
+	}
+	eventColumns = []column{
+		{"event_id", "text"}, {"event_type", "text"}, {"resource_id", "text"},
+		{"payload", "blob"}, {"received_at", "time"},
+	}
+)
+
+// table describes one persisted resource.
+type table struct {
+	name    string
+	columns []column
+	keys    []string
+	index   string
+}
+
+var tables = []table{
+	{"invoices", invoiceColumns, []string{"invoice_id"}, "api_ref"},
+	{"payouts", payoutColumns, []string{"tracking_id", "request_ref_id"}, ""},
+	{"chargebacks", chargebackColumns, []string{"chargeback_id"}, "invoice_id"},
+	{"events", eventColumns, []string{"event_id"}, "resource_id"},
+}
+
+// Schema returns the CREATE TABLE and CREATE INDEX statements for the store.
+// Statements use IF NOT EXISTS and can be run repeatedly.
+func (s *Store) Schema() []string {
+	var stmts []string
+	for _, t := range tables {
+		defs := make([]string, 0, len(t.columns)+1)
+		for _, c := range t.columns {
+			def := c.name + " " + s.columnType(c.typ)
+			if c.typ == "text" {
+				def += " NOT NULL DEFAULT ''"
+			}
+			defs = append(defs, def)
+		}
+		defs = append(defs, "PRIMARY KEY ("+strings.Join(t.keys, ", ")+")")
+		stmts = append(stmts, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)",
+			s.Table(t.name), strings.Join(defs, ",\n\t")))
+
+		if t.index != "" {
+			stmts = append(stmts, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_%s_idx ON %s (%s)",
+				s.Table(t.name), t.index, s.Table(t.name), t.index))
+		}
+	}
+	return stmts
+}
+
+// Migrate creates the tables and indexes if they do not exist.
+func (s *Store) Migrate(ctx context.Context, db Execer) error {
+	for _, stmt := range s.Schema() {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("intasendstore: migrate: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpsertInvoice inserts or updates an invoice row.
+func (s *Store) UpsertInvoice(ctx context.Context, db Execer, row InvoiceRow) error {
+	_, err := db.ExecContext(ctx, s.upsertSQL("invoices", invoiceColumns, []string{"invoice_id"}),
+		row.InvoiceID, row.State, row.Provider, row.Value, row.Account, row.APIRef,
+		row.FailedReason, row.CreatedAt, row.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("intasendstore: upsert invoice %s: %w", row.InvoiceID, err)
+	}
+	return nil
+}
+
+// UpsertPayout inserts or updates a payout transaction row.
+func (s *Store) UpsertPayout(ctx context.Context, db Execer, row PayoutRow) error {
+	_, err := db.ExecContext(ctx, s.upsertSQL("payouts", payoutColumns, []string{"tracking_id", "request_ref_id"}),
+		row.TrackingID, row.RequestRefID, row.Status, row.Name, row.Account, row.Amount,
+		row.Narrative, row.BankCode, row.AccountType, row.AccountReference, row.FailedReason,
+		row.CreatedAt, row.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("intasendstore: upsert payout %s/%s: %w", row.TrackingID, row.RequestRefID, err)
+	}
+	return nil
+}
+
+// UpsertChargeback inserts or updates a chargeback row.
+func (s *Store) UpsertChargeback(ctx context.Context, db Execer, row ChargebackRow) error {
+	_, err := db.ExecContext(ctx, s.upsertSQL("chargebacks", chargebackColumns, []string{"chargeback_id"}),
+		row.ChargebackID, row.InvoiceID, row.Amount, row.Status, row.Reason,
+		row.ReasonDetails, row.CreatedAt, row.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("intasendstore: upsert chargeback %s: %w", row.ChargebackID, err)
+	}
+	return nil
+}
+
+// InsertEvent records an event. It reports false without error when an event
+// with the same EventID already exists, which makes it suitable for
+// deduplicating at-least-once deliveries.
+func (s *Store) InsertEvent(ctx context.Context, db Execer, row EventRow) (bool, error) {
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (event_id) DO NOTHING",
+		s.Table("events"), columnNames(eventColumns), s.placeholders(len(eventColumns)))
+	res, err := db.ExecContext(ctx, query, row.EventID, row.EventType, row.ResourceID, row.Payload, row.ReceivedAt)
+	if err != nil {
+		return false, fmt.Errorf("intasendstore: insert event %s: %w", row.EventID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("intasendstore: insert event %s: %w", row.EventID, err)
+	}
+	return n > 0, nil
+}
+
+// upsertSQL builds an INSERT ... ON CONFLICT DO UPDATE statement that only
+// replaces rows whose updated_at is not newer than the incoming row.
+func (s *Store) upsertSQL(name string, cols []column, keys []string) string {
+	isKey := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		isKey[k] = true
+	}
+	var sets []string
+	for _, c := range cols {
+		if !isKey[c.name] {
+			sets = append(sets, fmt.Sprintf("%s = excluded.%s", c.name, c.name))
+		}
+	}
+	tbl := s.Table(name)
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s WHERE %s.updated_at <= excluded.updated_at",
+		tbl, columnNames(cols), s.placeholders(len(cols)), strings.Join(keys, ", "), strings.Join(sets, ", "), tbl)
+}
+
+// placeholders returns n bind parameters in the dialect's syntax.
+func (s *Store) placeholders(n int) string {
+	ps := make([]string, n)
+	for i := range ps {
+		if s.dialect == Postgres {
+			ps[i] = fmt.Sprintf("$%d", i+1)
+		} else {
+			ps[i] = "?"
+		}
+	}
+	return strings.Join(ps, ", ")
+}
+
+// columnType maps an abstract column type to the dialect's SQL type.
+func (s *Store) columnType(typ string) string {
+	switch typ {
+	case "money":
+		return "NUMERIC(18, 2)"
+	case "time":
+		if s.dialect == Postgres {
+			return "TIMESTAMPTZ"
+		}
+		return "TIMESTAMP"
+	case "blob":
+		if s.dialect == Postgres {
+			return "BYTEA"
+		}
+		return "BLOB"
+	default:
+		return "TEXT"
+	}
+}
+
+func columnNames(cols []column) string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.name
+	}
+	return strings.Join(names, ", ")
+}
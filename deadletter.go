@@ -0,0 +1,82 @@
+package intasend
+
+import "time"
+
+// DeadLetterSource identifies the background component that gave up on an
+// item.
+type DeadLetterSource string
+
+const (
+	// DeadLetterCollectQueue is a CollectQueue request that failed
+	// permanently or was abandoned. Item is the *STKPushRequest.
+	DeadLetterCollectQueue DeadLetterSource = "collect_queue"
+
+	// DeadLetterTransactionStream is a TransactionStream stopped by a
+	// failed poll. Item is a StreamPosition to resume from.
+	DeadLetterTransactionStream DeadLetterSource = "transaction_stream"
+
+	// DeadLetterWebhookRelay is a webhook.Relay delivery that could not be
+	// made. Item is the webhook.Delivery.
+	DeadLetterWebhookRelay DeadLetterSource = "webhook_relay"
+
+	// DeadLetterPayoutTracking is an initiated payout that could not be
+	// tokenized or saved to the TrackingStore. Item is the *TrackedPayout,
+	// not tokenized if tokenization was what failed.
+	DeadLetterPayoutTracking DeadLetterSource = "payout_tracking"
+)
+
+// DeadLetter is an item a background component stopped working on after a
+// terminal failure.
+type DeadLetter struct {
+	Source DeadLetterSource
+
+	// Item is what was being processed; its type depends on Source.
+	Item interface{}
+
+	// Attempts is the number of attempts made, where the source counts
+	// them.
+	Attempts int
+
+	// Err is the failure.
+	Err error
+
+	// Time is when the item was given up on.
+	Time time.Time
+}
+
+// DeadLetterHandler receives items that background components gave up on,
+// so a payment being tracked is never dropped silently. Store them for
+// replay or alert on them. Handlers are called from the components'
+// goroutines and must be safe for concurrent use.
+type DeadLetterHandler interface {
+	HandleDeadLetter(d DeadLetter)
+}
+
+// DeadLetterFunc adapts a function to a DeadLetterHandler.
+type DeadLetterFunc func(d DeadLetter)
+
+// HandleDeadLetter implements DeadLetterHandler.
+func (f DeadLetterFunc) HandleDeadLetter(d DeadLetter) {
+	f(d)
+}
+
+// StreamPosition is where a TransactionStream stopped; pass Cursor as since
+// to StreamTransactions to resume.
+type StreamPosition struct {
+	WalletID string
+	Cursor   time.Time
+}
+
+// deadLetter reports an item to the client's dead-letter handler, if any.
+func (c *Client) deadLetter(source DeadLetterSource, item interface{}, attempts int, err error) {
+	if c.deadLetters == nil {
+		return
+	}
+	c.deadLetters.HandleDeadLetter(DeadLetter{
+		Source:   source,
+		Item:     item,
+		Attempts: attempts,
+		Err:      err,
+		Time:     time.Now(),
+	})
+}
@@ -0,0 +1,383 @@
+package intasend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// harRedacted replaces secrets in recorded traffic.
+const harRedacted = "[REDACTED]"
+
+// harSensitiveHeaders are the headers whose values are never recorded.
+var harSensitiveHeaders = map[string]bool{
+	headerAuthorization:                              true,
+	http.CanonicalHeaderKey(headerPublicAPIKey):      true,
+	http.CanonicalHeaderKey(headerIntaSendPublicKey): true,
+	"Cookie":     true,
+	"Set-Cookie": true,
+}
+
+// SetDebug switches debug logging of requests and responses on or off,
+// taking effect for requests started afterwards. It is safe to call while
+// the client is in use, so verbose logging can be enabled on a live
+// service, for example from an admin endpoint, without a restart. Debug
+// logs include request and response bodies; switch it off again once done.
+//
+// Example:
+//
+//	http.HandleFunc("/admin/intasend-debug", func(w http.ResponseWriter, r *http.Request) {
+//	    client.SetDebug(r.URL.Query().Get("on") == "1")
+//	})
+func (c *Client) SetDebug(debug bool) {
+	c.debug.Store(debug)
+}
+
+// DebugEnabled reports whether debug logging is on.
+func (c *Client) DebugEnabled() bool {
+	return c.debug.Load()
+}
+
+// DebugService switches troubleshooting aids on and off on a running
+// client.
+type DebugService struct {
+	client *Client
+	har    harRecorder
+}
+
+// StartHAR starts recording every request the client sends, including
+// retries, redirects, and failed connections, as a HAR 1.2 file at path,
+// for sharing with IntaSend support. The file is created immediately and
+// written by StopHAR.
+//
+// Recordings are sanitized like debug logs: the Authorization, API key,
+// and cookie headers, headers set with WithHeaders or WithHeader, and
+// personal data query parameters such as "email" are replaced with
+// "[REDACTED]", and API keys, email addresses, and phone numbers in bodies
+// are masked. With WithTokenizer, personal data fields in bodies are
+// replaced by their tokens instead. Review the file before sending it all
+// the same: free-text fields such as names in narratives are kept. The file
+// is created readable only by its owner. Entries are held in memory until
+// StopHAR, so keep recordings short.
+//
+// StartHAR returns ErrHARInProgress if a recording is already running.
+//
+// Example:
+//
+//	if err := client.Debug().StartHAR("/tmp/intasend.har"); err != nil {
+//	    return err
+//	}
+//	defer client.Debug().StopHAR()
+func (s *DebugService) StartHAR(path string) error {
+	return s.har.start(path)
+}
+
+// StopHAR stops the recording started by StartHAR and writes the file. It
+// does nothing if no recording is running.
+func (s *DebugService) StopHAR() error {
+	return s.har.stop()
+}
+
+// harRecorder collects HAR entries while a recording is running.
+type harRecorder struct {
+	active  int32 // read atomically so idle clients skip the lock
+	mu      sync.Mutex
+	file    *os.File
+	entries []harEntry
+}
+
+func (r *harRecorder) start(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file != nil {
+		return ErrHARInProgress
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) // #nosec G304 -- path is chosen by the operator
+	if err != nil {
+		return fmt.Errorf("intasend: start HAR recording: %w", err)
+	}
+	r.file, r.entries = f, nil
+	atomic.StoreInt32(&r.active, 1)
+	return nil
+}
+
+func (r *harRecorder) stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	atomic.StoreInt32(&r.active, 0)
+	f, entries := r.file, r.entries
+	r.file, r.entries = nil, nil
+	if entries == nil {
+		entries = []harEntry{}
+	}
+
+	doc := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "intasend-go", Version: Version},
+		Entries: entries,
+	}}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(doc)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("intasend: write HAR recording: %w", err)
+	}
+	return nil
+}
+
+func (r *harRecorder) recording() bool {
+	return atomic.LoadInt32(&r.active) == 1
+}
+
+func (r *harRecorder) add(e harEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file != nil {
+		r.entries = append(r.entries, e)
+	}
+}
+
+// harTransport records the traffic of the round tripper it wraps while a
+// recording is running.
+type harTransport struct {
+	base   http.RoundTripper
+	rec    *harRecorder
+	redact func(ctx context.Context, body []byte) string
+
+	// custom holds the names of the headers set with WithHeaders, which may
+	// carry gateway credentials.
+	custom map[string]bool
+
+	// maxBody is the most response body bytes read; see
+	// WithMaxResponseBytes. Zero means no limit.
+	maxBody int64
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.rec.recording() {
+		return t.base.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			_ = body.Close() // #nosec G104 -- in-memory body
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	custom := t.customHeaders(req)
+	entry := harEntry{
+		StartedDateTime: start.UTC().Format(time.RFC3339Nano),
+		Request:         harRequestOf(req, reqBody, custom, t.redact),
+	}
+	if err != nil {
+		entry.Time = harMillis(time.Since(start))
+		entry.Timings = harTimings{Wait: entry.Time}
+		entry.Response = harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+		entry.Error = err.Error()
+		t.rec.add(entry)
+		return nil, err
+	}
+
+	// Read one byte past the limit, as doRequest does, so an oversized
+	// response is still rejected there without being held in memory.
+	var body io.Reader = resp.Body
+	if t.maxBody > 0 {
+		body = io.LimitReader(resp.Body, t.maxBody+1)
+	}
+	respBody, readErr := io.ReadAll(body)
+	_ = resp.Body.Close() // #nosec G104 -- error on close is not critical
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(respBody), errReader{readErr}))
+	entry.Time = harMillis(time.Since(start))
+	entry.Timings = harTimings{Wait: entry.Time}
+	entry.Response = harResponseOf(req.Context(), resp, respBody, custom, t.redact)
+	if readErr != nil {
+		entry.Error = readErr.Error()
+	}
+	t.rec.add(entry)
+	return resp, nil
+}
+
+// customHeaders returns the names of the headers set on req with
+// WithHeaders or WithHeader, other than those the SDK manages.
+func (t *harTransport) customHeaders(req *http.Request) map[string]bool {
+	ro := requestOptionsFromContext(req.Context())
+	if ro == nil || len(ro.header) == 0 {
+		return t.custom
+	}
+	names := make(map[string]bool, len(t.custom)+len(ro.header))
+	for k := range t.custom {
+		names[k] = true
+	}
+	for k := range ro.header {
+		if !managedHeader(k) {
+			names[http.CanonicalHeaderKey(k)] = true
+		}
+	}
+	return names
+}
+
+// errReader returns err, or io.EOF when err is nil.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
+
+func harRequestOf(req *http.Request, body []byte, custom map[string]bool, redact func(context.Context, []byte) string) harRequest {
+	h := harRequest{
+		Method:      req.Method,
+		URL:         redactURL(req.URL.String()),
+		HTTPVersion: req.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(req.Header, custom),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			h.QueryString = append(h.QueryString, harNameValue{Name: name, Value: redactQueryValue(name, v)})
+		}
+	}
+	if len(body) > 0 {
+		h.PostData = &harPostData{
+			MimeType: req.Header.Get(headerContentType),
+			Text:     redact(req.Context(), body),
+		}
+	}
+	return h
+}
+
+func harResponseOf(ctx context.Context, resp *http.Response, body []byte, custom map[string]bool, redact func(context.Context, []byte) string) harResponse {
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(resp.Header, custom),
+		Content: harContent{
+			Size:     len(body),
+			MimeType: resp.Header.Get(headerContentType),
+			Text:     redact(ctx, body),
+		},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+// harHeaders converts h to HAR headers, redacting sensitive values and
+// those of the custom headers.
+func harHeaders(h http.Header, custom map[string]bool) []harNameValue {
+	out := make([]harNameValue, 0, len(h))
+	for name, values := range h {
+		key := http.CanonicalHeaderKey(name)
+		for _, v := range values {
+			if harSensitiveHeaders[key] || custom[key] {
+				v = harRedacted
+			}
+			out = append(out, harNameValue{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+func harMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// HAR 1.2 document types; see http://www.softwareishard.com/blog/har-12-spec/.
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+
+	// Error is a custom field holding the transport error, if any.
+	Error string `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
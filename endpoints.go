@@ -0,0 +1,64 @@
+package intasend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+//go:generate go run ./internal/cmd/genendpoints
+
+// Endpoint is an IntaSend API route wrapped by the SDK.
+type Endpoint struct {
+	// Method is the HTTP method, e.g. "POST".
+	Method string
+
+	// Path is the route relative to the base URL, with resource IDs shown
+	// as ":id", e.g. "/wallets/:id/transactions/".
+	Path string
+
+	// SDKMethods lists the methods that call the route directly, e.g.
+	// "Collection().MPesaSTKPush".
+	SDKMethods []string
+}
+
+// Endpoints returns the API routes the SDK wraps, sorted by path. The list
+// is generated from the service code, so comparing it with IntaSend's
+// published API shows which routes still need Client.Do.
+//
+// Example:
+//
+//	for _, e := range intasend.Endpoints() {
+//	    fmt.Println(e.Method, e.Path, e.SDKMethods)
+//	}
+func Endpoints() []Endpoint {
+	out := make([]Endpoint, len(endpoints))
+	for i, e := range endpoints {
+		e.SDKMethods = append([]string(nil), e.SDKMethods...)
+		out[i] = e
+	}
+	return out
+}
+
+// Do sends an authenticated request to any API path, with the same retries,
+// failover, and metrics as the typed methods. body, if non-nil, is encoded
+// as JSON and the response is decoded into result, if non-nil. Use it for
+// routes the SDK does not wrap yet; see Endpoints.
+//
+// Example:
+//
+//	var out map[string]interface{}
+//	err := client.Do(ctx, http.MethodGet, "/some/new/route/", nil, &out)
+func (c *Client) Do(ctx context.Context, method, path string, body, result interface{}, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("intasend: path %q must start with /", path)
+	}
+	return c.doRequest(ctx, &requestConfig{
+		method:       strings.ToUpper(method),
+		path:         path,
+		body:         body,
+		result:       result,
+		requiresAuth: true,
+	})
+}
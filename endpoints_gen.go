@@ -0,0 +1,23 @@
+// Code generated by internal/cmd/genendpoints; DO NOT EDIT.
+
+package intasend
+
+var endpoints = []Endpoint{
+	{Method: "GET", Path: "/chargebacks/", SDKMethods: []string{"Refund().List", "Refund().ListAll"}},
+	{Method: "POST", Path: "/chargebacks/", SDKMethods: []string{"Refund().Create"}},
+	{Method: "GET", Path: "/chargebacks/:id/", SDKMethods: []string{"Refund().Get"}},
+	{Method: "POST", Path: "/checkout/", SDKMethods: []string{"Checkout().Create", "Collection().Charge", "Wallet().FundCheckout"}},
+	{Method: "POST", Path: "/payment/mpesa-stk-push/", SDKMethods: []string{"Collection().MPesaSTKPush", "Wallet().FundMPesa"}},
+	{Method: "POST", Path: "/payment/status/", SDKMethods: []string{"Checkout().CheckStatus", "Collection().Status"}},
+	{Method: "GET", Path: "/paymentlinks/", SDKMethods: []string{"PaymentLink().List", "PaymentLink().ListAll"}},
+	{Method: "POST", Path: "/paymentlinks/", SDKMethods: []string{"PaymentLink().Create"}},
+	{Method: "GET", Path: "/paymentlinks/:id/", SDKMethods: []string{"PaymentLink().Get"}},
+	{Method: "POST", Path: "/send-money/approve/", SDKMethods: []string{"Payout().Approve"}},
+	{Method: "POST", Path: "/send-money/initiate/", SDKMethods: []string{"Payout().Initiate"}},
+	{Method: "POST", Path: "/send-money/status/", SDKMethods: []string{"Payout().Status"}},
+	{Method: "GET", Path: "/wallets/", SDKMethods: []string{"Wallet().List", "Wallet().ListAll"}},
+	{Method: "POST", Path: "/wallets/", SDKMethods: []string{"Wallet().Create"}},
+	{Method: "GET", Path: "/wallets/:id/", SDKMethods: []string{"Wallet().Get"}},
+	{Method: "POST", Path: "/wallets/:id/intra_transfer/", SDKMethods: []string{"Wallet().IntraTransfer"}},
+	{Method: "GET", Path: "/wallets/:id/transactions/", SDKMethods: []string{"Wallet().ListTransactions", "Wallet().Transactions"}},
+}
@@ -0,0 +1,95 @@
+package intasend
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseEnum returns the value in values matching s, ignoring case and
+// surrounding space, or an error naming kind.
+func parseEnum(kind, s string, values ...string) (string, error) {
+	t := strings.TrimSpace(s)
+	for _, v := range values {
+		if strings.EqualFold(t, v) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s %q (want one of %s)", ErrInvalidEnumValue, kind, s, strings.Join(values, ", "))
+}
+
+// isEnum reports whether s is exactly one of values.
+func isEnum(s string, values ...string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	providerValues       = []string{string(ProviderMPesaB2C), string(ProviderMPesaB2B), string(ProviderPesaLink), string(ProviderIntaSend), string(ProviderAirtime)}
+	approvalStatusValues = []string{string(ApprovalRequired), string(ApprovalNotRequired)}
+	accountTypeValues    = []string{string(AccountTypePayBill), string(AccountTypeTillNumber)}
+	tariffValues         = []string{string(TariffBusinessPays), string(TariffCustomerPays)}
+	refundReasonValues   = []string{
+		string(RefundReasonServiceUnavailable), string(RefundReasonDuplicatePayment), string(RefundReasonFraudulent),
+		string(RefundReasonCustomerRequest), string(RefundReasonOther),
+	}
+)
+
+// IsValid reports whether p is one of the Provider constants.
+func (p Provider) IsValid() bool { return isEnum(string(p), providerValues...) }
+
+// ParseProvider parses a provider such as "mpesa-b2c", ignoring case. It
+// returns an error matching ErrInvalidEnumValue for unknown providers.
+func ParseProvider(s string) (Provider, error) {
+	v, err := parseEnum("provider", s, providerValues...)
+	return Provider(v), err
+}
+
+// IsValid reports whether a is one of the ApprovalStatus constants.
+func (a ApprovalStatus) IsValid() bool { return isEnum(string(a), approvalStatusValues...) }
+
+// ParseApprovalStatus parses "YES" or "NO", ignoring case.
+func ParseApprovalStatus(s string) (ApprovalStatus, error) {
+	v, err := parseEnum("approval status", s, approvalStatusValues...)
+	return ApprovalStatus(v), err
+}
+
+// IsValid reports whether a is one of the AccountType constants.
+func (a AccountType) IsValid() bool { return isEnum(string(a), accountTypeValues...) }
+
+// ParseAccountType parses "PayBill" or "TillNumber", ignoring case.
+func ParseAccountType(s string) (AccountType, error) {
+	v, err := parseEnum("account type", s, accountTypeValues...)
+	return AccountType(v), err
+}
+
+// IsValid reports whether t is one of the Tariff constants.
+func (t Tariff) IsValid() bool { return isEnum(string(t), tariffValues...) }
+
+// ParseTariff parses "BUSINESS-PAYS" or "CUSTOMER-PAYS", ignoring case.
+func ParseTariff(s string) (Tariff, error) {
+	v, err := parseEnum("tariff", s, tariffValues...)
+	return Tariff(v), err
+}
+
+// IsValid reports whether r is one of the RefundReason constants.
+func (r RefundReason) IsValid() bool { return isEnum(string(r), refundReasonValues...) }
+
+// ParseRefundReason parses a refund reason such as "customer_request",
+// ignoring case.
+func ParseRefundReason(s string) (RefundReason, error) {
+	v, err := parseEnum("refund reason", s, refundReasonValues...)
+	return RefundReason(v), err
+}
+
+// validateEnum returns an ErrInvalidEnumValue error for a set value that
+// is not valid.
+func validateEnum(kind, value string, valid bool) error {
+	if value == "" || valid {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %q", ErrInvalidEnumValue, kind, value)
+}
@@ -1,16 +1,58 @@
 package intasend
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
 )
 
 // Sentinel errors for common error conditions.
 var (
-	ErrMissingPublishableKey = errors.New("intasend: publishable key is required")
-	ErrMissingSecretKey      = errors.New("intasend: secret key is required")
-	ErrInvalidEnvironment    = errors.New("intasend: could not determine environment from keys")
-	ErrNoKeysProvided        = errors.New("intasend: at least one API key must be provided")
+	ErrMissingPublishableKey  = errors.New("intasend: publishable key is required")
+	ErrMissingSecretKey       = errors.New("intasend: secret key is required")
+	ErrInvalidEnvironment     = errors.New("intasend: could not determine environment from keys")
+	ErrNoKeysProvided         = errors.New("intasend: at least one API key must be provided")
+	ErrInvalidRefundAmount    = errors.New("intasend: refund amount must be positive")
+	ErrInvalidAmount          = errors.New("intasend: amount must be a positive number with at most 2 decimal places")
+	ErrInvalidPaymentMethods  = errors.New("intasend: invalid payment method selection")
+	ErrUnsupportedLocale      = errors.New("intasend: unsupported locale")
+	ErrResponseTooLarge       = errors.New("intasend: response body exceeds size limit")
+	ErrRefundExceedsBalance   = errors.New("intasend: refund amount exceeds remaining refundable amount")
+	ErrQueueClosed            = errors.New("intasend: collect queue is closed")
+	ErrRecipientThrottled     = errors.New("intasend: recipient throttled")
+	ErrNotTracked             = errors.New("intasend: payout is not tracked")
+	ErrNoTrackingStore        = errors.New("intasend: no tracking store configured")
+	ErrNotResendable          = errors.New("intasend: invoice cannot be resent")
+	ErrReasonDetailsRequired  = errors.New("intasend: reason details are required for refund reason OTHER")
+	ErrInvalidSyncToken       = errors.New("intasend: invalid sync token")
+	ErrInvalidAPIRef          = errors.New("intasend: invalid api_ref metadata")
+	ErrInvalidSignature       = errors.New("intasend: invalid checkout signature")
+	ErrSignatureExpired       = errors.New("intasend: checkout signature expired")
+	ErrNotSandbox             = errors.New("intasend: operation requires sandbox keys")
+	ErrNoSandboxTag           = errors.New("intasend: no sandbox tag configured")
+	ErrIncompleteRequest      = errors.New("intasend: request is missing required fields")
+	ErrRedirectRefused        = errors.New("intasend: redirect refused")
+	ErrInsufficientBalance    = errors.New("intasend: insufficient wallet balance")
+	ErrCertificatePinMismatch = errors.New("intasend: certificate pin mismatch")
+	ErrHARInProgress          = errors.New("intasend: HAR recording already in progress")
+	ErrNotPaid                = errors.New("intasend: invoice is not paid")
+	ErrNoLinkNotifier         = errors.New("intasend: no payment link notifier configured")
+	ErrNilRequest             = errors.New("intasend: request must not be nil")
+	ErrNilClient              = errors.New("intasend: service is not bound to a client; create one with New")
+	ErrInvalidEnumValue       = errors.New("intasend: invalid enum value")
+	ErrEnvironmentMismatch    = errors.New("intasend: keys are for a different environment")
+	ErrWaitTimeout            = errors.New("intasend: timed out waiting for a final status")
+	ErrInvalidBankAccount     = errors.New("intasend: invalid bank pay bill or account number")
+	ErrSecretKeyNotAllowed    = errors.New("intasend: a public client must not have a secret key")
+	ErrNoRateProvider         = errors.New("intasend: no rate provider configured")
+	ErrInvalidPhoneNumber     = errors.New("intasend: invalid phone number")
+	ErrInvalidCurrency        = errors.New("intasend: invalid currency code")
 )
 
 // APIError represents an error returned by the IntaSend API.
@@ -32,6 +74,41 @@ type APIError struct {
 
 	// RequestID is the unique request identifier for debugging.
 	RequestID string `json:"request_id,omitempty"`
+
+	// Headers holds the response's diagnostic headers, such as CF-Ray and
+	// X-Request-ID. IntaSend support asks for these when investigating
+	// blocked or failed requests.
+	Headers http.Header `json:"-"`
+}
+
+// diagnosticHeaders are the response headers kept on APIError.
+var diagnosticHeaders = []string{
+	"CF-Ray",
+	"CF-Cache-Status",
+	"X-Request-ID",
+	"X-Correlation-ID",
+	"X-Amzn-Trace-ID",
+	"Retry-After",
+	"Server",
+}
+
+// captureDiagnosticHeaders copies the diagnostic headers present in h.
+func captureDiagnosticHeaders(h http.Header) http.Header {
+	var out http.Header
+	for _, name := range diagnosticHeaders {
+		if v := h.Values(name); len(v) > 0 {
+			if out == nil {
+				out = make(http.Header, len(diagnosticHeaders))
+			}
+			out[http.CanonicalHeaderKey(name)] = append([]string(nil), v...)
+		}
+	}
+	return out
+}
+
+// RayID returns the Cloudflare Ray ID of the failed response, if any.
+func (e *APIError) RayID() string {
+	return e.Headers.Get("CF-Ray")
 }
 
 // Error implements the error interface.
@@ -60,6 +137,12 @@ func (e *APIError) IsValidationError() bool {
 	return e.HTTPStatusCode == 400 && len(e.Errors) > 0
 }
 
+// RetryAfter returns how long the API asked to wait before retrying, from
+// the response's Retry-After header, or zero if it did not say.
+func (e *APIError) RetryAfter() time.Duration {
+	return parseRetryAfter(e.Headers.Get("Retry-After"), time.Now())
+}
+
 // IsRateLimited returns true if the request was rate limited.
 func (e *APIError) IsRateLimited() bool {
 	return e.HTTPStatusCode == 429
@@ -69,6 +152,10 @@ func (e *APIError) IsRateLimited() bool {
 type NetworkError struct {
 	Err     error
 	Message string
+
+	// sent is set when this or an earlier attempt of the request got past
+	// connecting, so IntaSend may have received it.
+	sent bool
 }
 
 // Error implements the error interface.
@@ -81,6 +168,31 @@ func (e *NetworkError) Unwrap() error {
 	return e.Err
 }
 
+// IsTimeout returns true if the request timed out waiting for IntaSend,
+// as opposed to failing to reach it at all.
+func (e *NetworkError) IsTimeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e.Err, &netErr) && netErr.Timeout()
+}
+
+// IsConnRefused returns true if the connection to IntaSend was refused,
+// which usually indicates a network partition or proxy failure.
+func (e *NetworkError) IsConnRefused() bool {
+	return errors.Is(e.Err, syscall.ECONNREFUSED)
+}
+
+// isStaleConnection reports whether err comes from reusing a keep-alive
+// connection the server had already closed.
+func isStaleConnection(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	return strings.Contains(err.Error(), "server closed idle connection")
+}
+
 // IsAPIError checks if an error is an IntaSend API error.
 func IsAPIError(err error) bool {
 	var apiErr *APIError
@@ -0,0 +1,211 @@
+package intasend
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Event is a lifecycle event published by the client. Subscribers switch on
+// the concrete type: RequestStarted, RequestFinished, RequestRetried,
+// RateLimited, PayoutInitiated, PayoutApproved, RefundCreated, or
+// PaymentCompleted.
+type Event interface {
+	// EventName returns a stable name for the event type, such as
+	// "request.finished", suitable for logs and metric labels.
+	EventName() string
+}
+
+// RequestStarted is published before an API request is sent, once it has a
+// slot under WithMaxConcurrentRequests.
+type RequestStarted struct {
+	Method string
+
+	// Endpoint is the request path with resource IDs replaced by ":id".
+	Endpoint string
+}
+
+// RequestFinished is published when an API request completes, successfully
+// or not, with the same details a MetricsCollector receives.
+type RequestFinished struct {
+	RequestMetrics
+}
+
+// RequestRetried is published before each retry of a request.
+type RequestRetried struct {
+	Method   string
+	Endpoint string
+
+	// Attempt is the retry number, starting at 1.
+	Attempt int
+
+	// Wait is the backoff before the retry is sent.
+	Wait time.Duration
+
+	// Err is the error of the previous attempt.
+	Err error
+}
+
+// RateLimited is published when an attempt is rejected with HTTP 429.
+type RateLimited struct {
+	Method   string
+	Endpoint string
+
+	// RetryAfter is the delay the API asked for, or zero if it gave none.
+	RetryAfter time.Duration
+}
+
+// PayoutInitiated is published when a payout batch has been accepted by
+// the API.
+type PayoutInitiated struct {
+	// Request is the request as sent, with client defaults applied.
+	Request  *InitiateRequest
+	Response *InitiateResponse
+
+	// Actor is the actor set with ContextWithActor, if any.
+	Actor Actor
+}
+
+// PayoutApproved is published when a payout batch has been approved.
+type PayoutApproved struct {
+	Response *ApproveResponse
+
+	// Actor is the actor set with ContextWithActor, if any.
+	Actor Actor
+}
+
+// RefundCreated is published when a refund request has been accepted by
+// the API.
+type RefundCreated struct {
+	Request  *CreateChargebackRequest
+	Response *Chargeback
+
+	// Actor is the actor set with ContextWithActor, if any.
+	Actor Actor
+}
+
+// PaymentCompleted is published whenever a status check finds a COMPLETE
+// invoice, including the checks made while waiting for an STK push. The
+// same invoice may be reported more than once; deduplicate by InvoiceID.
+type PaymentCompleted struct {
+	Invoice *Invoice
+}
+
+// EventName implements Event.
+func (RequestStarted) EventName() string { return "request.started" }
+
+// EventName implements Event.
+func (RequestFinished) EventName() string { return "request.finished" }
+
+// EventName implements Event.
+func (RequestRetried) EventName() string { return "request.retried" }
+
+// EventName implements Event.
+func (RateLimited) EventName() string { return "request.rate_limited" }
+
+// EventName implements Event.
+func (PayoutInitiated) EventName() string { return "payout.initiated" }
+
+// EventName implements Event.
+func (PayoutApproved) EventName() string { return "payout.approved" }
+
+// EventName implements Event.
+func (RefundCreated) EventName() string { return "refund.created" }
+
+// EventName implements Event.
+func (PaymentCompleted) EventName() string { return "payment.completed" }
+
+// EventHandler receives events. The context is the one passed to the call
+// that produced the event, so values such as log fields are available.
+type EventHandler func(ctx context.Context, e Event)
+
+// EventService publishes the client's lifecycle events to subscribers, so
+// metrics, audit logging, and alerting attach in one place.
+type EventService struct {
+	client *Client
+
+	mu   sync.Mutex // serializes Subscribe and unsubscribe
+	subs atomic.Pointer[[]*subscription]
+}
+
+type subscription struct {
+	handler EventHandler
+}
+
+// Subscribe registers h for every event and returns a function that
+// removes it. Handlers run synchronously on the goroutine that produced the
+// event, so they must be safe for concurrent use and return quickly; hand
+// slow work off to another goroutine. A panicking handler is logged and
+// does not affect the call in progress.
+//
+// Example:
+//
+//	unsubscribe := client.Events().Subscribe(func(ctx context.Context, e intasend.Event) {
+//	    switch e := e.(type) {
+//	    case intasend.RateLimited:
+//	        alerts.Warn("IntaSend rate limit on %s", e.Endpoint)
+//	    case intasend.PayoutInitiated:
+//	        audit.Record(ctx, "payout", e.Response.TrackingID)
+//	    }
+//	})
+//	defer unsubscribe()
+func (s *EventService) Subscribe(h EventHandler) (unsubscribe func()) {
+	sub := &subscription{handler: h}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var subs []*subscription
+	if cur := s.subs.Load(); cur != nil {
+		subs = append(subs, *cur...)
+	}
+	subs = append(subs, sub)
+	s.subs.Store(&subs)
+
+	var once sync.Once
+	return func() { once.Do(func() { s.remove(sub) }) }
+}
+
+func (s *EventService) remove(sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.subs.Load()
+	if cur == nil {
+		return
+	}
+	subs := make([]*subscription, 0, len(*cur))
+	for _, other := range *cur {
+		if other != sub {
+			subs = append(subs, other)
+		}
+	}
+	s.subs.Store(&subs)
+}
+
+// active reports whether anyone is subscribed, so publishers can skip
+// building events nobody receives.
+func (s *EventService) active() bool {
+	if s == nil {
+		return false
+	}
+	cur := s.subs.Load()
+	return cur != nil && len(*cur) > 0
+}
+
+// publish delivers e to every subscriber.
+func (s *EventService) publish(ctx context.Context, e Event) {
+	if !s.active() {
+		return
+	}
+	for _, sub := range *s.subs.Load() {
+		s.deliver(ctx, sub, e)
+	}
+}
+
+func (s *EventService) deliver(ctx context.Context, sub *subscription, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.client.log(ctx, LogLevelError, "event handler panicked", map[string]interface{}{"event": e.EventName(), "panic": r})
+		}
+	}()
+	sub.handler(ctx, e)
+}
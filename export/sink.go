@@ -0,0 +1,155 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Record is one exported row. Columns and Values are parallel slices in a
+// fixed order per Kind.
+type Record struct {
+	// Kind is the resource type, e.g. "wallet_transaction".
+	Kind    string
+	Columns []string
+	Values  []interface{}
+}
+
+// Sink receives exported records. Implementations for columnar formats such
+// as Parquet can be plugged in alongside the CSV and JSON sinks provided here.
+type Sink interface {
+	WriteRecord(rec Record) error
+	Flush() error
+}
+
+// CSVSink writes records as CSV, emitting a header row before the first record.
+// All records written to one CSVSink must share the same columns.
+type CSVSink struct {
+	w       *csv.Writer
+	columns []string
+	resumed bool
+}
+
+// NewCSVSink creates a CSVSink writing to w.
+func NewCSVSink(w io.Writer) *CSVSink {
+	return &CSVSink{w: csv.NewWriter(w)}
+}
+
+// WriteRecord writes rec as a CSV row.
+func (s *CSVSink) WriteRecord(rec Record) error {
+	if s.columns == nil {
+		s.columns = rec.Columns
+		if !s.resumed {
+			if err := s.w.Write(rec.Columns); err != nil {
+				return err
+			}
+		}
+	} else if !equalColumns(s.columns, rec.Columns) {
+		return errors.New("export: CSV records must share the same columns")
+	}
+
+	row := make([]string, len(rec.Values))
+	for i, v := range rec.Values {
+		row[i] = formatValue(v)
+	}
+	return s.w.Write(row)
+}
+
+// Resume implements Resumer: the header row is not written, because the
+// output being appended to already has one.
+func (s *CSVSink) Resume() {
+	s.resumed = true
+}
+
+// Flush writes any buffered rows to the underlying writer.
+func (s *CSVSink) Flush() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// JSONSink writes records as newline-delimited JSON objects with keys in
+// column order, suitable for streaming into data warehouses.
+type JSONSink struct {
+	w   *bufio.Writer
+	buf bytes.Buffer
+}
+
+// NewJSONSink creates a JSONSink writing to w.
+func NewJSONSink(w io.Writer) *JSONSink {
+	return &JSONSink{w: bufio.NewWriter(w)}
+}
+
+// WriteRecord writes rec as one JSON line.
+func (s *JSONSink) WriteRecord(rec Record) error {
+	s.buf.Reset()
+	s.buf.WriteByte('{')
+	for i, col := range rec.Columns {
+		if i > 0 {
+			s.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(col)
+		s.buf.Write(key)
+		s.buf.WriteByte(':')
+		var v interface{}
+		if i < len(rec.Values) {
+			v = rec.Values[i]
+		}
+		if t, ok := v.(time.Time); ok && t.IsZero() {
+			v = nil
+		}
+		val, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("export: encoding %s: %w", col, err)
+		}
+		s.buf.Write(val)
+	}
+	s.buf.WriteString("}\n")
+	_, err := s.w.Write(s.buf.Bytes())
+	return err
+}
+
+// Flush writes any buffered lines to the underlying writer.
+func (s *JSONSink) Flush() error {
+	return s.w.Flush()
+}
+
+// formatValue renders a record value as CSV text.
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(x)
+	case bool:
+		return strconv.FormatBool(x)
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func equalColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
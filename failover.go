@@ -0,0 +1,100 @@
+package intasend
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultFailoverCooldown is how long an unreachable base URL is skipped
+// before it is tried again.
+const DefaultFailoverCooldown = 30 * time.Second
+
+// endpointPool tracks the health of the client's base URLs in priority order.
+type endpointPool struct {
+	urls     []string
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	downUntil []time.Time
+}
+
+func newEndpointPool(urls []string, cooldown time.Duration) *endpointPool {
+	return &endpointPool{urls: urls, cooldown: cooldown, downUntil: make([]time.Time, len(urls))}
+}
+
+// order returns the URLs to try: healthy ones in priority order, then
+// unhealthy ones by how soon they recover.
+func (p *endpointPool) order() []string {
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	healthy := make([]string, 0, len(p.urls))
+	var down []int
+	for i, u := range p.urls {
+		if now.Before(p.downUntil[i]) {
+			down = append(down, i)
+			continue
+		}
+		healthy = append(healthy, u)
+	}
+	sort.SliceStable(down, func(a, b int) bool {
+		return p.downUntil[down[a]].Before(p.downUntil[down[b]])
+	})
+	for _, i := range down {
+		healthy = append(healthy, p.urls[i])
+	}
+	return healthy
+}
+
+// active returns the URL the next request is sent to.
+func (p *endpointPool) active() string {
+	return p.order()[0]
+}
+
+// mark records the outcome of a request against url.
+func (p *endpointPool) mark(url string, up bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, u := range p.urls {
+		if u != url {
+			continue
+		}
+		if up {
+			p.downUntil[i] = time.Time{}
+		} else {
+			p.downUntil[i] = time.Now().Add(p.cooldown)
+		}
+	}
+}
+
+// shouldFailover reports whether a failed request may be repeated against
+// another base URL. GET requests always may. Other requests only fail over
+// when no attempt got past connecting, since nothing was sent; otherwise an
+// earlier attempt may have reached IntaSend, and payments are never
+// submitted twice.
+func shouldFailover(method string, err error) bool {
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		return false
+	}
+	if method == http.MethodGet {
+		return true
+	}
+	return !netErr.sent && isConnectFailure(netErr.Err)
+}
+
+// isConnectFailure reports whether err happened before a connection was
+// made: a failed dial or DNS lookup.
+func isConnectFailure(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
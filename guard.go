@@ -0,0 +1,15 @@
+package intasend
+
+// checkCall reports misuse of a service method as an error instead of a
+// panic, which would otherwise surface far from its cause when the call
+// runs in a goroutine: ErrNilClient for a service not obtained from a
+// Client created with New, and ErrNilRequest for a nil request.
+func (c *Client) checkCall(nilRequest bool) error {
+	if c == nil {
+		return ErrNilClient
+	}
+	if nilRequest {
+		return ErrNilRequest
+	}
+	return nil
+}
@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/emilio-kariuki/intasend-go/backoff"
 )
 
 const (
@@ -23,6 +28,26 @@ const (
 	contentTypeJSON = "application/json"
 )
 
+// bufferPool recycles buffers used for encoding request bodies and reading
+// responses, keeping high-frequency calls such as status polling cheap.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	buf, _ := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	// Avoid pinning unusually large buffers in the pool.
+	if buf.Cap() > 64<<10 {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
 // requestConfig holds configuration for a single request.
 type requestConfig struct {
 	method        string
@@ -33,85 +58,282 @@ type requestConfig struct {
 	publicKeyOnly bool
 }
 
-// doRequest performs an HTTP request with retries and error handling.
+// doRequest performs an HTTP request with retries and error handling,
+// reporting the outcome to the metrics collector and event subscribers and
+// keeping it for SupportBundle. With
+// WithMaxConcurrentRequests, it first waits for a free slot. The call's
+// WithRequestTimeout, or else the client's timeout, bounds the request; the
+// client's timeout only applies if ctx has no deadline of its own.
 func (c *Client) doRequest(ctx context.Context, cfg *requestConfig) error {
-	var bodyBytes []byte
-	var err error
+	if c == nil {
+		return ErrNilClient
+	}
+	if c.inflight != nil {
+		select {
+		case c.inflight <- struct{}{}:
+			defer func() { <-c.inflight }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if ro := requestOptionsFromContext(ctx); ro != nil && ro.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ro.timeout)
+		defer cancel()
+	} else if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
 
+	m := RequestMetrics{Method: cfg.method}
+	observed := c.metrics != nil || c.events.active()
+	if observed {
+		m.Endpoint = endpointLabel(cfg.path)
+		c.events.publish(ctx, RequestStarted{Method: m.Method, Endpoint: m.Endpoint})
+	}
+	start := time.Now()
+	err := c.executeWithFailover(ctx, cfg, &m)
+	m.Duration = time.Since(start)
+	m.Err = err
+	c.recent.add(start, cfg.path, m)
+	if observed {
+		if c.metrics != nil {
+			c.metrics.ObserveRequest(m)
+		}
+		c.events.publish(ctx, RequestFinished{RequestMetrics: m})
+	}
+	return err
+}
+
+// managedHeader reports whether the SDK sets the header itself, so a
+// per-call WithHeader cannot replace it.
+func managedHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case headerAuthorization, headerContentType, headerUserAgent, headerIdempotencyKey,
+		http.CanonicalHeaderKey(headerPublicAPIKey), http.CanonicalHeaderKey(headerIntaSendPublicKey):
+		return true
+	}
+	return false
+}
+
+// executeWithFailover runs the request against the primary base URL, moving
+// on to fallback URLs when it is unreachable. The body is encoded once and
+// shared by every attempt against every base URL.
+func (c *Client) executeWithFailover(ctx context.Context, cfg *requestConfig, m *RequestMetrics) error {
+	ov := environmentFromContext(ctx)
+	if ov != nil {
+		if err := ov.check(cfg); err != nil {
+			return err
+		}
+	}
+
+	var body []byte
 	if cfg.body != nil {
-		bodyBytes, err = json.Marshal(cfg.body)
-		if err != nil {
+		bodyBuf := getBuffer()
+		defer putBuffer(bodyBuf)
+		if err := json.NewEncoder(bodyBuf).Encode(cfg.body); err != nil {
 			return fmt.Errorf("intasend: failed to marshal request body: %w", err)
 		}
+		body = bytes.TrimSuffix(bodyBuf.Bytes(), []byte("\n"))
+	}
+
+	if ov != nil {
+		return c.execute(ctx, cfg, m, ov.env.BaseURL(), body)
+	}
+	if c.endpoints == nil {
+		return c.execute(ctx, cfg, m, c.baseURL, body)
+	}
+
+	var err error
+	for _, baseURL := range c.endpoints.order() {
+		err = c.execute(ctx, cfg, m, baseURL, body)
+		if err == nil {
+			c.endpoints.mark(baseURL, true)
+			return nil
+		}
+		if !shouldFailover(cfg.method, err) || ctx.Err() != nil {
+			return err
+		}
+		c.endpoints.mark(baseURL, false)
+		if c.debug.Load() {
+			c.log(ctx, LogLevelWarn, "endpoint unreachable, failing over", map[string]interface{}{"base_url": baseURL, "error": redactError(err, baseURL+cfg.path)})
+		}
 	}
+	return err
+}
 
-	url := c.baseURL + cfg.path
+// execute runs the request attempts against baseURL with the encoded
+// bodyBytes, recording status and retry counts in m.
+func (c *Client) execute(ctx context.Context, cfg *requestConfig, m *RequestMetrics, baseURL string, bodyBytes []byte) error {
+	respBuf := getBuffer()
+	defer putBuffer(respBuf)
+
+	reqURL := baseURL + cfg.path
+
+	ov := environmentFromContext(ctx)
+	ro := requestOptionsFromContext(ctx)
+	maxRetries := c.maxRetries
+	if ro != nil && ro.setRetries {
+		maxRetries = ro.retries
+	}
 
 	var lastErr error
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
-		if attempt > 0 {
-			waitTime := c.retryWait * time.Duration(1<<(attempt-1))
-			if c.debug {
-				log.Printf("[IntaSend] Retry attempt %d after %v", attempt, waitTime)
+	var retryAfter time.Duration
+	replayed, replayNow := false, false
+	sent := false
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 && !replayNow {
+			m.Retries = attempt
+			waitTime := backoff.Exponential(c.retryWait, 0, attempt)
+			if retryAfter > 0 {
+				// The server said when to come back; if that is past the
+				// deadline, its answer is more useful than a timeout.
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < retryAfter {
+					return lastErr
+				}
+				waitTime = retryAfter
+			}
+			if c.debug.Load() {
+				c.log(ctx, LogLevelDebug, "retrying request", map[string]interface{}{"attempt": attempt, "wait": waitTime, "error": redactError(lastErr, reqURL)})
 			}
+			c.events.publish(ctx, RequestRetried{Method: m.Method, Endpoint: m.Endpoint, Attempt: attempt, Wait: waitTime, Err: lastErr})
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
+				return withLastAttempt(ctx.Err(), lastErr)
 			case <-time.After(waitTime):
 			}
 		}
+		replayNow = false
+		retryAfter = 0
+
+		if c.limiter != nil {
+			if err := c.limiter.wait(ctx); err != nil {
+				return withLastAttempt(err, lastErr)
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return withLastAttempt(err, lastErr)
+		}
 
 		var bodyReader io.Reader
 		if bodyBytes != nil {
 			bodyReader = bytes.NewReader(bodyBytes)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, cfg.method, url, bodyReader)
+		req, err := http.NewRequestWithContext(ctx, cfg.method, reqURL, bodyReader)
 		if err != nil {
 			return fmt.Errorf("intasend: failed to create request: %w", err)
 		}
 
-		req.Header.Set(headerContentType, contentTypeJSON)
-		req.Header.Set(headerUserAgent, c.userAgent)
-
-		if c.publishableKey != "" {
-			req.Header.Set(headerPublicAPIKey, c.publishableKey)
-			req.Header.Set(headerIntaSendPublicKey, c.publishableKey)
+		creds := c.keys()
+		authHeader := creds.authHeader
+		if ov != nil {
+			authHeader = ""
+			if ov.secretKey != "" {
+				authHeader = "Bearer " + ov.secretKey
+			}
 		}
-
-		if cfg.requiresAuth && c.secretKey != "" {
-			req.Header.Set(headerAuthorization, "Bearer "+c.secretKey)
+		req.Header = creds.headers.Clone()
+		if ro != nil {
+			for k, v := range ro.header {
+				if managedHeader(k) {
+					continue
+				}
+				req.Header[k] = append([]string(nil), v...)
+			}
+		}
+		if ov != nil {
+			// Never send the client's keys to another environment.
+			req.Header.Del(headerPublicAPIKey)
+			req.Header.Del(headerIntaSendPublicKey)
+			if ov.publishableKey != "" {
+				req.Header.Set(headerPublicAPIKey, ov.publishableKey)
+				req.Header.Set(headerIntaSendPublicKey, ov.publishableKey)
+			}
+		}
+		if cfg.requiresAuth && authHeader != "" {
+			req.Header.Set(headerAuthorization, authHeader)
+		}
+		if key := idempotencyKeyFromContext(ctx); key != "" {
+			req.Header.Set(headerIdempotencyKey, key)
 		}
 
-		if c.debug {
-			log.Printf("[IntaSend] %s %s", cfg.method, url)
+		if c.debug.Load() {
+			fields := map[string]interface{}{"method": cfg.method, "url": redactURL(reqURL)}
 			if bodyBytes != nil {
-				log.Printf("[IntaSend] Request Body: %s", string(bodyBytes))
+				fields["body"] = c.redactBody(ctx, bodyBytes)
 			}
+			c.log(ctx, LogLevelDebug, "request sent", fields)
 		}
 
 		resp, err := c.httpClient.Do(req)
+		if err == nil || !isConnectFailure(err) {
+			sent = true
+		}
 		if err != nil {
-			lastErr = &NetworkError{Err: err, Message: "request failed"}
-			if c.debug {
-				log.Printf("[IntaSend] Network error: %v", err)
+			var redirectErr *RedirectError
+			if errors.As(err, &redirectErr) {
+				return redirectErr
+			}
+			if errors.Is(err, ErrCertificatePinMismatch) {
+				return &NetworkError{Err: err, Message: "certificate pin mismatch"}
+			}
+			// A stale keep-alive connection fails before the server sees
+			// the request, so an idempotent request is replayed once
+			// straight away instead of spending a retry and its backoff.
+			if !replayed && isIdempotent(cfg.method) && isStaleConnection(err) && ctx.Err() == nil {
+				replayed, replayNow = true, true
+				attempt--
+				if c.debug.Load() {
+					c.log(ctx, LogLevelDebug, "stale connection, replaying request", map[string]interface{}{"error": redactError(err, reqURL)})
+				}
+				continue
+			}
+			m.StatusCode, m.RequestID = 0, ""
+			lastErr = &NetworkError{Err: err, Message: "request failed", sent: sent}
+			if c.debug.Load() {
+				c.log(ctx, LogLevelWarn, "network error", map[string]interface{}{"method": cfg.method, "url": redactURL(reqURL), "error": redactError(err, reqURL)})
 			}
 			continue
 		}
 
-		respBody, err := io.ReadAll(resp.Body)
+		respBuf.Reset()
+		var body io.Reader = resp.Body
+		if c.maxRespBytes > 0 {
+			body = io.LimitReader(resp.Body, c.maxRespBytes+1)
+		}
+		_, err = respBuf.ReadFrom(body)
 		_ = resp.Body.Close() // #nosec G104 -- error on close is not critical
+		respBody := respBuf.Bytes()
+		if err == nil && c.maxRespBytes > 0 && int64(len(respBody)) > c.maxRespBytes {
+			return fmt.Errorf("%w: more than %d bytes from %s %s", ErrResponseTooLarge, c.maxRespBytes, cfg.method, cfg.path)
+		}
 		if err != nil {
-			lastErr = &NetworkError{Err: err, Message: "failed to read response"}
-			if c.debug {
-				log.Printf("[IntaSend] Failed to read response: %v", err)
+			lastErr = &NetworkError{Err: err, Message: "failed to read response", sent: true}
+			if c.debug.Load() {
+				c.log(ctx, LogLevelWarn, "failed to read response", map[string]interface{}{"method": cfg.method, "url": redactURL(reqURL), "error": redactError(err, reqURL)})
 			}
 			continue
 		}
 
-		if c.debug {
-			log.Printf("[IntaSend] Response Status: %d", resp.StatusCode)
-			log.Printf("[IntaSend] Response Body: %s", string(respBody))
+		m.StatusCode = resp.StatusCode
+		m.RequestID = resp.Header.Get("X-Request-ID")
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			m.RateLimited++
+			c.events.publish(ctx, RateLimited{Method: m.Method, Endpoint: m.Endpoint, RetryAfter: retryAfter})
+		}
+
+		if c.debug.Load() {
+			c.log(ctx, LogLevelDebug, "response received", map[string]interface{}{
+				"method": cfg.method,
+				"url":    redactURL(reqURL),
+				"status": resp.StatusCode,
+				"body":   c.redactBody(ctx, respBody),
+			})
 		}
 
 		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -119,6 +341,11 @@ func (c *Client) doRequest(ctx context.Context, cfg *requestConfig) error {
 			if err := json.Unmarshal(respBody, apiErr); err != nil {
 				apiErr.Message = string(respBody)
 			}
+			apiErr.Headers = captureDiagnosticHeaders(resp.Header)
+			if apiErr.RequestID == "" {
+				apiErr.RequestID = apiErr.Headers.Get("X-Request-ID")
+			}
+			m.RequestID = apiErr.RequestID
 
 			// Don't retry client errors (except rate limiting)
 			if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != 429 {
@@ -128,6 +355,10 @@ func (c *Client) doRequest(ctx context.Context, cfg *requestConfig) error {
 			continue
 		}
 
+		if sink := responseHeaderFromContext(ctx); sink != nil {
+			*sink = resp.Header
+		}
+
 		if cfg.result != nil && len(respBody) > 0 {
 			if err := json.Unmarshal(respBody, cfg.result); err != nil {
 				return fmt.Errorf("intasend: failed to unmarshal response: %w", err)
@@ -140,6 +371,15 @@ func (c *Client) doRequest(ctx context.Context, cfg *requestConfig) error {
 	return lastErr
 }
 
+// withLastAttempt returns err joined with the error of the request's last
+// attempt, if there was one, so callers can tell that an attempt was made.
+func withLastAttempt(err, lastErr error) error {
+	if lastErr == nil {
+		return err
+	}
+	return errors.Join(err, lastErr)
+}
+
 // get performs a GET request.
 func (c *Client) get(ctx context.Context, path string, result interface{}) error {
 	return c.doRequest(ctx, &requestConfig{
@@ -172,3 +412,22 @@ func (c *Client) postPublic(ctx context.Context, path string, body, result inter
 		publicKeyOnly: true,
 	})
 }
+
+// isIdempotent reports whether a request with method may be sent twice
+// without side effects.
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// withQuery appends encoded query parameters to path, if any.
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// pageQuery returns the query selecting a results page.
+func pageQuery(page int) url.Values {
+	return url.Values{"page": {strconv.Itoa(page)}}
+}
@@ -24,7 +24,7 @@ package intasend
 import (
 	"fmt"
 	"net/http"
-	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -44,21 +44,104 @@ const (
 	// DefaultRetryWait is the default wait time between retries.
 	DefaultRetryWait = 1 * time.Second
 
+	// DefaultMaxResponseBytes is the default limit on response body size.
+	DefaultMaxResponseBytes = 10 << 20
+
 	// Version is the SDK version.
 	Version = "1.0.0"
 )
 
 // Client is the main IntaSend API client.
+//
+// A Client is safe for concurrent use by multiple goroutines. Its
+// configuration is fixed once New returns and services never modify the
+// request values passed to them, so a single Client should be created and
+// shared across an application. Only the API keys (UpdateKeys), debug
+// logging (SetDebug), the troubleshooting aids under Debug, and event
+// subscriptions can be changed at runtime.
 type Client struct {
+	// Keys set by the options; once New returns, read them through keys().
 	publishableKey string
 	secretKey      string
-	baseURL        string
-	httpClient     *http.Client
-	timeout        time.Duration
-	maxRetries     int
-	retryWait      time.Duration
-	userAgent      string
-	debug          bool
+
+	// creds holds the current keys; see UpdateKeys.
+	creds atomic.Pointer[credentials]
+
+	baseURL      string
+	httpClient   *http.Client
+	timeout      time.Duration
+	maxRetries   int
+	retryWait    time.Duration
+	maxRespBytes int64
+	userAgent    string
+	debug        atomic.Bool
+	logger       Logger
+	metrics      MetricsCollector
+
+	// structuredLogger, if set, receives log records instead of logger;
+	// see WithStructuredLogger.
+	structuredLogger StructuredLogger
+
+	// Defaults applied to outgoing requests.
+	defaultCurrency   string
+	payoutCallbackURL string
+	webhookChallenge  string
+
+	// enforceApproval forces RequiresApproval=YES on every payout.
+	enforceApproval bool
+
+	// approvalNotifier and approvalLink are set by WithApprovalNotifier.
+	approvalNotifier ApprovalNotifier
+	approvalLink     string
+
+	// throttle limits requests per recipient phone; nil when disabled.
+	throttle *recipientThrottle
+
+	// Fallback base URLs and their health; endpoints is nil without fallbacks.
+	fallbackURLs     []string
+	failoverCooldown time.Duration
+	endpoints        *endpointPool
+
+	// trackingStore records initiated payouts; nil when disabled.
+	trackingStore TrackingStore
+
+	// inflight holds a token per request in flight when
+	// WithMaxConcurrentRequests is set; nil means unlimited.
+	inflight chan struct{}
+
+	// limiter spaces requests out when WithRateLimit is set; nil means
+	// unlimited.
+	limiter *rateLimiter
+
+	// deadLetters receives items background components gave up on; see
+	// WithDeadLetterHandler.
+	deadLetters DeadLetterHandler
+
+	// tokenizer replaces personal data before it is persisted; see
+	// WithTokenizer.
+	tokenizer Tokenizer
+
+	// recent keeps the latest requests for SupportBundle.
+	recent recentRequests
+
+	// linkNotifier delivers payment links; see WithLinkNotifier.
+	linkNotifier LinkNotifier
+
+	// rateProvider supplies exchange rates; see WithRateProvider.
+	rateProvider RateProvider
+
+	// Payment link cache lifetimes; see WithPaymentLinkCache.
+	linkCacheTTL      time.Duration
+	linkCacheMaxStale time.Duration
+
+	// extraHeaders are sent with every request; see WithHeaders.
+	extraHeaders http.Header
+
+	// sandboxTag marks resources created with test keys; see WithSandboxTag.
+	sandboxTag string
+
+	// pins are the accepted public key digests; see WithCertificatePinning.
+	pins map[string]bool
 
 	// Services (lazily initialized)
 	collection  *CollectionService
@@ -67,6 +150,9 @@ type Client struct {
 	refund      *RefundService
 	checkout    *CheckoutService
 	paymentLink *PaymentLinkService
+	sandbox     *SandboxService
+	debugging   *DebugService
+	events      *EventService
 }
 
 // New creates a new IntaSend API client with the given options.
@@ -82,10 +168,14 @@ type Client struct {
 //	)
 func New(opts ...Option) (*Client, error) {
 	c := &Client{
-		timeout:    DefaultTimeout,
-		maxRetries: DefaultMaxRetries,
-		retryWait:  DefaultRetryWait,
-		userAgent:  fmt.Sprintf("intasend-go/%s", Version),
+		timeout:           DefaultTimeout,
+		maxRetries:        DefaultMaxRetries,
+		retryWait:         DefaultRetryWait,
+		maxRespBytes:      DefaultMaxResponseBytes,
+		failoverCooldown:  DefaultFailoverCooldown,
+		linkCacheTTL:      DefaultPaymentLinkCacheTTL,
+		linkCacheMaxStale: DefaultPaymentLinkCacheMaxStale,
+		userAgent:         fmt.Sprintf("intasend-go/%s", Version),
 	}
 
 	for _, opt := range opts {
@@ -109,45 +199,62 @@ func New(opts ...Option) (*Client, error) {
 		return nil, ErrInvalidEnvironment
 	}
 
-	// Create HTTP client if not provided
+	// Create HTTP client if not provided. It has no Timeout of its own:
+	// doRequest bounds calls through the context, so a caller's longer
+	// deadline or WithRequestTimeout is not cut short.
 	if c.httpClient == nil {
-		c.httpClient = &http.Client{
-			Timeout: c.timeout,
+		c.httpClient = &http.Client{}
+	}
+
+	// Install the redirect policy, certificate pinning, and HAR recording
+	// on a copy so a caller's client is not modified.
+	hc := *c.httpClient
+	hc.CheckRedirect = checkRedirect(hc.CheckRedirect)
+	if len(c.pins) > 0 {
+		if err := pinTransport(&hc, c.pins); err != nil {
+			return nil, err
+		}
+	}
+	c.debugging = &DebugService{client: c}
+	base := hc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	custom := make(map[string]bool, len(c.extraHeaders))
+	for k := range c.extraHeaders {
+		if !managedHeader(k) {
+			custom[k] = true
 		}
 	}
+	hc.Transport = &harTransport{base: base, rec: &c.debugging.har, redact: c.redactBody, custom: custom, maxBody: c.maxRespBytes}
+	c.httpClient = &hc
+
+	if len(c.fallbackURLs) > 0 {
+		urls := append([]string{c.baseURL}, c.fallbackURLs...)
+		c.endpoints = newEndpointPool(urls, c.failoverCooldown)
+	}
+
+	c.creds.Store(c.newCredentials(c.publishableKey, c.secretKey))
 
-	// Initialize services eagerly (they are lightweight, holding only a client pointer).
+	// Initialize services eagerly (they are lightweight, holding little more than a client pointer).
 	c.collection = &CollectionService{client: c}
 	c.payout = &PayoutService{client: c}
 	c.wallet = &WalletService{client: c}
 	c.refund = &RefundService{client: c}
 	c.checkout = &CheckoutService{client: c}
-	c.paymentLink = &PaymentLinkService{client: c}
+	c.paymentLink = &PaymentLinkService{
+		client: c,
+		cache:  newLinkCache(c.linkCacheTTL, c.linkCacheMaxStale),
+	}
+	c.sandbox = &SandboxService{client: c}
+	c.events = &EventService{client: c}
 
 	return c, nil
 }
 
 // detectEnvironment sets the base URL based on the API key prefixes.
 func (c *Client) detectEnvironment() {
-	// Check publishable key
-	if strings.HasPrefix(c.publishableKey, "ISPubKey_test") {
-		c.baseURL = SandboxBaseURL
-		return
-	}
-	if strings.HasPrefix(c.publishableKey, "ISPubKey_live") {
-		c.baseURL = ProductionBaseURL
-		return
-	}
-
-	// Check secret key
-	if strings.HasPrefix(c.secretKey, "ISSecretKey_test") {
-		c.baseURL = SandboxBaseURL
-		return
-	}
-	if strings.HasPrefix(c.secretKey, "ISSecretKey_live") {
-		c.baseURL = ProductionBaseURL
-		return
-	}
+	c.baseURL = baseURLForKeys(c.publishableKey, c.secretKey)
 }
 
 // Collection returns the collection service for payment collection operations.
@@ -165,25 +272,57 @@ func (c *Client) Refund() *RefundService { return c.refund }
 // Checkout returns the checkout service for creating checkout pages.
 func (c *Client) Checkout() *CheckoutService { return c.checkout }
 
+// Debug returns the debugging service for troubleshooting a running client.
+func (c *Client) Debug() *DebugService { return c.debugging }
+
+// Events returns the event bus that publishes request, payout, and payment
+// lifecycle events.
+func (c *Client) Events() *EventService { return c.events }
+
 // PaymentLink returns the payment link service.
 func (c *Client) PaymentLink() *PaymentLinkService { return c.paymentLink }
 
+// Sandbox returns the sandbox housekeeping service.
+func (c *Client) Sandbox() *SandboxService { return c.sandbox }
+
 // PublishableKey returns the client's publishable key.
 func (c *Client) PublishableKey() string {
-	return c.publishableKey
+	return c.keys().publishableKey
 }
 
-// BaseURL returns the client's base URL.
+// BaseURL returns the base URL requests are sent to. With WithBaseURLs,
+// this is the preferred reachable URL, which changes after a failover or
+// ProbeEndpoints.
 func (c *Client) BaseURL() string {
+	if c.endpoints != nil {
+		return c.endpoints.active()
+	}
 	return c.baseURL
 }
 
-// IsSandbox returns true if the client is configured for the sandbox environment.
+// IsSandbox returns true if the client is sending requests to the sandbox
+// environment.
 func (c *Client) IsSandbox() bool {
-	return c.baseURL == SandboxBaseURL
+	return c.BaseURL() == SandboxBaseURL
+}
+
+// WebhookChallenge returns the webhook challenge configured with
+// WithWebhookChallenge, or an empty string.
+func (c *Client) WebhookChallenge() string {
+	return c.webhookChallenge
+}
+
+// currency returns the given currency, or the client's default currency when
+// it is empty.
+func (c *Client) currency(currency string) string {
+	if currency == "" {
+		return c.defaultCurrency
+	}
+	return currency
 }
 
-// IsProduction returns true if the client is configured for the production environment.
+// IsProduction returns true if the client is sending requests to the
+// production environment.
 func (c *Client) IsProduction() bool {
-	return c.baseURL == ProductionBaseURL
+	return c.BaseURL() == ProductionBaseURL
 }
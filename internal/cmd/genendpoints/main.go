@@ -0,0 +1,251 @@
+// Command genendpoints writes endpoints_gen.go, the table behind
+// intasend.Endpoints. It scans the service methods of the intasend package
+// for the API paths they call, so the table cannot drift from the code.
+//
+// Run it through go generate from the repository root:
+//
+//	go generate .
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const output = "endpoints_gen.go"
+
+// verbRE matches fmt verbs used to interpolate resource IDs into paths.
+var verbRE = regexp.MustCompile(`%[a-z]`)
+
+type endpoint struct {
+	method  string
+	path    string
+	callers map[string]bool
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("genendpoints: ")
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	found := make(map[string]*endpoint)
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !fn.Name.IsExported() || fn.Body == nil {
+				continue
+			}
+			service := serviceName(fn)
+			if service == "" {
+				continue
+			}
+			caller := service + "()." + fn.Name.Name
+			for _, call := range apiCalls(fn.Body) {
+				key := call.method + " " + call.path
+				e := found[key]
+				if e == nil {
+					e = &endpoint{method: call.method, path: call.path, callers: make(map[string]bool)}
+					found[key] = e
+				}
+				e.callers[caller] = true
+			}
+		}
+	}
+
+	src, err := render(found)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// serviceName returns the client accessor for a *XxxService method
+// receiver, e.g. "Collection" for *CollectionService.
+func serviceName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return ""
+	}
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := star.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	if !strings.HasSuffix(ident.Name, "Service") {
+		return ""
+	}
+	return strings.TrimSuffix(ident.Name, "Service")
+}
+
+type apiCall struct {
+	method string
+	path   string
+}
+
+// apiCalls finds the client requests made in body, including inside
+// closures. Paths held in local variables are resolved from their
+// assignment.
+func apiCalls(body *ast.BlockStmt) []apiCall {
+	locals := make(map[string]ast.Expr)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if as, ok := n.(*ast.AssignStmt); ok && len(as.Lhs) == len(as.Rhs) {
+			for i, lhs := range as.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					locals[id.Name] = as.Rhs[i]
+				}
+			}
+		}
+		return true
+	})
+
+	var calls []apiCall
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || !isClientExpr(sel.X) || len(n.Args) < 2 {
+				return true
+			}
+			var method string
+			switch sel.Sel.Name {
+			case "get":
+				method = "GET"
+			case "post", "postPublic":
+				method = "POST"
+			default:
+				return true
+			}
+			if path := evalPath(n.Args[1], locals); path != "" {
+				calls = append(calls, apiCall{method: method, path: path})
+			}
+		case *ast.CompositeLit:
+			ident, ok := n.Type.(*ast.Ident)
+			if !ok || ident.Name != "requestConfig" {
+				return true
+			}
+			var call apiCall
+			for _, elt := range n.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				key, ok := kv.Key.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				switch key.Name {
+				case "method":
+					if sel, ok := kv.Value.(*ast.SelectorExpr); ok {
+						call.method = strings.ToUpper(strings.TrimPrefix(sel.Sel.Name, "Method"))
+					}
+				case "path":
+					call.path = evalPath(kv.Value, locals)
+				}
+			}
+			if call.method != "" && call.path != "" {
+				calls = append(calls, call)
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+// isClientExpr reports whether x is s.client.
+func isClientExpr(x ast.Expr) bool {
+	sel, ok := x.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "client"
+}
+
+// evalPath reduces a path expression to its route, replacing interpolated
+// IDs with ":id" and dropping query strings.
+func evalPath(x ast.Expr, locals map[string]ast.Expr) string {
+	switch x := x.(type) {
+	case *ast.BasicLit:
+		if x.Kind != token.STRING {
+			return ""
+		}
+		s, err := strconv.Unquote(x.Value)
+		if err != nil {
+			return ""
+		}
+		return verbRE.ReplaceAllString(s, ":id")
+	case *ast.Ident:
+		if v, ok := locals[x.Name]; ok {
+			delete(locals, x.Name) // guard against self-referencing assignments
+			defer func() { locals[x.Name] = v }()
+			return evalPath(v, locals)
+		}
+	case *ast.CallExpr:
+		if len(x.Args) == 0 {
+			return ""
+		}
+		switch fun := x.Fun.(type) {
+		case *ast.SelectorExpr: // fmt.Sprintf
+			if fun.Sel.Name == "Sprintf" {
+				return evalPath(x.Args[0], locals)
+			}
+		case *ast.Ident: // withQuery
+			if fun.Name == "withQuery" {
+				return evalPath(x.Args[0], locals)
+			}
+		}
+	}
+	return ""
+}
+
+func render(found map[string]*endpoint) ([]byte, error) {
+	list := make([]*endpoint, 0, len(found))
+	for _, e := range found {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].path != list[j].path {
+			return list[i].path < list[j].path
+		}
+		return list[i].method < list[j].method
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by internal/cmd/genendpoints; DO NOT EDIT.\n\n")
+	buf.WriteString("package intasend\n\n")
+	buf.WriteString("var endpoints = []Endpoint{\n")
+	for _, e := range list {
+		callers := make([]string, 0, len(e.callers))
+		for c := range e.callers {
+			callers = append(callers, strconv.Quote(c))
+		}
+		sort.Strings(callers)
+		fmt.Fprintf(&buf, "\t{Method: %q, Path: %q, SDKMethods: []string{%s}},\n",
+			e.method, e.path, strings.Join(callers, ", "))
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,93 @@
+package intasend
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// credentials are the client's API keys with the headers derived from them.
+// They are replaced as a whole by UpdateKeys and never modified.
+type credentials struct {
+	publishableKey string
+	secretKey      string
+
+	// Pre-computed request headers, so each attempt only clones them.
+	headers    http.Header
+	authHeader string
+}
+
+// newCredentials builds the credentials for the given keys.
+func (c *Client) newCredentials(publishableKey, secretKey string) *credentials {
+	h := make(http.Header, 5+len(c.extraHeaders))
+	for k, v := range c.extraHeaders {
+		h[k] = append([]string(nil), v...)
+	}
+	h.Del(headerAuthorization) // set per request when authentication is required
+	h.Set(headerContentType, contentTypeJSON)
+	h.Set(headerUserAgent, c.userAgent)
+	if publishableKey != "" {
+		h.Set(headerPublicAPIKey, publishableKey)
+		h.Set(headerIntaSendPublicKey, publishableKey)
+	}
+
+	creds := &credentials{
+		publishableKey: publishableKey,
+		secretKey:      secretKey,
+		headers:        h,
+	}
+	if secretKey != "" {
+		creds.authHeader = "Bearer " + secretKey
+	}
+	return creds
+}
+
+// keys returns the client's current credentials.
+func (c *Client) keys() *credentials {
+	return c.creds.Load()
+}
+
+// UpdateKeys replaces the client's API keys. It is safe to call while the
+// client is in use: requests started afterwards, including retries of
+// requests already in flight, authenticate with the new keys, so
+// credentials can be rotated without rebuilding the client or re-wiring the
+// code holding it.
+//
+// Both keys are replaced; pass PublishableKey() to rotate only the secret
+// key. At least one key is required, and keys whose prefix names a
+// different environment than the client's are rejected with
+// ErrEnvironmentMismatch.
+//
+// Example:
+//
+//	err := client.UpdateKeys(client.PublishableKey(), os.Getenv("INTASEND_SECRET_KEY"))
+func (c *Client) UpdateKeys(publishableKey, secretKey string) error {
+	if c == nil {
+		return ErrNilClient
+	}
+	if publishableKey == "" && secretKey == "" {
+		return ErrNoKeysProvided
+	}
+	if env := baseURLForKeys(publishableKey, secretKey); env != "" && env != c.baseURL &&
+		(c.baseURL == SandboxBaseURL || c.baseURL == ProductionBaseURL) {
+		return fmt.Errorf("%w: keys are for %s, client uses %s", ErrEnvironmentMismatch, env, c.baseURL)
+	}
+	c.creds.Store(c.newCredentials(publishableKey, secretKey))
+	return nil
+}
+
+// baseURLForKeys returns the base URL of the environment named by the key
+// prefixes, or an empty string if they name none.
+func baseURLForKeys(publishableKey, secretKey string) string {
+	switch {
+	case strings.HasPrefix(publishableKey, "ISPubKey_test"):
+		return SandboxBaseURL
+	case strings.HasPrefix(publishableKey, "ISPubKey_live"):
+		return ProductionBaseURL
+	case strings.HasPrefix(secretKey, "ISSecretKey_test"):
+		return SandboxBaseURL
+	case strings.HasPrefix(secretKey, "ISSecretKey_live"):
+		return ProductionBaseURL
+	}
+	return ""
+}
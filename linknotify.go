@@ -0,0 +1,90 @@
+package intasend
+
+import (
+	"context"
+	"fmt"
+)
+
+// LinkDestination is who a payment link is sent to. Set PhoneNumber for
+// SMS, Email for email, or both; the LinkNotifier decides how to deliver.
+type LinkDestination struct {
+	Name        string
+	PhoneNumber string
+	Email       string
+}
+
+// LinkNotifier delivers a payment link to a customer, for example through
+// an SMS gateway or a transactional email service. IntaSend has no API for
+// sending payment links, so delivery is left to the notifier configured
+// with WithLinkNotifier. Implementations must be safe for concurrent use.
+type LinkNotifier interface {
+	NotifyLink(ctx context.Context, link *PaymentLink, to LinkDestination) error
+}
+
+// LinkNotifierFunc adapts a function to a LinkNotifier.
+type LinkNotifierFunc func(ctx context.Context, link *PaymentLink, to LinkDestination) error
+
+// NotifyLink implements LinkNotifier.
+func (f LinkNotifierFunc) NotifyLink(ctx context.Context, link *PaymentLink, to LinkDestination) error {
+	return f(ctx, link, to)
+}
+
+// Send fetches a payment link and delivers its URL to the destination
+// with the client's LinkNotifier. It returns ErrNoLinkNotifier if none is
+// configured.
+//
+// Example:
+//
+//	err := client.PaymentLink().Send(ctx, "LINK-123", intasend.LinkDestination{
+//	    PhoneNumber: "254712345678",
+//	})
+func (s *PaymentLinkService) Send(ctx context.Context, linkID string, to LinkDestination, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if s.client.linkNotifier == nil {
+		return ErrNoLinkNotifier
+	}
+	link, err := s.Get(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	return s.notify(ctx, link, to)
+}
+
+// CreateAndSend creates a payment link and delivers it to the destination
+// in one call, for invoicing flows. If delivery fails, the created link is
+// returned with the error so it can be sent again with Send instead of
+// creating another.
+//
+// Example:
+//
+//	link, err := client.PaymentLink().CreateAndSend(ctx, &intasend.CreatePaymentLinkRequest{
+//	    Title:    "Invoice 2024-031",
+//	    Currency: "KES",
+//	    Amount:   12000,
+//	    IsActive: true,
+//	}, intasend.LinkDestination{Name: "Jane", Email: "jane@example.com"})
+func (s *PaymentLinkService) CreateAndSend(ctx context.Context, req *CreatePaymentLinkRequest, to LinkDestination, reqOpts ...RequestOption) (*PaymentLink, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if s.client.linkNotifier == nil {
+		return nil, ErrNoLinkNotifier
+	}
+	link, err := s.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return link, s.notify(ctx, link, to)
+}
+
+// notify delivers link with the client's notifier.
+func (s *PaymentLinkService) notify(ctx context.Context, link *PaymentLink, to LinkDestination) error {
+	if to.PhoneNumber == "" && to.Email == "" {
+		return fmt.Errorf("%w: payment link destination needs a phone number or email", ErrIncompleteRequest)
+	}
+	if err := s.client.linkNotifier.NotifyLink(ctx, link, to); err != nil {
+		return fmt.Errorf("intasend: send payment link %s: %w", link.LinkID, err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package intasend
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Logger receives the client's log lines: debug output when debug logging
+// is on, and errors from background work such as payout tracking.
+// *log.Logger implements it. Each line is the message followed by its
+// fields as key=value pairs; use WithStructuredLogger to receive the
+// fields separately.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// LogLevel is the severity of a log record.
+type LogLevel int
+
+// Log levels, in increasing severity.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the level's name, such as "DEBUG".
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+	return "UNKNOWN"
+}
+
+// StructuredLogger receives the client's log records with a level, a
+// constant message such as "request sent", and fields such as "method" and
+// "status". Fields include those attached with ContextWithFields. Request
+// and response bodies are redacted before they reach it. Implementations
+// must be safe for concurrent use; see NewSlogLogger for log/slog.
+type StructuredLogger interface {
+	Log(ctx context.Context, level LogLevel, msg string, fields map[string]interface{})
+}
+
+// StructuredLoggerFunc adapts a function to a StructuredLogger.
+type StructuredLoggerFunc func(ctx context.Context, level LogLevel, msg string, fields map[string]interface{})
+
+// Log implements StructuredLogger.
+func (f StructuredLoggerFunc) Log(ctx context.Context, level LogLevel, msg string, fields map[string]interface{}) {
+	f(ctx, level, msg, fields)
+}
+
+// log writes a record to the client's structured logger, or else as a line
+// to its Logger or the standard logger. The context's fields are added to
+// fields, which win on key conflicts.
+func (c *Client) log(ctx context.Context, level LogLevel, msg string, fields map[string]interface{}) {
+	if ctxFields := FieldsFromContext(ctx); len(ctxFields) > 0 {
+		merged := make(map[string]interface{}, len(ctxFields)+len(fields))
+		for k, v := range ctxFields {
+			merged[k] = v
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		fields = merged
+	}
+	if c.structuredLogger != nil {
+		c.structuredLogger.Log(ctx, level, msg, fields)
+		return
+	}
+	if c.logger != nil {
+		c.logger.Printf("[IntaSend] %s%s", msg, formatFields(fields))
+		return
+	}
+	log.Printf("[IntaSend] %s%s", msg, formatFields(fields))
+}
+
+var (
+	// secretKeyPattern matches IntaSend secret and publishable keys.
+	secretKeyPattern = regexp.MustCompile(`(ISSecretKey|ISPubKey)_(test|live)_[A-Za-z0-9_.-]+`)
+
+	// emailPattern matches email addresses.
+	emailPattern = regexp.MustCompile(`\b([A-Za-z0-9])[A-Za-z0-9._%+-]*@([A-Za-z0-9-]+\.)+[A-Za-z]{2,}\b`)
+
+	// phonePattern matches Kenyan mobile numbers as 2547..., +2547...,
+	// 07..., or 01... .
+	phonePattern = regexp.MustCompile(`(\+?\b254|\b0)([17][0-9]{5})([0-9]{3})\b`)
+)
+
+// redact masks API keys, email addresses, and phone numbers in a logged
+// body, keeping the key's environment, the address's first letter and
+// domain, and the number's last three digits so lines can still be told
+// apart.
+func redact(body []byte) string {
+	s := secretKeyPattern.ReplaceAllString(string(body), "${1}_${2}_[REDACTED]")
+	s = emailPattern.ReplaceAllStringFunc(s, func(m string) string {
+		at := strings.IndexByte(m, '@')
+		return m[:1] + "***" + m[at:]
+	})
+	return phonePattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := phonePattern.FindStringSubmatch(m)
+		masked := make([]byte, len(sub[2]))
+		for i := range masked {
+			masked[i] = '*'
+		}
+		return sub[1] + string(masked) + sub[3]
+	})
+}
+
+// redactQueryValue masks a query parameter value in a logged URL: personal
+// data parameters, such as "email" and "phone_number", and the customer ID
+// they are linked to are replaced outright; other values are masked as
+// redact does.
+func redactQueryValue(key, value string) string {
+	if _, ok := piiFields[key]; (ok || key == "customer_id") && value != "" {
+		return "[REDACTED]"
+	}
+	return redact([]byte(value))
+}
+
+// redactURL masks personal data in the query of a logged URL, such as
+// customer filters passed to Do.
+func redactURL(raw string) string {
+	i := strings.IndexByte(raw, '?')
+	if i < 0 {
+		return raw
+	}
+	q, err := url.ParseQuery(raw[i+1:])
+	if err != nil {
+		return raw[:i] + "?[REDACTED]"
+	}
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(q))
+	for _, k := range keys {
+		for _, v := range q[k] {
+			masked := redactQueryValue(k, v)
+			if masked != "[REDACTED]" {
+				masked = url.QueryEscape(masked)
+			}
+			parts = append(parts, url.QueryEscape(k)+"="+masked)
+		}
+	}
+	return raw[:i+1] + strings.Join(parts, "&")
+}
+
+// redactError returns err's message for logging, with reqURL, which
+// net/http includes in its errors, redacted.
+func redactError(err error, reqURL string) string {
+	if err == nil {
+		return ""
+	}
+	return redact([]byte(strings.ReplaceAll(err.Error(), reqURL, redactURL(reqURL))))
+}
+
+// redactBody masks personal data and keys in a logged or recorded body.
+// With a Tokenizer configured, personal data fields of a JSON body are
+// replaced by their tokens first, as when the SDK persists them.
+func (c *Client) redactBody(ctx context.Context, body []byte) string {
+	if c.tokenizer != nil {
+		if tokenized, err := TokenizeJSON(ctx, c.tokenizer, body); err == nil {
+			body = tokenized
+		}
+	}
+	return redact(body)
+}
@@ -0,0 +1,69 @@
+package intasend
+
+import (
+	"strings"
+	"time"
+)
+
+// RequestMetrics describes a single completed API request, including all
+// of its retry attempts.
+type RequestMetrics struct {
+	// Method is the HTTP method, e.g. "POST".
+	Method string
+
+	// Endpoint is the request path with resource IDs replaced by ":id",
+	// e.g. "/wallets/:id/transactions/". It is safe to use as a metric label.
+	Endpoint string
+
+	// StatusCode is the HTTP status of the final attempt, or 0 if no
+	// response was received.
+	StatusCode int
+
+	// Duration is the total time spent on the request, including retries.
+	Duration time.Duration
+
+	// Retries is the number of attempts made after the first one.
+	Retries int
+
+	// RateLimited is the number of attempts rejected with HTTP 429.
+	RateLimited int
+
+	// RequestID is the X-Request-ID of the final response, or the request
+	// ID from its error body, if any.
+	RequestID string
+
+	// Err is the error returned to the caller, if any.
+	Err error
+}
+
+// MetricsCollector receives metrics for every API request made by the client.
+// Implementations must be safe for concurrent use.
+type MetricsCollector interface {
+	ObserveRequest(m RequestMetrics)
+}
+
+// endpointLabel normalizes a request path into a low-cardinality label by
+// replacing resource ID segments with ":id".
+func endpointLabel(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if seg != "" && !isStaticSegment(seg) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isStaticSegment reports whether a path segment is part of the API route
+// rather than a resource identifier. Routes only use lowercase words.
+func isStaticSegment(seg string) bool {
+	for _, r := range seg {
+		if (r < 'a' || r > 'z') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
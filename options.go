@@ -1,7 +1,9 @@
 package intasend
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -35,6 +37,31 @@ func WithBaseURL(url string) Option {
 	}
 }
 
+// WithBaseURLs sets a primary base URL and fallbacks, such as a regional
+// mirror or a pass-through proxy. When the active URL is unreachable after
+// retries, the request moves to the next URL and the failed one is skipped
+// for the failover cooldown; the primary is preferred again once it recovers.
+//
+// POST requests only fail over when no attempt, including earlier retries,
+// got past connecting, so a payment that may have reached IntaSend is never
+// resubmitted elsewhere.
+func WithBaseURLs(primary string, fallback ...string) Option {
+	return func(c *Client) error {
+		c.baseURL = primary
+		c.fallbackURLs = fallback
+		return nil
+	}
+}
+
+// WithFailoverCooldown sets how long an unreachable base URL is skipped.
+// Default is 30 seconds. See WithBaseURLs.
+func WithFailoverCooldown(d time.Duration) Option {
+	return func(c *Client) error {
+		c.failoverCooldown = d
+		return nil
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client.
 // This is useful for testing or custom transport configuration.
 func WithHTTPClient(client *http.Client) Option {
@@ -44,7 +71,9 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
-// WithTimeout sets the request timeout duration.
+// WithTimeout sets the request timeout duration, covering all attempts of
+// a request. It applies whatever HTTP client is used, to calls whose
+// context has no deadline of its own; a zero timeout disables it.
 // Default is 30 seconds.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) error {
@@ -55,6 +84,8 @@ func WithTimeout(timeout time.Duration) Option {
 
 // WithRetry configures the retry behavior for failed requests.
 // Default is 3 retries with 1 second initial wait (exponential backoff).
+// A 429 or 503 response's Retry-After header replaces the backoff wait
+// before the next retry.
 func WithRetry(maxRetries int, waitTime time.Duration) Option {
 	return func(c *Client) error {
 		c.maxRetries = maxRetries
@@ -63,10 +94,70 @@ func WithRetry(maxRetries int, waitTime time.Duration) Option {
 	}
 }
 
-// WithDebug enables debug logging of requests and responses.
+// WithRateLimit limits the client to rps requests per second across all
+// goroutines, including retries, with bursts of up to one second's worth.
+// Requests over the limit wait for their turn or for their context to end.
+// Use it to stay under IntaSend's rate limits instead of hitting 429
+// responses. Zero or less means no limit, the default.
+func WithRateLimit(rps float64) Option {
+	return func(c *Client) error {
+		c.limiter = nil
+		if rps > 0 {
+			c.limiter = newRateLimiter(rps)
+		}
+		return nil
+	}
+}
+
+// WithMaxResponseBytes limits the size of response bodies read from the API.
+// Larger responses fail with ErrResponseTooLarge. Default is 10 MiB;
+// a value of 0 or less removes the limit.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) error {
+		c.maxRespBytes = n
+		return nil
+	}
+}
+
+// WithMaxConcurrentRequests limits the client to n API requests in flight
+// at once, across all goroutines. Further requests wait for a free slot or
+// for their context to end. Use it to protect the API and your egress from
+// bursts in batch jobs. Retries of a request keep its slot. Zero or less
+// means no limit, the default.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(c *Client) error {
+		c.inflight = nil
+		if n > 0 {
+			c.inflight = make(chan struct{}, n)
+		}
+		return nil
+	}
+}
+
+// WithDebug enables debug logging of requests and responses. Use
+// Client.SetDebug to switch it on a running client.
 func WithDebug(debug bool) Option {
 	return func(c *Client) error {
-		c.debug = debug
+		c.debug.Store(debug)
+		return nil
+	}
+}
+
+// WithLogger sends the client's log output to l instead of the standard
+// logger.
+func WithLogger(l Logger) Option {
+	return func(c *Client) error {
+		c.logger = l
+		return nil
+	}
+}
+
+// WithStructuredLogger sends the client's log records to l, with their
+// level and fields, instead of formatting them as lines. It takes
+// precedence over WithLogger.
+func WithStructuredLogger(l StructuredLogger) Option {
+	return func(c *Client) error {
+		c.structuredLogger = l
 		return nil
 	}
 }
@@ -94,3 +185,195 @@ func WithProduction() Option {
 		return nil
 	}
 }
+
+// WithDefaultCurrency sets the currency used by checkout, charge, payment link,
+// and payout requests that leave Currency empty.
+func WithDefaultCurrency(currency string) Option {
+	return func(c *Client) error {
+		c.defaultCurrency = currency
+		return nil
+	}
+}
+
+// WithPayoutCallbackURL sets the batch-level callback URL sent with payout
+// requests that leave CallbackURL empty. IntaSend posts status updates for
+// the whole batch to it; see Payout().Correlate.
+func WithPayoutCallbackURL(url string) Option {
+	return func(c *Client) error {
+		c.payoutCallbackURL = url
+		return nil
+	}
+}
+
+// WithHeaders adds headers to every request, such as a header required by
+// an outbound gateway. Repeated calls accumulate. Headers the SDK manages
+// (Content-Type, User-Agent, and the API key headers) take precedence and
+// Authorization is ignored; use WithUserAgent to change the user agent.
+func WithHeaders(h http.Header) Option {
+	return func(c *Client) error {
+		if c.extraHeaders == nil {
+			c.extraHeaders = make(http.Header, len(h))
+		}
+		for k, v := range h {
+			key := http.CanonicalHeaderKey(k)
+			c.extraHeaders[key] = append(c.extraHeaders[key], v...)
+		}
+		return nil
+	}
+}
+
+// WithEnforceApproval forces RequiresApproval to "YES" on every payout the
+// client initiates, whatever the request says, so payouts can only be
+// released through an explicit approval. Use it to enforce an
+// organization-wide approval policy in one place.
+func WithEnforceApproval() Option {
+	return func(c *Client) error {
+		c.enforceApproval = true
+		return nil
+	}
+}
+
+// WithApprovalNotifier notifies approvers whenever a payout batch needs
+// approval, so callers do not each have to remember to. linkTemplate, if
+// not empty, is the URL of your approval UI; "{tracking_id}" in it is
+// replaced with the batch's escaped tracking ID to build
+// PendingApproval.Link.
+//
+// Example:
+//
+//	intasend.WithApprovalNotifier("https://admin.example.com/payouts/{tracking_id}",
+//	    func(ctx context.Context, p *intasend.PendingApproval) {
+//	        go slack.Post("#payouts", fmt.Sprintf("%d payouts totalling %s %.2f need approval: %s",
+//	            p.Count, p.Currency, p.Total, p.Link))
+//	    })
+func WithApprovalNotifier(linkTemplate string, fn ApprovalNotifier) Option {
+	return func(c *Client) error {
+		c.approvalLink = linkTemplate
+		c.approvalNotifier = fn
+		return nil
+	}
+}
+
+// WithLinkNotifier sets how PaymentLink().Send and CreateAndSend deliver
+// payment links to customers.
+//
+// Example:
+//
+//	intasend.WithLinkNotifier(intasend.LinkNotifierFunc(
+//	    func(ctx context.Context, link *intasend.PaymentLink, to intasend.LinkDestination) error {
+//	        return sms.Send(ctx, to.PhoneNumber, "Pay "+link.Title+": "+link.URL)
+//	    }))
+func WithLinkNotifier(n LinkNotifier) Option {
+	return func(c *Client) error {
+		c.linkNotifier = n
+		return nil
+	}
+}
+
+// WithRateProvider sets the source of exchange rates for
+// PaymentLink().PreviewAmounts.
+//
+// Example:
+//
+//	intasend.WithRateProvider(intasend.RateProviderFunc(
+//	    func(ctx context.Context, from, to string) (intasend.Amount, error) {
+//	        return fxCache.Rate(ctx, from, to)
+//	    }))
+func WithRateProvider(p RateProvider) Option {
+	return func(c *Client) error {
+		c.rateProvider = p
+		return nil
+	}
+}
+
+// WithWebhookChallenge sets the challenge string configured for webhooks in
+// the IntaSend dashboard. It is exposed through Client.WebhookChallenge so
+// webhook handlers can share the client's configuration.
+func WithWebhookChallenge(challenge string) Option {
+	return func(c *Client) error {
+		c.webhookChallenge = challenge
+		return nil
+	}
+}
+
+// WithRecipientThrottle rejects STK pushes, M-Pesa wallet funding, and airtime
+// top-ups that target a phone number already targeted within interval,
+// returning a *ThrottledError. This guards against duplicate payment prompts
+// from retried jobs or double-submitted forms. A zero interval disables it.
+func WithRecipientThrottle(interval time.Duration) Option {
+	return func(c *Client) error {
+		c.throttle = nil
+		if interval > 0 {
+			c.throttle = newRecipientThrottle(interval)
+		}
+		return nil
+	}
+}
+
+// WithTrackingStore records every initiated payout batch in store so that
+// Payout().Correlate can match webhooks and status updates to the original
+// request.
+func WithTrackingStore(store TrackingStore) Option {
+	return func(c *Client) error {
+		c.trackingStore = store
+		return nil
+	}
+}
+
+// WithTokenizer replaces phone numbers, names, and accounts with tokens
+// from t before the SDK persists them, such as in the records saved to the
+// TrackingStore. If tokenizing fails, the record is not saved.
+func WithTokenizer(t Tokenizer) Option {
+	return func(c *Client) error {
+		c.tokenizer = t
+		return nil
+	}
+}
+
+// WithDeadLetterHandler registers h to receive the items that background
+// components, such as CollectQueue, StreamTransactions, and payout
+// tracking, give up on after a terminal failure.
+func WithDeadLetterHandler(h DeadLetterHandler) Option {
+	return func(c *Client) error {
+		c.deadLetters = h
+		return nil
+	}
+}
+
+// WithPaymentLinkCache sets how long PaymentLink().GetCached serves a link
+// without contacting the API (ttl), and for how long after that a stale
+// link is still served while it is refreshed in the background (maxStale).
+// Defaults to DefaultPaymentLinkCacheTTL and DefaultPaymentLinkCacheMaxStale.
+func WithPaymentLinkCache(ttl, maxStale time.Duration) Option {
+	return func(c *Client) error {
+		if ttl < 0 || maxStale < 0 {
+			return errors.New("intasend: payment link cache durations must not be negative")
+		}
+		c.linkCacheTTL = ttl
+		c.linkCacheMaxStale = maxStale
+		return nil
+	}
+}
+
+// WithMetricsCollector registers a collector that is notified after every
+// API request with its duration, status code, and retry counts.
+func WithMetricsCollector(mc MetricsCollector) Option {
+	return func(c *Client) error {
+		c.metrics = mc
+		return nil
+	}
+}
+
+// WithSandboxTag prefixes the labels of wallets and the titles of payment
+// links created with test keys with "[tag] ", so resources left behind by
+// CI runs can be found with Sandbox().Leftovers. Clients using live keys
+// are unaffected.
+func WithSandboxTag(tag string) Option {
+	return func(c *Client) error {
+		if strings.ContainsAny(tag, "[]") {
+			return errors.New("intasend: sandbox tag must not contain brackets")
+		}
+		c.sandboxTag = tag
+		return nil
+	}
+}
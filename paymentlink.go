@@ -2,6 +2,7 @@ package intasend
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -9,6 +10,7 @@ import (
 // PaymentLinkService handles payment link operations.
 type PaymentLinkService struct {
 	client *Client
+	cache  *linkCache
 }
 
 // Tariff represents who pays the transaction fees.
@@ -32,13 +34,20 @@ type PaymentLink struct {
 	MobileTariff Tariff    `json:"mobile_tarrif"`
 	CardTariff   Tariff    `json:"card_tarrif"`
 	IsActive     bool      `json:"is_active"`
+	Locale       Locale    `json:"locale,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
-// PaymentLinkListResponse represents the response from listing payment links.
+// AmountDecimal returns Amount as an exact decimal.
+func (l *PaymentLink) AmountDecimal() Amount { return AmountFromFloat(l.Amount) }
+
+// PaymentLinkListResponse represents a page of payment links.
 type PaymentLinkListResponse struct {
-	Results []PaymentLink `json:"results"`
+	Count    int           `json:"count,omitempty"`
+	Next     string        `json:"next,omitempty"`
+	Previous string        `json:"previous,omitempty"`
+	Results  []PaymentLink `json:"results"`
 }
 
 // CreatePaymentLinkRequest represents a request to create a payment link.
@@ -49,14 +58,33 @@ type CreatePaymentLinkRequest struct {
 	MobileTariff Tariff  `json:"mobile_tarrif,omitempty"`
 	CardTariff   Tariff  `json:"card_tarrif,omitempty"`
 	IsActive     bool    `json:"is_active"`
+	Locale       Locale  `json:"locale,omitempty"`
+
+	// AmountDecimal, if set, is sent instead of Amount, exactly as given.
+	AmountDecimal Amount `json:"-"`
+}
+
+// MarshalJSON encodes the request with its amount formatted for the API.
+func (r CreatePaymentLinkRequest) MarshalJSON() ([]byte, error) {
+	type plain CreatePaymentLinkRequest
+	body := struct {
+		plain
+		Amount Amount `json:"amount,omitempty"`
+	}{plain: plain(r)}
+	if r.Amount != 0 || r.AmountDecimal != "" {
+		body.Amount = requestAmount(r.AmountDecimal, r.Amount)
+	}
+	return json.Marshal(body)
 }
 
-// List returns all payment links.
+// List returns the first page of payment links. Use ListAll for accounts
+// with more links than fit on a page.
 //
 // Example:
 //
 //	links, err := client.PaymentLink().List(ctx)
-func (s *PaymentLinkService) List(ctx context.Context) (*PaymentLinkListResponse, error) {
+func (s *PaymentLinkService) List(ctx context.Context, reqOpts ...RequestOption) (*PaymentLinkListResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	var resp PaymentLinkListResponse
 	if err := s.client.get(ctx, "/paymentlinks/", &resp); err != nil {
 		return nil, err
@@ -64,6 +92,26 @@ func (s *PaymentLinkService) List(ctx context.Context) (*PaymentLinkListResponse
 	return &resp, nil
 }
 
+// ListAll returns every payment link, following pagination.
+//
+// Example:
+//
+//	links, err := client.PaymentLink().ListAll(ctx)
+func (s *PaymentLinkService) ListAll(ctx context.Context, reqOpts ...RequestOption) ([]PaymentLink, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var all []PaymentLink
+	for page := 1; ; page++ {
+		var resp PaymentLinkListResponse
+		if err := s.client.get(ctx, withQuery("/paymentlinks/", pageQuery(page)), &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Results...)
+		if resp.Next == "" || len(resp.Results) == 0 {
+			return all, nil
+		}
+	}
+}
+
 // Create creates a new payment link.
 //
 // Example:
@@ -76,9 +124,32 @@ func (s *PaymentLinkService) List(ctx context.Context) (*PaymentLinkListResponse
 //	    CardTariff:   intasend.TariffBusinessPays,
 //	    IsActive:     true,
 //	})
-func (s *PaymentLinkService) Create(ctx context.Context, req *CreatePaymentLinkRequest) (*PaymentLink, error) {
+func (s *PaymentLinkService) Create(ctx context.Context, req *CreatePaymentLinkRequest, reqOpts ...RequestOption) (*PaymentLink, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateLocale(req.Locale); err != nil {
+		return nil, err
+	}
+	if err := validateEnum("mobile_tarrif", string(req.MobileTariff), req.MobileTariff.IsValid()); err != nil {
+		return nil, err
+	}
+	if err := validateEnum("card_tarrif", string(req.CardTariff), req.CardTariff.IsValid()); err != nil {
+		return nil, err
+	}
+	// Copy the request so the caller's value is never modified.
+	body := *req
+	if body.Currency == "" {
+		body.Currency = s.client.defaultCurrency
+	}
+	body.Title = s.client.sandboxTagged(ctx, body.Title)
+
 	var resp PaymentLink
-	if err := s.client.post(ctx, "/paymentlinks/", req, &resp); err != nil {
+	if err := s.client.post(ctx, "/paymentlinks/", &body, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -89,10 +160,41 @@ func (s *PaymentLinkService) Create(ctx context.Context, req *CreatePaymentLinkR
 // Example:
 //
 //	link, err := client.PaymentLink().Get(ctx, "LINK-123")
-func (s *PaymentLinkService) Get(ctx context.Context, linkID string) (*PaymentLink, error) {
+func (s *PaymentLinkService) Get(ctx context.Context, linkID string, reqOpts ...RequestOption) (*PaymentLink, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	var resp PaymentLink
 	if err := s.client.get(ctx, fmt.Sprintf("/paymentlinks/%s/", linkID), &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
+
+// GetCached returns a payment link from an in-memory cache, fetching it on a
+// miss. Within the cache TTL the API is not contacted; once the TTL passes,
+// the stale link is still returned while a background request refreshes it,
+// until the stale window also ends. Concurrent requests for the same link
+// share one API call. Lifetimes are set with WithPaymentLinkCache.
+//
+// Links are cached separately for each ContextWithEnvironment environment
+// and keys. A fetch keeps ctx's environment and request options but not its
+// cancellation, so callers that give up do not fail the others waiting on
+// it. The cache holds at most 1024 links, dropping the oldest first.
+//
+// Use it on hot paths such as rendering a checkout page; use Get when the
+// latest state matters.
+//
+// Example:
+//
+//	link, err := client.PaymentLink().GetCached(ctx, "LINK-123")
+func (s *PaymentLinkService) GetCached(ctx context.Context, linkID string, reqOpts ...RequestOption) (*PaymentLink, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return s.cache.get(ctx, linkID, func(ctx context.Context, linkID string) (*PaymentLink, error) {
+		return s.Get(ctx, linkID)
+	})
+}
+
+// Invalidate removes a payment link from the GetCached cache, for example
+// after it was changed in the dashboard.
+func (s *PaymentLinkService) Invalidate(linkID string) {
+	s.cache.invalidate(linkID)
+}
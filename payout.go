@@ -2,6 +2,8 @@ package intasend
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -63,6 +65,25 @@ type Transaction struct {
 	BankCode         string `json:"bank_code,omitempty"`
 }
 
+// NewTransaction builds a payout Transaction, formatting amount with
+// FormatAmount so it never reaches the API in scientific notation.
+//
+// Example:
+//
+//	txn, err := intasend.NewTransaction("254712345678", 1000000, "Salary")
+//	// txn.Amount == "1000000"
+func NewTransaction(account string, amount float64, narrative string) (Transaction, error) {
+	formatted, err := FormatAmount(amount)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return Transaction{
+		Account:   account,
+		Amount:    formatted,
+		Narrative: narrative,
+	}, nil
+}
+
 // InitiateRequest represents a request to initiate a payout batch.
 type InitiateRequest struct {
 	Provider         Provider       `json:"provider"`
@@ -198,7 +219,8 @@ const (
 )
 
 // Initiate starts a new payout batch.
-// Payouts require approval unless RequiresApproval is set to "NO".
+// Payouts require approval unless RequiresApproval is set to "NO" and the
+// client was not created with WithEnforceApproval.
 //
 // Example:
 //
@@ -209,14 +231,61 @@ const (
 //	        {Account: "254712345678", Amount: "100", Narrative: "Payment"},
 //	    },
 //	})
-func (s *PayoutService) Initiate(ctx context.Context, req *InitiateRequest) (*InitiateResponse, error) {
+func (s *PayoutService) Initiate(ctx context.Context, req *InitiateRequest, reqOpts ...RequestOption) (*InitiateResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if err := validateInitiate(req); err != nil {
+		return nil, err
+	}
+	if (req.Currency == "" && s.client.defaultCurrency != "") ||
+		(req.CallbackURL == "" && s.client.payoutCallbackURL != "") ||
+		(s.client.enforceApproval && req.RequiresApproval != ApprovalRequired) {
+		withDefaults := *req
+		withDefaults.Currency = s.client.currency(req.Currency)
+		if withDefaults.CallbackURL == "" {
+			withDefaults.CallbackURL = s.client.payoutCallbackURL
+		}
+		if s.client.enforceApproval {
+			withDefaults.RequiresApproval = ApprovalRequired
+		}
+		req = &withDefaults
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
 	var resp InitiateResponse
 	if err := s.client.post(ctx, "/send-money/initiate/", req, &resp); err != nil {
 		return nil, err
 	}
+	s.trackPayout(ctx, req, &resp)
+	s.notifyApproval(ctx, req, &resp)
+	if s.client.events.active() {
+		actor, _ := ActorFromContext(ctx)
+		s.client.events.publish(ctx, PayoutInitiated{Request: req, Response: &resp, Actor: actor})
+	}
 	return &resp, nil
 }
 
+// validateInitiate rejects providers, approval statuses, and account types
+// the API does not accept before a request is sent.
+func validateInitiate(req *InitiateRequest) error {
+	if !req.Provider.IsValid() {
+		return fmt.Errorf("%w: provider %q", ErrInvalidEnumValue, req.Provider)
+	}
+	if err := validateEnum("requires_approval", string(req.RequiresApproval), req.RequiresApproval.IsValid()); err != nil {
+		return err
+	}
+	for i, tx := range req.Transactions {
+		if err := validateEnum("account_type", tx.AccountType, AccountType(tx.AccountType).IsValid()); err != nil {
+			return fmt.Errorf("transaction %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
 // MPesa initiates an M-Pesa B2C payout (consumer payments).
 //
 // Example:
@@ -227,7 +296,11 @@ func (s *PayoutService) Initiate(ctx context.Context, req *InitiateRequest) (*In
 //	        {Account: "254712345678", Amount: "100", Narrative: "Salary"},
 //	    },
 //	})
-func (s *PayoutService) MPesa(ctx context.Context, req *MPesaRequest) (*InitiateResponse, error) {
+func (s *PayoutService) MPesa(ctx context.Context, req *MPesaRequest, reqOpts ...RequestOption) (*InitiateResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
 	initReq := &InitiateRequest{
 		Provider:         ProviderMPesaB2C,
 		Currency:         req.Currency,
@@ -255,7 +328,11 @@ func (s *PayoutService) MPesa(ctx context.Context, req *MPesaRequest) (*Initiate
 //	        },
 //	    },
 //	})
-func (s *PayoutService) MPesaB2B(ctx context.Context, req *MPesaB2BRequest) (*InitiateResponse, error) {
+func (s *PayoutService) MPesaB2B(ctx context.Context, req *MPesaB2BRequest, reqOpts ...RequestOption) (*InitiateResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
 	transactions := make([]Transaction, len(req.Transactions))
 	for i, t := range req.Transactions {
 		transactions[i] = Transaction{
@@ -295,7 +372,11 @@ func (s *PayoutService) MPesaB2B(ctx context.Context, req *MPesaB2BRequest) (*In
 //	        },
 //	    },
 //	})
-func (s *PayoutService) Bank(ctx context.Context, req *BankRequest) (*InitiateResponse, error) {
+func (s *PayoutService) Bank(ctx context.Context, req *BankRequest, reqOpts ...RequestOption) (*InitiateResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
 	transactions := make([]Transaction, len(req.Transactions))
 	for i, t := range req.Transactions {
 		transactions[i] = Transaction{
@@ -328,7 +409,11 @@ func (s *PayoutService) Bank(ctx context.Context, req *BankRequest) (*InitiateRe
 //	        {Account: "wallet@intasend.com", Amount: "500", Narrative: "Transfer"},
 //	    },
 //	})
-func (s *PayoutService) IntaSend(ctx context.Context, req *IntaSendTransferRequest) (*InitiateResponse, error) {
+func (s *PayoutService) IntaSend(ctx context.Context, req *IntaSendTransferRequest, reqOpts ...RequestOption) (*InitiateResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
 	initReq := &InitiateRequest{
 		Provider:         ProviderIntaSend,
 		Currency:         req.Currency,
@@ -350,7 +435,11 @@ func (s *PayoutService) IntaSend(ctx context.Context, req *IntaSendTransferReque
 //	        {Account: "254712345678", Amount: "100", Narrative: "Airtime"},
 //	    },
 //	})
-func (s *PayoutService) Airtime(ctx context.Context, req *AirtimeRequest) (*InitiateResponse, error) {
+func (s *PayoutService) Airtime(ctx context.Context, req *AirtimeRequest, reqOpts ...RequestOption) (*InitiateResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
 	initReq := &InitiateRequest{
 		Provider:         ProviderAirtime,
 		Currency:         req.Currency,
@@ -359,7 +448,17 @@ func (s *PayoutService) Airtime(ctx context.Context, req *AirtimeRequest) (*Init
 		WalletID:         req.WalletID,
 		RequiresApproval: req.RequiresApproval,
 	}
-	return s.Initiate(ctx, initReq)
+
+	phones := make([]string, len(req.Transactions))
+	for i, tx := range req.Transactions {
+		phones[i] = tx.Account
+	}
+	var resp *InitiateResponse
+	err := s.client.throttled(phones, func() (err error) {
+		resp, err = s.Initiate(ctx, initReq)
+		return err
+	})
+	return resp, err
 }
 
 // Approve approves a pending payout batch.
@@ -372,11 +471,19 @@ func (s *PayoutService) Airtime(ctx context.Context, req *AirtimeRequest) (*Init
 //	    Nonce:      resp.Nonce,
 //	    WalletID:   resp.WalletID,
 //	})
-func (s *PayoutService) Approve(ctx context.Context, req *ApproveRequest) (*ApproveResponse, error) {
+func (s *PayoutService) Approve(ctx context.Context, req *ApproveRequest, reqOpts ...RequestOption) (*ApproveResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
 	var resp ApproveResponse
 	if err := s.client.post(ctx, "/send-money/approve/", req, &resp); err != nil {
 		return nil, err
 	}
+	if s.client.events.active() {
+		actor, _ := ActorFromContext(ctx)
+		s.client.events.publish(ctx, PayoutApproved{Response: &resp, Actor: actor})
+	}
 	return &resp, nil
 }
 
@@ -385,7 +492,8 @@ func (s *PayoutService) Approve(ctx context.Context, req *ApproveRequest) (*Appr
 // Example:
 //
 //	status, err := client.Payout().Status(ctx, "tracking-id-123")
-func (s *PayoutService) Status(ctx context.Context, trackingID string) (*PayoutStatusResponse, error) {
+func (s *PayoutService) Status(ctx context.Context, trackingID string, reqOpts ...RequestOption) (*PayoutStatusResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	req := &payoutStatusRequest{TrackingID: trackingID}
 
 	var resp PayoutStatusResponse
@@ -394,3 +502,31 @@ func (s *PayoutService) Status(ctx context.Context, trackingID string) (*PayoutS
 	}
 	return &resp, nil
 }
+
+// WaitForCompletion polls the batch's status until it is Completed or
+// Failed and returns the final status. It waits until ctx is done unless
+// opts sets a Timeout, after which the last status is returned with
+// ErrWaitTimeout. Individual transactions may still fail in a completed
+// batch; check their statuses.
+//
+// Example:
+//
+//	status, err := client.Payout().WaitForCompletion(ctx, resp.TrackingID, &intasend.PollOptions{Timeout: 10 * time.Minute})
+//	if err == nil && status.Status == intasend.PayoutStatusFailed {
+//	    alert(status)
+//	}
+func (s *PayoutService) WaitForCompletion(ctx context.Context, trackingID string, opts *PollOptions, reqOpts ...RequestOption) (*PayoutStatusResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var last *PayoutStatusResponse
+	err := pollUntil(ctx, opts, func(ctx context.Context) (string, bool, error) {
+		status, err := s.Status(ctx, trackingID)
+		if err != nil {
+			return "", false, err
+		}
+		last = status
+		done := strings.EqualFold(status.Status, PayoutStatusCompleted) || strings.EqualFold(status.Status, PayoutStatusFailed)
+		// Batch statuses are title case; PollOptions adapts to invoice states.
+		return strings.ToUpper(status.Status), done, nil
+	})
+	return last, err
+}
@@ -0,0 +1,173 @@
+package intasend
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// PIIKind identifies the kind of personal data passed to a Tokenizer.
+type PIIKind string
+
+const (
+	// PIIPhoneNumber is a phone number.
+	PIIPhoneNumber PIIKind = "phone_number"
+
+	// PIIEmail is an email address.
+	PIIEmail PIIKind = "email"
+
+	// PIIName is a person's name.
+	PIIName PIIKind = "name"
+
+	// PIIAccount is a payment account: an M-Pesa number, bank account,
+	// or PayBill account.
+	PIIAccount PIIKind = "account"
+)
+
+// piiFields maps the JSON keys IntaSend uses for personal data to their
+// kind.
+var piiFields = map[string]PIIKind{
+	"phone_number": PIIPhoneNumber,
+	"email":        PIIEmail,
+	"name":         PIIName,
+	"first_name":   PIIName,
+	"last_name":    PIIName,
+	"account":      PIIAccount,
+}
+
+// Tokenizer replaces personal data with tokens before the SDK persists it,
+// so stored records such as tracked payouts stay compliant with data
+// protection rules by construction. Implementations must be safe for
+// concurrent use. Deterministic tokenizers, which always map a value to
+// the same token, keep tokenized records searchable and let
+// Payout().Correlate match transactions by account.
+type Tokenizer interface {
+	Tokenize(ctx context.Context, kind PIIKind, value string) (string, error)
+}
+
+// TokenizerFunc adapts a function, such as a call to a vault service, to a
+// Tokenizer.
+type TokenizerFunc func(ctx context.Context, kind PIIKind, value string) (string, error)
+
+// Tokenize implements Tokenizer.
+func (f TokenizerFunc) Tokenize(ctx context.Context, kind PIIKind, value string) (string, error) {
+	return f(ctx, kind, value)
+}
+
+// hmacTokenizer is the Tokenizer returned by NewHMACTokenizer.
+type hmacTokenizer struct {
+	key []byte
+}
+
+// NewHMACTokenizer returns a deterministic Tokenizer producing tokens of
+// the form "tok_<kind>_<hex>", keyed with key. Tokens cannot be reversed;
+// anyone holding key can check whether a token belongs to a known value.
+//
+// Example:
+//
+//	client, err := intasend.New(
+//	    intasend.WithSecretKey(secret),
+//	    intasend.WithTrackingStore(store),
+//	    intasend.WithTokenizer(intasend.NewHMACTokenizer([]byte(os.Getenv("PII_TOKEN_KEY")))),
+//	)
+func NewHMACTokenizer(key []byte) Tokenizer {
+	return &hmacTokenizer{key: append([]byte(nil), key...)}
+}
+
+// Tokenize implements Tokenizer.
+func (t *hmacTokenizer) Tokenize(_ context.Context, kind PIIKind, value string) (string, error) {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return "tok_" + string(kind) + "_" + hex.EncodeToString(mac.Sum(nil)[:16]), nil
+}
+
+// tokenize returns value tokenized by t. Empty values are returned as is.
+func tokenize(ctx context.Context, t Tokenizer, kind PIIKind, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	token, err := t.Tokenize(ctx, kind, value)
+	if err != nil {
+		return "", fmt.Errorf("intasend: tokenize %s: %w", kind, err)
+	}
+	return token, nil
+}
+
+// TokenizeJSON returns body with the string values of personal data fields
+// ("phone_number", "email", "name", "first_name", "last_name" and
+// "account"), at any depth, replaced by t's tokens. Use it to tokenize
+// request or webhook bodies before storing them. Object keys in the result
+// are sorted.
+func TokenizeJSON(ctx context.Context, t Tokenizer, body []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("intasend: tokenize JSON: %w", err)
+	}
+	v, err := tokenizeValue(ctx, t, "", v)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("intasend: tokenize JSON: %w", err)
+	}
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n")), nil
+}
+
+// tokenizeValue tokenizes v, found under key, and its children.
+func tokenizeValue(ctx context.Context, t Tokenizer, key string, v interface{}) (interface{}, error) {
+	var err error
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, child := range x {
+			if x[k], err = tokenizeValue(ctx, t, k, child); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, child := range x {
+			if x[i], err = tokenizeValue(ctx, t, key, child); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		if kind, ok := piiFields[key]; ok {
+			return tokenize(ctx, t, kind, x)
+		}
+	}
+	return v, nil
+}
+
+// tokenizePayout replaces the personal data in a tracked payout record.
+func tokenizePayout(ctx context.Context, t Tokenizer, record *TrackedPayout) error {
+	var err error
+	for i := range record.Request.Transactions {
+		tx := &record.Request.Transactions[i]
+		if tx.Name, err = tokenize(ctx, t, PIIName, tx.Name); err != nil {
+			return err
+		}
+		if tx.Account, err = tokenize(ctx, t, PIIAccount, tx.Account); err != nil {
+			return err
+		}
+	}
+	for i := range record.Response.Transactions {
+		tx := &record.Response.Transactions[i]
+		if tx.Name, err = tokenize(ctx, t, PIIName, tx.Name); err != nil {
+			return err
+		}
+		if tx.Account, err = tokenize(ctx, t, PIIAccount, tx.Account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
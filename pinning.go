@@ -0,0 +1,114 @@
+package intasend
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// pinPrefix is the optional prefix of a pin, as used by HPKP and curl.
+const pinPrefix = "sha256/"
+
+// WithCertificatePinning requires the API's TLS certificate chain to
+// contain a public key matching one of pins. Each pin is the base64
+// SHA-256 digest of a certificate's SubjectPublicKeyInfo, optionally
+// prefixed with "sha256/"; PinFromCertificate computes one. Pinning is
+// checked in addition to normal certificate verification.
+//
+// Pin more than one key, such as the current intermediate and a backup,
+// so a certificate rotation does not take payments down. Connections that
+// fail the check return an error matching ErrCertificatePinMismatch and
+// are not retried.
+//
+// Only the chains verified against the system or configured roots are
+// checked, never the certificates as sent by the server, so a server cannot
+// pass the check by appending a pinned certificate to its own chain.
+//
+// Pinning is installed on a copy of the HTTP client's *http.Transport, or
+// of http.DefaultTransport when none is set. New fails if a custom client
+// uses another RoundTripper, or skips certificate verification with
+// InsecureSkipVerify.
+//
+// Example:
+//
+//	client, err := intasend.New(
+//	    intasend.WithSecretKey(os.Getenv("INTASEND_SECRET_KEY")),
+//	    intasend.WithCertificatePinning(
+//	        "sha256/YLh1dUR9y6Kja30RrAn7JKnbQG/uEtLMkBgFF2Fuihg=",
+//	        "sha256/Vjs8r4z+80wjNcr1YKepWQboSIRi63WsWXhIMN+eWys=",
+//	    ),
+//	)
+func WithCertificatePinning(pins ...string) Option {
+	return func(c *Client) error {
+		if len(pins) == 0 {
+			return errors.New("intasend: certificate pinning needs at least one pin")
+		}
+		set := make(map[string]bool, len(pins))
+		for _, pin := range pins {
+			pin = strings.TrimPrefix(pin, pinPrefix)
+			if sum, err := base64.StdEncoding.DecodeString(pin); err != nil || len(sum) != sha256.Size {
+				return fmt.Errorf("intasend: invalid certificate pin %q", pin)
+			}
+			set[pin] = true
+		}
+		c.pins = set
+		return nil
+	}
+}
+
+// PinFromCertificate returns the pin of cert's public key in the
+// "sha256/<base64>" form accepted by WithCertificatePinning.
+func PinFromCertificate(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return pinPrefix + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// pinTransport installs pin verification on a copy of hc's transport.
+func pinTransport(hc *http.Client, pins map[string]bool) error {
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("intasend: certificate pinning needs an *http.Transport, got %T", rt)
+	}
+	if base.TLSClientConfig != nil && base.TLSClientConfig.InsecureSkipVerify {
+		return errors.New("intasend: certificate pinning cannot be used with InsecureSkipVerify")
+	}
+	t := base.Clone()
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	next := t.TLSClientConfig.VerifyConnection
+	t.TLSClientConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		if !matchesPin(cs, pins) {
+			return fmt.Errorf("%w for %s", ErrCertificatePinMismatch, cs.ServerName)
+		}
+		if next != nil {
+			return next(cs)
+		}
+		return nil
+	}
+	hc.Transport = t
+	return nil
+}
+
+// matchesPin reports whether any verified chain for the connection has a
+// pinned public key. The unverified PeerCertificates are ignored: anyone
+// can append a public intermediate to them.
+func matchesPin(cs tls.ConnectionState, pins map[string]bool) bool {
+	for _, chain := range cs.VerifiedChains {
+		for _, cert := range chain {
+			if pins[strings.TrimPrefix(PinFromCertificate(cert), pinPrefix)] {
+				return true
+			}
+		}
+	}
+	return false
+}
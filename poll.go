@@ -0,0 +1,178 @@
+package intasend
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Defaults for polling helpers.
+const (
+	// DefaultPollInterval is the time between status checks while a
+	// payment's state is not yet known.
+	DefaultPollInterval = 3 * time.Second
+
+	// DefaultMinPollInterval and DefaultMaxPollInterval bound adaptive
+	// polling.
+	DefaultMinPollInterval = time.Second
+	DefaultMaxPollInterval = 5 * time.Second
+
+	// DefaultSTKPromptExpiry is how long an STK prompt stays open on the
+	// customer's handset before M-Pesa discards it.
+	DefaultSTKPromptExpiry = 60 * time.Second
+)
+
+// PollOptions configures helpers that poll for a terminal status.
+//
+// By default polling adapts to the payment: while it is PENDING on the
+// customer, checks are made every MaxInterval; once it is PROCESSING and
+// about to settle, every MinInterval. A Retry-After header on a status
+// response takes precedence and may exceed MaxInterval or Interval; only
+// the timeout caps it.
+type PollOptions struct {
+	// Interval, if set, is a fixed time between status checks, disabling
+	// adaptive polling.
+	Interval time.Duration
+
+	// MinInterval and MaxInterval bound adaptive polling. They default to
+	// DefaultMinPollInterval and DefaultMaxPollInterval.
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	// Timeout bounds how long to wait for a terminal status. Each helper
+	// documents its own default.
+	Timeout time.Duration
+}
+
+// next returns the wait before the next status check, given the last
+// observed state and any server hint.
+func (o *PollOptions) next(state string, hint time.Duration) time.Duration {
+	if o != nil && o.Interval > 0 {
+		if hint > o.Interval {
+			return hint
+		}
+		return o.Interval
+	}
+	lo, hi := DefaultMinPollInterval, DefaultMaxPollInterval
+	if o != nil && o.MinInterval > 0 {
+		lo = o.MinInterval
+	}
+	if o != nil && o.MaxInterval > 0 {
+		hi = o.MaxInterval
+	}
+	if hi < lo {
+		hi = lo
+	}
+
+	// The server's hint is only bounded below: it asked not to be polled
+	// sooner, so waiting less would just be throttled again.
+	if hint > 0 {
+		if hint < lo {
+			return lo
+		}
+		return hint
+	}
+
+	d := DefaultPollInterval
+	switch state {
+	case StateProcessing:
+		d = lo
+	case StatePending:
+		d = hi
+	}
+	if d < lo {
+		return lo
+	}
+	if d > hi {
+		return hi
+	}
+	return d
+}
+
+// timeout returns the effective timeout, or def when none is set.
+func (o *PollOptions) timeout(def time.Duration) time.Duration {
+	if o == nil || o.Timeout <= 0 {
+		return def
+	}
+	return o.Timeout
+}
+
+// responseHeaderKey is the context key for withResponseHeader.
+type responseHeaderKey struct{}
+
+// withResponseHeader returns a context whose successful requests store
+// their response headers in h, letting helpers read hints such as
+// Retry-After without widening every service method.
+func withResponseHeader(ctx context.Context, h *http.Header) context.Context {
+	return context.WithValue(ctx, responseHeaderKey{}, h)
+}
+
+// responseHeaderFromContext returns the header sink set by
+// withResponseHeader, or nil.
+func responseHeaderFromContext(ctx context.Context) *http.Header {
+	h, _ := ctx.Value(responseHeaderKey{}).(*http.Header)
+	return h
+}
+
+// parseRetryAfter parses a Retry-After value in seconds or as an HTTP
+// date, returning zero if it is absent or invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// pollUntil calls check until it reports a terminal state, waiting between
+// checks as described by opts and the Retry-After of the last response.
+// When opts.Timeout passes, check runs once more before ErrWaitTimeout is
+// returned, so a state reached at the last moment is not missed.
+func pollUntil(ctx context.Context, opts *PollOptions, check func(ctx context.Context) (state string, done bool, err error)) error {
+	var header http.Header
+	pollCtx := withResponseHeader(ctx, &header)
+
+	var deadline <-chan time.Time
+	if opts != nil && opts.Timeout > 0 {
+		t := time.NewTimer(opts.Timeout)
+		defer t.Stop()
+		deadline = t.C
+	}
+
+	var timer *time.Timer
+	for {
+		header = nil
+		state, done, err := check(pollCtx)
+		if err != nil || done {
+			return err
+		}
+		wait := opts.next(state, parseRetryAfter(header.Get("Retry-After"), time.Now()))
+		if timer == nil {
+			timer = time.NewTimer(wait)
+			defer timer.Stop()
+		} else {
+			timer.Reset(wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		case <-deadline:
+			header = nil
+			if _, done, err := check(pollCtx); err != nil || done {
+				return err
+			}
+			return ErrWaitTimeout
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package intasend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EndpointLatency is the result of probing one base URL.
+type EndpointLatency struct {
+	BaseURL string
+
+	// Latency is the time to receive a response. It is zero when Err is set.
+	Latency time.Duration
+
+	// Err is set when the base URL could not be reached.
+	Err error
+}
+
+// ProbeEndpoints measures the round trip to each base URL configured with
+// WithBaseURLs and makes the fastest reachable one preferred, keeping the
+// others as fallbacks in order of latency. Unreachable URLs are skipped for
+// the failover cooldown. Any HTTP response counts as reachable.
+//
+// IntaSend serves each environment from a single host, so this is useful
+// when the base URLs are your own regional egress proxies, for example one
+// per data centre. Call it at startup, and periodically if network
+// conditions change. Results are returned fastest first, with unreachable
+// URLs last. Without fallback URLs, only the base URL is probed and nothing
+// is reordered.
+//
+// Example:
+//
+//	client, _ := intasend.New(
+//	    intasend.WithSecretKey(secret),
+//	    intasend.WithBaseURLs("https://intasend-egress.nbo.internal/api/v1", "https://intasend-egress.fra.internal/api/v1"),
+//	)
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	defer cancel()
+//	results, err := client.ProbeEndpoints(ctx)
+func (c *Client) ProbeEndpoints(ctx context.Context) ([]EndpointLatency, error) {
+	results := c.probeAll(ctx)
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Err == nil) != (results[j].Err == nil) {
+			return results[i].Err == nil
+		}
+		return results[i].Latency < results[j].Latency
+	})
+	if c.endpoints != nil {
+		c.endpoints.reorder(results)
+	}
+	return results, nil
+}
+
+// probeAll probes the base URLs concurrently, returning the results in
+// priority order.
+func (c *Client) probeAll(ctx context.Context) []EndpointLatency {
+	urls := []string{c.baseURL}
+	if c.endpoints != nil {
+		urls = c.endpoints.order()
+	}
+
+	results := make([]EndpointLatency, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			results[i] = EndpointLatency{BaseURL: u}
+			results[i].Latency, results[i].Err = c.probe(ctx, u)
+		}(i, u)
+	}
+	wg.Wait()
+	return results
+}
+
+// probe times a GET of baseURL.
+func (c *Client) probe(ctx context.Context, baseURL string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/", nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set(headerUserAgent, c.userAgent)
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, &NetworkError{Err: err, Message: "probe failed"}
+	}
+	latency := time.Since(start)
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	_ = resp.Body.Close() // #nosec G104 -- error on close is not critical
+	return latency, nil
+}
+
+// reorder sets the pool's priority to the order of results, marking
+// unreachable URLs down.
+func (p *endpointPool) reorder(results []EndpointLatency) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	until := time.Now().Add(p.cooldown)
+	urls := make([]string, 0, len(results))
+	downUntil := make([]time.Time, 0, len(results))
+	for _, r := range results {
+		urls = append(urls, r.BaseURL)
+		if r.Err != nil {
+			downUntil = append(downUntil, until)
+		} else {
+			downUntil = append(downUntil, time.Time{})
+		}
+	}
+	p.urls, p.downUntil = urls, downUntil
+}
@@ -0,0 +1,383 @@
+// Package provision manages IntaSend wallets and payment links declaratively.
+//
+// Describe the desired state as Go values (or decode it from JSON), compute a
+// plan against the live account, review it, and apply it:
+//
+//	desired := &provision.State{
+//	    Wallets: []provision.WalletSpec{
+//	        {Label: "Operations", Currency: "KES", CanDisburse: true},
+//	    },
+//	    PaymentLinks: []provision.PaymentLinkSpec{
+//	        {Title: "Premium Plan", Currency: "KES", Amount: 5000, IsActive: true},
+//	    },
+//	}
+//
+//	p := provision.New(client)
+//	plan, err := p.Plan(ctx, desired)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Print(plan)
+//	result, err := p.Apply(ctx, plan)
+//
+// The IntaSend API cannot update or delete wallets and payment links, so
+// Apply only creates missing resources. Differences on existing resources
+// are reported as drift, live resources absent from the desired state are
+// reported as unmanaged, and specs matching several live resources are
+// reported as conflicts; all must be resolved in the dashboard. Applying
+// the same state twice is a no-op.
+package provision
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// WalletSpec describes a desired wallet. Wallets are matched by Label and Currency.
+type WalletSpec struct {
+	Label       string `json:"label"`
+	Currency    string `json:"currency"`
+	CanDisburse bool   `json:"can_disburse"`
+}
+
+// key returns the identity used to match the spec against live wallets.
+func (w WalletSpec) key() string {
+	return w.Label + "/" + strings.ToUpper(w.Currency)
+}
+
+// PaymentLinkSpec describes a desired payment link. Links are matched by Title.
+type PaymentLinkSpec struct {
+	Title        string          `json:"title"`
+	Currency     string          `json:"currency"`
+	Amount       float64         `json:"amount,omitempty"`
+	MobileTariff intasend.Tariff `json:"mobile_tariff,omitempty"`
+	CardTariff   intasend.Tariff `json:"card_tariff,omitempty"`
+	IsActive     bool            `json:"is_active"`
+}
+
+// State is the desired set of wallets and payment links.
+type State struct {
+	Wallets      []WalletSpec      `json:"wallets"`
+	PaymentLinks []PaymentLinkSpec `json:"payment_links"`
+}
+
+// ResourceType identifies the kind of resource a change applies to.
+type ResourceType string
+
+const (
+	// ResourceWallet is a wallet.
+	ResourceWallet ResourceType = "wallet"
+
+	// ResourcePaymentLink is a payment link.
+	ResourcePaymentLink ResourceType = "payment_link"
+)
+
+// ChangeKind describes what a plan intends to do with a resource.
+type ChangeKind string
+
+const (
+	// ChangeCreate means the resource is missing and will be created by Apply.
+	ChangeCreate ChangeKind = "create"
+
+	// ChangeDrift means the live resource differs from the spec. The API
+	// cannot update it, so Apply leaves it untouched.
+	ChangeDrift ChangeKind = "drift"
+
+	// ChangeUnmanaged means a live resource is not described by the state.
+	ChangeUnmanaged ChangeKind = "unmanaged"
+
+	// ChangeConflict means several live resources match the spec, so it
+	// cannot tell which one it describes. Apply leaves them untouched.
+	ChangeConflict ChangeKind = "conflict"
+)
+
+// Change is a single entry in a Plan.
+type Change struct {
+	Kind     ChangeKind
+	Resource ResourceType
+
+	// Key is the wallet "label/currency" or the payment link title.
+	Key string
+
+	// ID is the live resource ID for drift and unmanaged changes.
+	ID string
+
+	// IDs lists the matching live resource IDs for ChangeConflict.
+	IDs []string
+
+	// Fields lists the drifted fields for ChangeDrift.
+	Fields []string
+
+	// Wallet is set for wallet creations.
+	Wallet *WalletSpec
+
+	// PaymentLink is set for payment link creations.
+	PaymentLink *PaymentLinkSpec
+}
+
+// String renders the change as a single plan line.
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeCreate:
+		return fmt.Sprintf("+ create %s %q", c.Resource, c.Key)
+	case ChangeDrift:
+		return fmt.Sprintf("~ drift  %s %q (%s): %s", c.Resource, c.Key, c.ID, strings.Join(c.Fields, ", "))
+	case ChangeConflict:
+		return fmt.Sprintf("! conflict %s %q matches %d live resources (%s)", c.Resource, c.Key, len(c.IDs), strings.Join(c.IDs, ", "))
+	default:
+		return fmt.Sprintf("? unmanaged %s %q (%s)", c.Resource, c.Key, c.ID)
+	}
+}
+
+// Plan is the set of differences between the desired and live state.
+type Plan struct {
+	Changes []Change
+}
+
+// HasChanges reports whether Apply would create any resources.
+func (p *Plan) HasChanges() bool {
+	for _, c := range p.Changes {
+		if c.Kind == ChangeCreate {
+			return true
+		}
+	}
+	return false
+}
+
+// Drifted reports whether any live resource differs from its spec.
+func (p *Plan) Drifted() bool {
+	for _, c := range p.Changes {
+		if c.Kind == ChangeDrift {
+			return true
+		}
+	}
+	return false
+}
+
+// Conflicted reports whether any spec matches several live resources.
+func (p *Plan) Conflicted() bool {
+	for _, c := range p.Changes {
+		if c.Kind == ChangeConflict {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the plan one change per line.
+func (p *Plan) String() string {
+	if len(p.Changes) == 0 {
+		return "No changes. Live state matches the desired state.\n"
+	}
+	var b strings.Builder
+	for _, c := range p.Changes {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Result reports the resources created by Apply.
+type Result struct {
+	Wallets      []*intasend.Wallet
+	PaymentLinks []*intasend.PaymentLink
+}
+
+// Provisioner plans and applies desired state against an IntaSend account.
+type Provisioner struct {
+	client *intasend.Client
+}
+
+// New creates a Provisioner using the given client.
+func New(client *intasend.Client) *Provisioner {
+	return &Provisioner{client: client}
+}
+
+// Plan compares the desired state with the live account.
+func (p *Provisioner) Plan(ctx context.Context, desired *State) (*Plan, error) {
+	if err := desired.validate(); err != nil {
+		return nil, err
+	}
+
+	wallets, err := p.client.Wallet().ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("provision: listing wallets: %w", err)
+	}
+	links, err := p.client.PaymentLink().ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("provision: listing payment links: %w", err)
+	}
+
+	plan := &Plan{}
+	plan.Changes = append(plan.Changes, diffWallets(desired.Wallets, wallets)...)
+	plan.Changes = append(plan.Changes, diffPaymentLinks(desired.PaymentLinks, links)...)
+	return plan, nil
+}
+
+// Apply creates the resources planned for creation. It stops at the first
+// failure and returns the resources created so far; re-planning and applying
+// again is safe. Resources are created without the client's WithSandboxTag
+// prefix, so the next Plan matches them by their desired names.
+func (p *Provisioner) Apply(ctx context.Context, plan *Plan) (*Result, error) {
+	result := &Result{}
+	for _, c := range plan.Changes {
+		if c.Kind != ChangeCreate {
+			continue
+		}
+		switch c.Resource {
+		case ResourceWallet:
+			w, err := p.client.Wallet().Create(ctx, &intasend.CreateWalletRequest{
+				Label:       c.Wallet.Label,
+				Currency:    c.Wallet.Currency,
+				CanDisburse: c.Wallet.CanDisburse,
+			}, intasend.WithoutSandboxTag())
+			if err != nil {
+				return result, fmt.Errorf("provision: creating wallet %q: %w", c.Key, err)
+			}
+			result.Wallets = append(result.Wallets, w)
+		case ResourcePaymentLink:
+			l, err := p.client.PaymentLink().Create(ctx, &intasend.CreatePaymentLinkRequest{
+				Title:        c.PaymentLink.Title,
+				Currency:     c.PaymentLink.Currency,
+				Amount:       c.PaymentLink.Amount,
+				MobileTariff: c.PaymentLink.MobileTariff,
+				CardTariff:   c.PaymentLink.CardTariff,
+				IsActive:     c.PaymentLink.IsActive,
+			}, intasend.WithoutSandboxTag())
+			if err != nil {
+				return result, fmt.Errorf("provision: creating payment link %q: %w", c.Key, err)
+			}
+			result.PaymentLinks = append(result.PaymentLinks, l)
+		}
+	}
+	return result, nil
+}
+
+// validate rejects specs that cannot be matched unambiguously.
+func (s *State) validate() error {
+	wallets := make(map[string]bool, len(s.Wallets))
+	for _, w := range s.Wallets {
+		if w.Label == "" || w.Currency == "" {
+			return fmt.Errorf("provision: wallet spec requires label and currency: %+v", w)
+		}
+		if wallets[w.key()] {
+			return fmt.Errorf("provision: duplicate wallet spec %q", w.key())
+		}
+		wallets[w.key()] = true
+	}
+
+	links := make(map[string]bool, len(s.PaymentLinks))
+	for _, l := range s.PaymentLinks {
+		if l.Title == "" || l.Currency == "" {
+			return fmt.Errorf("provision: payment link spec requires title and currency: %+v", l)
+		}
+		if links[l.Title] {
+			return fmt.Errorf("provision: duplicate payment link spec %q", l.Title)
+		}
+		links[l.Title] = true
+	}
+	return nil
+}
+
+// diffWallets compares wallet specs with live wallets.
+func diffWallets(desired []WalletSpec, live []intasend.Wallet) []Change {
+	byKey := make(map[string][]intasend.Wallet, len(live))
+	for _, w := range live {
+		key := WalletSpec{Label: w.Label, Currency: w.Currency}.key()
+		byKey[key] = append(byKey[key], w)
+	}
+
+	var changes []Change
+	matched := make(map[string]bool, len(desired))
+	for i := range desired {
+		spec := desired[i]
+		found := byKey[spec.key()]
+		if len(found) == 0 {
+			changes = append(changes, Change{Kind: ChangeCreate, Resource: ResourceWallet, Key: spec.key(), Wallet: &spec})
+			continue
+		}
+		matched[spec.key()] = true
+		if len(found) > 1 {
+			ids := make([]string, len(found))
+			for j, w := range found {
+				ids[j] = w.WalletID
+			}
+			changes = append(changes, Change{Kind: ChangeConflict, Resource: ResourceWallet, Key: spec.key(), IDs: ids})
+			continue
+		}
+		w := found[0]
+		if w.CanDisburse != spec.CanDisburse {
+			changes = append(changes, Change{
+				Kind: ChangeDrift, Resource: ResourceWallet, Key: spec.key(), ID: w.WalletID,
+				Fields: []string{fmt.Sprintf("can_disburse: live=%t desired=%t", w.CanDisburse, spec.CanDisburse)},
+			})
+		}
+	}
+
+	for _, w := range live {
+		key := WalletSpec{Label: w.Label, Currency: w.Currency}.key()
+		if !matched[key] {
+			changes = append(changes, Change{Kind: ChangeUnmanaged, Resource: ResourceWallet, Key: key, ID: w.WalletID})
+		}
+	}
+	return changes
+}
+
+// diffPaymentLinks compares payment link specs with live links.
+func diffPaymentLinks(desired []PaymentLinkSpec, live []intasend.PaymentLink) []Change {
+	byTitle := make(map[string][]intasend.PaymentLink, len(live))
+	for _, l := range live {
+		byTitle[l.Title] = append(byTitle[l.Title], l)
+	}
+
+	var changes []Change
+	matched := make(map[string]bool, len(desired))
+	for i := range desired {
+		spec := desired[i]
+		found := byTitle[spec.Title]
+		if len(found) == 0 {
+			changes = append(changes, Change{Kind: ChangeCreate, Resource: ResourcePaymentLink, Key: spec.Title, PaymentLink: &spec})
+			continue
+		}
+		matched[spec.Title] = true
+		if len(found) > 1 {
+			ids := make([]string, len(found))
+			for j, l := range found {
+				ids[j] = l.LinkID
+			}
+			changes = append(changes, Change{Kind: ChangeConflict, Resource: ResourcePaymentLink, Key: spec.Title, IDs: ids})
+			continue
+		}
+		l := found[0]
+
+		var fields []string
+		if !strings.EqualFold(l.Currency, spec.Currency) {
+			fields = append(fields, fmt.Sprintf("currency: live=%s desired=%s", l.Currency, spec.Currency))
+		}
+		if l.Amount != spec.Amount {
+			fields = append(fields, fmt.Sprintf("amount: live=%.2f desired=%.2f", l.Amount, spec.Amount))
+		}
+		if spec.MobileTariff != "" && l.MobileTariff != spec.MobileTariff {
+			fields = append(fields, fmt.Sprintf("mobile_tariff: live=%s desired=%s", l.MobileTariff, spec.MobileTariff))
+		}
+		if spec.CardTariff != "" && l.CardTariff != spec.CardTariff {
+			fields = append(fields, fmt.Sprintf("card_tariff: live=%s desired=%s", l.CardTariff, spec.CardTariff))
+		}
+		if l.IsActive != spec.IsActive {
+			fields = append(fields, fmt.Sprintf("is_active: live=%t desired=%t", l.IsActive, spec.IsActive))
+		}
+		if len(fields) > 0 {
+			changes = append(changes, Change{Kind: ChangeDrift, Resource: ResourcePaymentLink, Key: spec.Title, ID: l.LinkID, Fields: fields})
+		}
+	}
+
+	for _, l := range live {
+		if !matched[l.Title] {
+			changes = append(changes, Change{Kind: ChangeUnmanaged, Resource: ResourcePaymentLink, Key: l.Title, ID: l.LinkID})
+		}
+	}
+	return changes
+}
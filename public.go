@@ -0,0 +1,86 @@
+package intasend
+
+import "context"
+
+// PublicClient is a client holding only a publishable key. It exposes just
+// the operations IntaSend allows with that key, so code built on it, such
+// as a service rendering checkout pages, cannot call payout, wallet, or
+// refund APIs even by mistake: they do not compile.
+type PublicClient struct {
+	client     *Client
+	collection *PublicCollectionService
+	checkout   *PublicCheckoutService
+}
+
+// PublicCollectionService is the subset of CollectionService available
+// with a publishable key.
+type PublicCollectionService struct {
+	svc *CollectionService
+}
+
+// PublicCheckoutService is the subset of CheckoutService available with a
+// publishable key.
+type PublicCheckoutService struct {
+	svc *CheckoutService
+}
+
+// NewPublic creates a PublicClient for publishableKey. Options configure
+// it as they do a Client, but passing a secret key, with WithSecretKey or
+// otherwise, returns ErrSecretKeyNotAllowed.
+//
+// Example:
+//
+//	pub, err := intasend.NewPublic(os.Getenv("INTASEND_PUBLISHABLE_KEY"))
+//	checkout, err := pub.Checkout().Create(ctx, req)
+func NewPublic(publishableKey string, opts ...Option) (*PublicClient, error) {
+	if publishableKey == "" {
+		return nil, ErrMissingPublishableKey
+	}
+	// Copy opts so appending never writes into the caller's array.
+	c, err := New(append(append([]Option(nil), opts...), WithPublishableKey(publishableKey))...)
+	if err != nil {
+		return nil, err
+	}
+	if c.keys().secretKey != "" {
+		return nil, ErrSecretKeyNotAllowed
+	}
+	return &PublicClient{
+		client:     c,
+		collection: &PublicCollectionService{svc: c.collection},
+		checkout:   &PublicCheckoutService{svc: c.checkout},
+	}, nil
+}
+
+// Collection returns the public collection operations.
+func (p *PublicClient) Collection() *PublicCollectionService { return p.collection }
+
+// Checkout returns the public checkout operations.
+func (p *PublicClient) Checkout() *PublicCheckoutService { return p.checkout }
+
+// Events returns the event service of the underlying client.
+func (p *PublicClient) Events() *EventService { return p.client.Events() }
+
+// PublishableKey returns the client's publishable key.
+func (p *PublicClient) PublishableKey() string { return p.client.PublishableKey() }
+
+// Charge creates a checkout session; see CollectionService.Charge.
+func (s *PublicCollectionService) Charge(ctx context.Context, req *ChargeRequest, reqOpts ...RequestOption) (*ChargeResponse, error) {
+	return s.svc.Charge(ctx, req, reqOpts...)
+}
+
+// Status checks the payment status for an invoice; see
+// CollectionService.Status.
+func (s *PublicCollectionService) Status(ctx context.Context, invoiceID string, opts *StatusOptions, reqOpts ...RequestOption) (*StatusResponse, error) {
+	return s.svc.Status(ctx, invoiceID, opts, reqOpts...)
+}
+
+// Create creates a checkout session; see CheckoutService.Create.
+func (s *PublicCheckoutService) Create(ctx context.Context, req *CreateCheckoutRequest, reqOpts ...RequestOption) (*CreateCheckoutResponse, error) {
+	return s.svc.Create(ctx, req, reqOpts...)
+}
+
+// CheckStatus checks the status of a checkout session; see
+// CheckoutService.CheckStatus.
+func (s *PublicCheckoutService) CheckStatus(ctx context.Context, req *CheckoutStatusRequest, reqOpts ...RequestOption) (*CheckoutStatusResponse, error) {
+	return s.svc.CheckStatus(ctx, req, reqOpts...)
+}
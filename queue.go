@@ -0,0 +1,288 @@
+package intasend
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/emilio-kariuki/intasend-go/backoff"
+)
+
+// Defaults for CollectQueue.
+const (
+	DefaultQueueWorkers       = 4
+	DefaultQueueMaxAttempts   = 3
+	DefaultQueueBackoff       = 30 * time.Second
+	DefaultQueueMaxBackoff    = 10 * time.Minute
+	DefaultQueuePhoneCooldown = 2 * time.Minute
+)
+
+// CollectQueueOptions configures a CollectQueue.
+type CollectQueueOptions struct {
+	// Workers is the number of STK pushes sent concurrently.
+	// Defaults to DefaultQueueWorkers.
+	Workers int
+
+	// MaxAttempts is the number of times a request is sent before it is
+	// reported as failed. Defaults to DefaultQueueMaxAttempts.
+	MaxAttempts int
+
+	// Backoff is the wait before the first retry; it doubles on each
+	// further attempt up to MaxBackoff. Defaults to DefaultQueueBackoff.
+	Backoff time.Duration
+
+	// MaxBackoff caps the retry wait. Defaults to DefaultQueueMaxBackoff.
+	MaxBackoff time.Duration
+
+	// PhoneCooldown is the minimum time between two pushes to the same
+	// phone number, including retries. Defaults to DefaultQueuePhoneCooldown.
+	PhoneCooldown time.Duration
+
+	// OnOutcome is called once per request when it succeeds, fails
+	// permanently, or is abandoned because the queue's context ended.
+	// It is called from worker goroutines and must be safe for concurrent use.
+	OnOutcome func(CollectOutcome)
+}
+
+// CollectOutcome is the terminal result of a queued STK push.
+type CollectOutcome struct {
+	// Request is the request as enqueued.
+	Request *STKPushRequest
+
+	// Response is set when the push was accepted.
+	Response *STKPushResponse
+
+	// Attempts is the number of pushes sent.
+	Attempts int
+
+	// Err is the last error, or nil on success.
+	Err error
+}
+
+// CollectQueue sends STK push requests in the background, retrying failures
+// where the push cannot have reached the customer (connection failures, and
+// 429 and 503 responses) with exponential backoff, and spacing pushes to
+// the same phone by a cooldown so a customer is not prompted repeatedly.
+//
+// Failures after the request may have been processed, such as a timeout
+// waiting for the response or a 500, are not retried: a second push could
+// prompt and charge the customer twice. They are reported as outcomes, so
+// the caller can look the invoice up by its api_ref before pushing again.
+//
+// Each attempt also goes through the client's own request retries; the
+// queue's backoff is intended for longer outages measured in minutes.
+//
+// Example:
+//
+//	queue := client.Collection().NewQueue(ctx, &intasend.CollectQueueOptions{
+//	    OnOutcome: func(o intasend.CollectOutcome) {
+//	        if o.Err != nil {
+//	            log.Printf("billing %s failed after %d attempts: %v", o.Request.APIRef, o.Attempts, o.Err)
+//	        }
+//	    },
+//	})
+//	defer queue.Close()
+//
+//	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 500, APIRef: "sub-42"})
+type CollectQueue struct {
+	ctx     context.Context
+	service *CollectionService
+	opts    CollectQueueOptions
+
+	jobs    chan *collectJob
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+
+	mu       sync.Mutex
+	closed   bool
+	lastPush map[string]time.Time
+}
+
+// collectJob is a queued request and its attempt count.
+type collectJob struct {
+	req      *STKPushRequest
+	attempts int
+	lastErr  error
+}
+
+// NewQueue starts a CollectQueue. Cancelling ctx abandons requests that have
+// not yet succeeded; Close waits for all enqueued requests to finish.
+func (s *CollectionService) NewQueue(ctx context.Context, opts *CollectQueueOptions) *CollectQueue {
+	q := &CollectQueue{
+		ctx:      ctx,
+		service:  s,
+		jobs:     make(chan *collectJob),
+		lastPush: make(map[string]time.Time),
+	}
+	if opts != nil {
+		q.opts = *opts
+	}
+	if q.opts.Workers <= 0 {
+		q.opts.Workers = DefaultQueueWorkers
+	}
+	if q.opts.MaxAttempts <= 0 {
+		q.opts.MaxAttempts = DefaultQueueMaxAttempts
+	}
+	if q.opts.Backoff <= 0 {
+		q.opts.Backoff = DefaultQueueBackoff
+	}
+	if q.opts.MaxBackoff <= 0 {
+		q.opts.MaxBackoff = DefaultQueueMaxBackoff
+	}
+	if q.opts.PhoneCooldown <= 0 {
+		q.opts.PhoneCooldown = DefaultQueuePhoneCooldown
+	}
+
+	for i := 0; i < q.opts.Workers; i++ {
+		q.workers.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Enqueue adds a request to the queue without blocking. The request must not
+// be modified afterwards.
+func (q *CollectQueue) Enqueue(req *STKPushRequest) error {
+	if req == nil {
+		return ErrNilRequest
+	}
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrQueueClosed
+	}
+	q.pending.Add(1)
+	q.mu.Unlock()
+
+	q.schedule(&collectJob{req: req}, 0)
+	return nil
+}
+
+// Close stops accepting requests and waits until every enqueued request has
+// reached an outcome.
+func (q *CollectQueue) Close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	q.pending.Wait()
+	close(q.jobs)
+	q.workers.Wait()
+}
+
+// schedule hands the job to a worker after delay.
+func (q *CollectQueue) schedule(job *collectJob, delay time.Duration) {
+	go func() {
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-q.ctx.Done():
+				q.abandon(job)
+				return
+			}
+		}
+		select {
+		case q.jobs <- job:
+		case <-q.ctx.Done():
+			q.abandon(job)
+		}
+	}()
+}
+
+func (q *CollectQueue) work() {
+	defer q.workers.Done()
+	for job := range q.jobs {
+		q.process(job)
+	}
+}
+
+// process sends one attempt, honouring the phone cooldown.
+func (q *CollectQueue) process(job *collectJob) {
+	if q.ctx.Err() != nil {
+		q.abandon(job)
+		return
+	}
+
+	phone := normalizePhone(job.req.PhoneNumber)
+	now := time.Now()
+	q.mu.Lock()
+	if len(q.lastPush) > throttleSweepSize {
+		for k, at := range q.lastPush {
+			if now.Sub(at) >= q.opts.PhoneCooldown {
+				delete(q.lastPush, k)
+			}
+		}
+	}
+	if next := q.lastPush[phone].Add(q.opts.PhoneCooldown); now.Before(next) {
+		q.mu.Unlock()
+		q.schedule(job, next.Sub(now))
+		return
+	}
+	q.lastPush[phone] = now
+	q.mu.Unlock()
+
+	resp, err := q.service.MPesaSTKPush(q.ctx, job.req)
+	var throttled *ThrottledError
+	if errors.As(err, &throttled) {
+		// Rejected before sending; wait out the client's throttle.
+		q.schedule(job, throttled.RetryAfter)
+		return
+	}
+	job.attempts++
+	if err == nil {
+		q.finish(job, resp, nil)
+		return
+	}
+	job.lastErr = err
+	if !notSent(err) || job.attempts >= q.opts.MaxAttempts {
+		q.finish(job, nil, err)
+		return
+	}
+
+	q.schedule(job, backoff.Exponential(q.opts.Backoff, q.opts.MaxBackoff, job.attempts))
+}
+
+// abandon finishes a job because the queue's context ended, keeping the
+// last attempt's error for context.
+func (q *CollectQueue) abandon(job *collectJob) {
+	err := q.ctx.Err()
+	if job.lastErr != nil {
+		err = errors.Join(err, job.lastErr)
+	}
+	q.finish(job, nil, err)
+}
+
+// finish reports the outcome, and failures to the client's dead-letter
+// handler, and releases the job.
+func (q *CollectQueue) finish(job *collectJob, resp *STKPushResponse, err error) {
+	if q.opts.OnOutcome != nil {
+		q.opts.OnOutcome(CollectOutcome{Request: job.req, Response: resp, Attempts: job.attempts, Err: err})
+	}
+	if err != nil {
+		q.service.client.deadLetter(DeadLetterCollectQueue, job.req, job.attempts, err)
+	}
+	q.pending.Done()
+}
+
+// notSent reports whether err shows the request was not processed, so it
+// can be sent again without risking a second charge: the connection could
+// not be made, or the API refused the request with 429 or 503.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if IsNetworkError(err) && errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if apiErr := AsAPIError(err); apiErr != nil {
+		return apiErr.HTTPStatusCode == http.StatusTooManyRequests || apiErr.HTTPStatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package intasend
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RateProvider supplies the exchange rates used by PreviewAmounts, for
+// example from a foreign exchange API or rates cached in your database.
+// The SDK does not wrap an IntaSend rates endpoint, so rates come from the
+// provider configured with WithRateProvider. Implementations must be safe
+// for concurrent use, and should cache rates, as a preview asks for one
+// rate per currency.
+type RateProvider interface {
+	// Rate returns the number of units of currency to that one unit of
+	// currency from buys.
+	Rate(ctx context.Context, from, to string) (Amount, error)
+}
+
+// RateProviderFunc adapts a function to a RateProvider.
+type RateProviderFunc func(ctx context.Context, from, to string) (Amount, error)
+
+// Rate implements RateProvider.
+func (f RateProviderFunc) Rate(ctx context.Context, from, to string) (Amount, error) {
+	return f(ctx, from, to)
+}
+
+// AmountPreview is a payment link's amount converted into another
+// currency, for display only: the customer still pays in the link's
+// currency.
+type AmountPreview struct {
+	// Currency is the currency converted into, such as "USD".
+	Currency string
+
+	// Amount is the converted amount, rounded half away from zero to
+	// cents.
+	Amount Amount
+
+	// Rate is the rate the amount was converted at.
+	Rate Amount
+}
+
+// Text returns the amount with its currency, such as "USD 38.50". Prefix
+// it with "≈" when showing it next to the link's price.
+func (p AmountPreview) Text() string { return formatMoney(p.Currency, p.Amount.Float64()) }
+
+// PreviewAmounts converts a payment link's amount into each of currencies
+// with the client's RateProvider, in the order given, so a payment page
+// can show "≈ USD 38.50" next to the KES price. It returns
+// ErrNoRateProvider if none is configured, and ErrInvalidAmount for links
+// without a fixed amount.
+//
+// Example:
+//
+//	previews, err := client.PaymentLink().PreviewAmounts(ctx, link, "USD", "EUR")
+//	if err != nil {
+//	    return err
+//	}
+//	for _, p := range previews {
+//	    fmt.Printf("%s (≈ %s)\n", formatKES(link.Amount), p.Text())
+//	}
+func (s *PaymentLinkService) PreviewAmounts(ctx context.Context, link *PaymentLink, currencies ...string) ([]AmountPreview, error) {
+	if err := s.client.checkCall(link == nil); err != nil {
+		return nil, err
+	}
+	if s.client.rateProvider == nil {
+		return nil, ErrNoRateProvider
+	}
+	amount := link.AmountDecimal()
+	if amount.Cmp("0") <= 0 {
+		return nil, fmt.Errorf("%w: payment link %s has no fixed amount", ErrInvalidAmount, link.LinkID)
+	}
+	from := strings.ToUpper(s.client.currency(link.Currency))
+	if from == "" {
+		return nil, fmt.Errorf("%w: currency", ErrIncompleteRequest)
+	}
+
+	previews := make([]AmountPreview, 0, len(currencies))
+	for _, to := range currencies {
+		to = strings.ToUpper(to)
+		rate := Amount("1")
+		if to != from {
+			var err error
+			rate, err = s.client.rateProvider.Rate(ctx, from, to)
+			if err != nil {
+				return nil, fmt.Errorf("intasend: rate from %s to %s: %w", from, to, err)
+			}
+			if rate.Cmp("0") <= 0 {
+				return nil, fmt.Errorf("intasend: rate from %s to %s: %w: got %q", from, to, ErrInvalidAmount, rate)
+			}
+		}
+		previews = append(previews, AmountPreview{Currency: to, Amount: convertAmount(amount, rate), Rate: rate})
+	}
+	return previews, nil
+}
+
+// convertAmount returns a*rate rounded half away from zero to cents.
+func convertAmount(a, rate Amount) Amount {
+	return Amount(new(big.Rat).Mul(a.rat(), rate.rat()).FloatString(maxAmountDecimals))
+}
@@ -0,0 +1,159 @@
+package intasend
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// invoiceMethodNames are the customer-facing names of payment channels.
+var invoiceMethodNames = map[PaymentMethod]string{
+	PaymentMethodMPesa:    "M-Pesa",
+	PaymentMethodCard:     "Card",
+	PaymentMethodBankACH:  "Bank transfer",
+	PaymentMethodBitcoin:  "Bitcoin",
+	PaymentMethodIntaSend: "IntaSend wallet",
+}
+
+// ReceiptSummary is a customer-facing summary of a paid invoice, with
+// fields ready to use in email and SMS templates. It is built locally from
+// data already fetched.
+type ReceiptSummary struct {
+	InvoiceID string
+
+	// Reference is your reference for the payment, the invoice's api_ref.
+	Reference string
+
+	// Currency is the invoice currency, such as "KES".
+	Currency string
+
+	// Amount is the amount paid.
+	Amount float64
+
+	// Fees are the transaction charges.
+	Fees float64
+
+	// Net is the amount received after fees.
+	Net float64
+
+	// Method is the payment channel's display name, such as "M-Pesa" or
+	// "Card".
+	Method string
+
+	// MPesaCode is the M-Pesa receipt code the customer received by SMS,
+	// empty for other methods.
+	MPesaCode string
+
+	CustomerName  string
+	CustomerEmail string
+	CustomerPhone string
+
+	// PaidAt is when the invoice was completed.
+	PaidAt time.Time
+}
+
+// NewReceiptSummary builds a summary from a completed invoice and, if
+// known, its customer, so confirmation emails and SMS render the same
+// details everywhere. It returns ErrNotPaid unless the invoice is COMPLETE.
+//
+// Example:
+//
+//	status, err := client.Collection().Status(ctx, invoiceID, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	summary, err := intasend.NewReceiptSummary(status.Invoice, status.Customer)
+//	if err != nil {
+//	    return err
+//	}
+//	tmpl := template.Must(template.New("sms").Parse(
+//	    "Received {{.AmountText}} via {{.Method}} ({{.MPesaCode}}) for {{.Reference}}. Thank you!"))
+//	err = tmpl.Execute(&sms, summary)
+func NewReceiptSummary(inv *Invoice, customer *CustomerInfo) (*ReceiptSummary, error) {
+	if inv == nil {
+		return nil, errors.New("intasend: receipt summary needs an invoice")
+	}
+	if inv.State != StateComplete {
+		return nil, ErrNotPaid
+	}
+
+	r := &ReceiptSummary{
+		InvoiceID: inv.InvoiceID,
+		Reference: inv.APIRef,
+		Currency:  inv.Currency,
+		Amount:    inv.Value,
+		Fees:      inv.Charges,
+		Net:       inv.NetAmount,
+		MPesaCode: inv.MPesaReference,
+		PaidAt:    inv.UpdatedAt,
+	}
+	if r.Net == 0 {
+		r.Net = roundCents(r.Amount - r.Fees)
+	}
+	if r.PaidAt.IsZero() {
+		r.PaidAt = inv.CreatedAt
+	}
+
+	provider := inv.ProviderType()
+	if name, ok := invoiceMethodNames[provider]; ok {
+		r.Method = name
+	} else {
+		r.Method = inv.Provider
+	}
+
+	if customer != nil {
+		r.CustomerName = strings.TrimSpace(customer.FirstName + " " + customer.LastName)
+		r.CustomerEmail = customer.Email
+		r.CustomerPhone = customer.PhoneNumber
+	}
+	if r.CustomerPhone == "" && provider == PaymentMethodMPesa {
+		r.CustomerPhone = inv.Account
+	}
+	return r, nil
+}
+
+// ReceiptSummary builds a receipt summary from the status response; see
+// NewReceiptSummary.
+func (r *StatusResponse) ReceiptSummary() (*ReceiptSummary, error) {
+	return NewReceiptSummary(r.Invoice, r.Customer)
+}
+
+// AmountText returns the amount with its currency, such as "KES 1,500.00".
+func (r *ReceiptSummary) AmountText() string { return formatMoney(r.Currency, r.Amount) }
+
+// FeesText returns the fees with their currency, such as "KES 45.00".
+func (r *ReceiptSummary) FeesText() string { return formatMoney(r.Currency, r.Fees) }
+
+// NetText returns the net amount with its currency, such as "KES 1,455.00".
+func (r *ReceiptSummary) NetText() string { return formatMoney(r.Currency, r.Net) }
+
+// roundCents rounds v to two decimal places.
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// formatMoney formats v with two decimals and thousands separators,
+// prefixed with currency when it is set.
+func formatMoney(currency string, v float64) string {
+	s := strconv.FormatFloat(math.Abs(v), 'f', 2, 64)
+	whole, frac := s[:len(s)-3], s[len(s)-3:]
+
+	var b strings.Builder
+	if currency != "" {
+		b.WriteString(currency)
+		b.WriteByte(' ')
+	}
+	if v < 0 && roundCents(v) != 0 {
+		b.WriteByte('-')
+	}
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	b.WriteString(frac)
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package intasend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// maxRedirects is the number of redirects followed before giving up.
+const maxRedirects = 10
+
+// headerIdempotencyKey carries the key set with ContextWithIdempotencyKey.
+const headerIdempotencyKey = "Idempotency-Key"
+
+// idempotencyKeyKey is the context key for ContextWithIdempotencyKey.
+type idempotencyKeyKey struct{}
+
+// ContextWithIdempotencyKey returns a context whose requests carry key in
+// the Idempotency-Key header. A POST carrying a key may follow 307 and 308
+// redirects, which re-send the same method and body; without one, the SDK
+// refuses to follow redirects of POST requests so a payment is never sent
+// twice or silently turned into a GET.
+//
+// Example:
+//
+//	ctx := intasend.ContextWithIdempotencyKey(ctx, "order-123-attempt-1")
+//	resp, err := client.Collection().MPesaSTKPush(ctx, req)
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// idempotencyKeyFromContext returns the key set with
+// ContextWithIdempotencyKey, or an empty string.
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// RedirectError is returned when the SDK refuses to follow a redirect.
+type RedirectError struct {
+	// Method is the method of the original request.
+	Method string
+
+	// StatusCode is the redirect status.
+	StatusCode int
+
+	// Location is the redirect target.
+	Location string
+
+	// Reason explains why the redirect was not followed.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("intasend: refused %d redirect of %s to %s: %s", e.StatusCode, e.Method, e.Location, e.Reason)
+}
+
+// Is reports whether target is ErrRedirectRefused.
+func (e *RedirectError) Is(target error) bool {
+	return target == ErrRedirectRefused
+}
+
+// checkRedirect returns the redirect policy installed on the SDK's HTTP
+// client. GET and HEAD requests follow redirects. POST requests follow only
+// 307 and 308 redirects, which preserve the method and body, and only when
+// they carry an idempotency key. Redirects from HTTPS to plain HTTP are
+// always refused. next, the HTTP client's own policy, is consulted for
+// redirects the SDK allows.
+func checkRedirect(next func(*http.Request, []*http.Request) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		orig := via[0]
+		refuse := func(reason string) error {
+			e := &RedirectError{Method: orig.Method, Location: req.URL.String(), Reason: reason}
+			if req.Response != nil {
+				e.StatusCode = req.Response.StatusCode
+			}
+			return e
+		}
+
+		switch {
+		case len(via) >= maxRedirects:
+			return refuse(fmt.Sprintf("stopped after %d redirects", maxRedirects))
+		case orig.URL.Scheme == "https" && req.URL.Scheme != "https":
+			return refuse("redirect leaves HTTPS")
+		case isIdempotent(orig.Method):
+		case req.Method != orig.Method:
+			return refuse("the request would be re-sent as " + req.Method)
+		case orig.Header.Get(headerIdempotencyKey) == "":
+			return refuse("the request has no idempotency key")
+		}
+
+		if next != nil {
+			return next(req, via)
+		}
+		return nil
+	}
+}
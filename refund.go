@@ -2,7 +2,9 @@ package intasend
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -31,6 +33,102 @@ const (
 	RefundReasonOther RefundReason = "OTHER"
 )
 
+// DisputeCategory is a merchant-side classification of a customer dispute.
+type DisputeCategory string
+
+const (
+	// DisputeDuplicateCharge means the customer was charged more than once.
+	DisputeDuplicateCharge DisputeCategory = "duplicate_charge"
+
+	// DisputeUnauthorized means the customer did not authorize the payment.
+	DisputeUnauthorized DisputeCategory = "unauthorized"
+
+	// DisputeNotDelivered means the goods or service were never provided.
+	DisputeNotDelivered DisputeCategory = "not_delivered"
+
+	// DisputeCancelled means the customer cancelled the order.
+	DisputeCancelled DisputeCategory = "cancelled"
+
+	// DisputeNotAsDescribed means the goods or service differed from what
+	// was sold, such as a defective product.
+	DisputeNotAsDescribed DisputeCategory = "not_as_described"
+
+	// DisputeOther covers disputes that fit no other category.
+	DisputeOther DisputeCategory = "other"
+)
+
+// RefundReasonGuide is the suggested RefundReason for a dispute category.
+type RefundReasonGuide struct {
+	// Reason is the RefundReason to submit.
+	Reason RefundReason
+
+	// Guidance explains what to put in ReasonDetails.
+	Guidance string
+
+	// RequiresDetails reports whether ReasonDetails must be set.
+	RequiresDetails bool
+}
+
+var disputeReasons = map[DisputeCategory]RefundReasonGuide{
+	DisputeDuplicateCharge: {
+		Reason:   RefundReasonDuplicatePayment,
+		Guidance: "Reference the invoice ID of the payment that is being kept.",
+	},
+	DisputeUnauthorized: {
+		Reason:   RefundReasonFraudulent,
+		Guidance: "Describe how the customer reported the payment and any fraud case number.",
+	},
+	DisputeNotDelivered: {
+		Reason:   RefundReasonServiceUnavailable,
+		Guidance: "State what was not delivered and why.",
+	},
+	DisputeCancelled: {
+		Reason:   RefundReasonCustomerRequest,
+		Guidance: "Note when and how the customer cancelled.",
+	},
+	DisputeNotAsDescribed: {
+		Reason:          RefundReasonOther,
+		Guidance:        "Describe how the goods or service differed from the order.",
+		RequiresDetails: true,
+	},
+}
+
+// RefundReasonForDispute maps a dispute category to the RefundReason to
+// submit, with guidance for ReasonDetails. Unknown categories map to
+// RefundReasonOther, which requires ReasonDetails.
+//
+// Example:
+//
+//	guide := intasend.RefundReasonForDispute(intasend.DisputeDuplicateCharge)
+//	chargeback, err := client.Refund().Create(ctx, &intasend.CreateChargebackRequest{
+//	    Invoice:       "INV-123",
+//	    Amount:        500,
+//	    Reason:        guide.Reason,
+//	    ReasonDetails: "Duplicate of INV-122",
+//	})
+func RefundReasonForDispute(category DisputeCategory) RefundReasonGuide {
+	if guide, ok := disputeReasons[category]; ok {
+		return guide
+	}
+	return RefundReasonGuide{
+		Reason:          RefundReasonOther,
+		Guidance:        "Explain the reason for the refund.",
+		RequiresDetails: true,
+	}
+}
+
+// validateRefundReason rejects unknown reasons, and RefundReasonOther
+// without details, which the API refuses with an unhelpful message.
+func validateRefundReason(req *CreateChargebackRequest) error {
+	if err := validateEnum("reason", string(req.Reason), req.Reason.IsValid()); err != nil {
+		return err
+	}
+	if req.Reason == RefundReasonOther && strings.TrimSpace(req.ReasonDetails) == "" {
+		return ErrReasonDetailsRequired
+	}
+	return nil
+}
+
 // Chargeback represents a refund/chargeback record.
 type Chargeback struct {
 	ChargebackID  string       `json:"chargeback_id"`
@@ -43,9 +141,15 @@ type Chargeback struct {
 	UpdatedAt     time.Time    `json:"updated_at"`
 }
 
-// ChargebackListResponse represents the response from listing chargebacks.
+// AmountDecimal returns Amount as an exact decimal.
+func (c *Chargeback) AmountDecimal() Amount { return AmountFromFloat(c.Amount) }
+
+// ChargebackListResponse represents a page of chargebacks.
 type ChargebackListResponse struct {
-	Results []Chargeback `json:"results"`
+	Count    int          `json:"count,omitempty"`
+	Next     string       `json:"next,omitempty"`
+	Previous string       `json:"previous,omitempty"`
+	Results  []Chargeback `json:"results"`
 }
 
 // CreateChargebackRequest represents a request to create a chargeback.
@@ -54,6 +158,18 @@ type CreateChargebackRequest struct {
 	Amount        float64      `json:"amount"`
 	Reason        RefundReason `json:"reason"`
 	ReasonDetails string       `json:"reason_details,omitempty"`
+
+	// AmountDecimal, if set, is sent instead of Amount, exactly as given.
+	AmountDecimal Amount `json:"-"`
+}
+
+// MarshalJSON encodes the request with its amount formatted for the API.
+func (r CreateChargebackRequest) MarshalJSON() ([]byte, error) {
+	type plain CreateChargebackRequest
+	return json.Marshal(struct {
+		plain
+		Amount Amount `json:"amount"`
+	}{plain(r), requestAmount(r.AmountDecimal, r.Amount)})
 }
 
 // Chargeback states
@@ -64,12 +180,14 @@ const (
 	ChargebackStatusComplete = "COMPLETE"
 )
 
-// List returns all chargebacks/refunds.
+// List returns the first page of chargebacks/refunds. Use ListAll for
+// accounts with more chargebacks than fit on a page.
 //
 // Example:
 //
 //	refunds, err := client.Refund().List(ctx)
-func (s *RefundService) List(ctx context.Context) (*ChargebackListResponse, error) {
+func (s *RefundService) List(ctx context.Context, reqOpts ...RequestOption) (*ChargebackListResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	var resp ChargebackListResponse
 	if err := s.client.get(ctx, "/chargebacks/", &resp); err != nil {
 		return nil, err
@@ -77,7 +195,29 @@ func (s *RefundService) List(ctx context.Context) (*ChargebackListResponse, erro
 	return &resp, nil
 }
 
-// Create initiates a new refund/chargeback request.
+// ListAll returns every chargeback/refund, following pagination.
+//
+// Example:
+//
+//	refunds, err := client.Refund().ListAll(ctx)
+func (s *RefundService) ListAll(ctx context.Context, reqOpts ...RequestOption) ([]Chargeback, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var all []Chargeback
+	for page := 1; ; page++ {
+		var resp ChargebackListResponse
+		if err := s.client.get(ctx, withQuery("/chargebacks/", pageQuery(page)), &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Results...)
+		if resp.Next == "" || len(resp.Results) == 0 {
+			return all, nil
+		}
+	}
+}
+
+// Create initiates a new refund/chargeback request. RefundReasonOther
+// requires ReasonDetails and is otherwise rejected with
+// ErrReasonDetailsRequired.
 //
 // Example:
 //
@@ -87,11 +227,26 @@ func (s *RefundService) List(ctx context.Context) (*ChargebackListResponse, erro
 //	    Reason:        intasend.RefundReasonCustomerRequest,
 //	    ReasonDetails: "Customer requested cancellation",
 //	})
-func (s *RefundService) Create(ctx context.Context, req *CreateChargebackRequest) (*Chargeback, error) {
+func (s *RefundService) Create(ctx context.Context, req *CreateChargebackRequest, reqOpts ...RequestOption) (*Chargeback, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateRefundReason(req); err != nil {
+		return nil, err
+	}
+
 	var resp Chargeback
 	if err := s.client.post(ctx, "/chargebacks/", req, &resp); err != nil {
 		return nil, err
 	}
+	if s.client.events.active() {
+		actor, _ := ActorFromContext(ctx)
+		s.client.events.publish(ctx, RefundCreated{Request: req, Response: &resp, Actor: actor})
+	}
 	return &resp, nil
 }
 
@@ -100,10 +255,113 @@ func (s *RefundService) Create(ctx context.Context, req *CreateChargebackRequest
 // Example:
 //
 //	chargeback, err := client.Refund().Get(ctx, "CHG-123")
-func (s *RefundService) Get(ctx context.Context, chargebackID string) (*Chargeback, error) {
+func (s *RefundService) Get(ctx context.Context, chargebackID string, reqOpts ...RequestOption) (*Chargeback, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	var resp Chargeback
 	if err := s.client.get(ctx, fmt.Sprintf("/chargebacks/%s/", chargebackID), &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
+
+// RefundableAmount describes how much of an invoice can still be refunded.
+type RefundableAmount struct {
+	// InvoiceID is the invoice the amounts refer to.
+	InvoiceID string
+
+	// InvoiceValue is the original amount paid on the invoice.
+	InvoiceValue float64
+
+	// Refunded is the sum of prior chargebacks that were not rejected.
+	Refunded float64
+
+	// Remaining is the amount that can still be refunded.
+	Remaining float64
+}
+
+// Refundable returns the remaining refundable amount for an invoice: the
+// invoice value minus all prior chargebacks that were not rejected, across
+// every page of chargebacks. It returns ErrNotPaid unless the invoice is
+// COMPLETE, as nothing else can be refunded.
+//
+// Example:
+//
+//	refundable, err := client.Refund().Refundable(ctx, "INV-123")
+//	fmt.Printf("Can still refund %.2f\n", refundable.Remaining)
+func (s *RefundService) Refundable(ctx context.Context, invoiceID string, reqOpts ...RequestOption) (*RefundableAmount, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	status, err := s.client.Collection().Status(ctx, invoiceID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if status.Invoice == nil {
+		return nil, fmt.Errorf("intasend: invoice %s not found in status response", invoiceID)
+	}
+	if status.Invoice.State != StateComplete {
+		return nil, fmt.Errorf("%w: invoice %s is %s", ErrNotPaid, invoiceID, status.Invoice.State)
+	}
+
+	chargebacks, err := s.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sum exactly, so three refunds of 33.33, 33.33, and 33.34 leave
+	// nothing of 100 rather than a fraction of a cent.
+	value := status.Invoice.ValueDecimal()
+	var refunded Amount
+	for _, cb := range chargebacks {
+		if cb.Invoice == invoiceID && cb.Status != ChargebackStatusRejected {
+			refunded = refunded.Add(cb.AmountDecimal())
+		}
+	}
+	remaining := value.Sub(refunded)
+	if remaining.Cmp("0") < 0 {
+		remaining = "0"
+	}
+	return &RefundableAmount{
+		InvoiceID:    invoiceID,
+		InvoiceValue: value.Float64(),
+		Refunded:     refunded.Float64(),
+		Remaining:    remaining.Float64(),
+	}, nil
+}
+
+// CreateValidated checks the requested amount against Refundable before
+// creating the chargeback, so over-refunds are rejected locally with
+// ErrRefundExceedsBalance instead of failing late at the API.
+//
+// Example:
+//
+//	chargeback, err := client.Refund().CreateValidated(ctx, &intasend.CreateChargebackRequest{
+//	    Invoice: "INV-123",
+//	    Amount:  250,
+//	    Reason:  intasend.RefundReasonCustomerRequest,
+//	})
+//	if errors.Is(err, intasend.ErrRefundExceedsBalance) {
+//	    // offer a smaller refund
+//	}
+func (s *RefundService) CreateValidated(ctx context.Context, req *CreateChargebackRequest, reqOpts ...RequestOption) (*Chargeback, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	requested := requestAmount(req.AmountDecimal, req.Amount)
+	if requested.Cmp("0") <= 0 {
+		return nil, ErrInvalidRefundAmount
+	}
+	if err := validateRefundReason(req); err != nil {
+		return nil, err
+	}
+
+	refundable, err := s.Refundable(ctx, req.Invoice)
+	if err != nil {
+		return nil, err
+	}
+	if remaining := AmountFromFloat(refundable.Remaining); requested.Cmp(remaining) > 0 {
+		return nil, fmt.Errorf("%w: requested %s, remaining %s",
+			ErrRefundExceedsBalance, requested, remaining)
+	}
+
+	return s.Create(ctx, req)
+}
@@ -0,0 +1,100 @@
+package intasend
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOption customizes a single API call, overriding the client's
+// configuration for that call only. Options apply to every request the
+// call makes, including the polling of the Wait* methods. Every service
+// method that calls the API accepts them as trailing arguments, except
+// PaymentLink().PreviewAmounts, which makes no API call.
+//
+// Example:
+//
+//	resp, err := client.Collection().MPesaSTKPush(ctx, req,
+//	    intasend.WithRequestTimeout(5*time.Second),
+//	    intasend.WithHeader("X-Trace-ID", traceID),
+//	)
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the overrides set by RequestOptions.
+type requestOptions struct {
+	timeout    time.Duration
+	retries    int
+	setRetries bool
+	header     http.Header
+	untagged   bool
+}
+
+// WithRequestTimeout bounds the call, including its retries, by d instead
+// of the client's timeout, whether d is shorter or longer. A deadline
+// already on the context still applies if it is sooner.
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithRequestRetries sets how many times the call's requests are retried,
+// instead of the client's WithRetry setting. Zero disables retries.
+func WithRequestRetries(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.retries = n
+		o.setRetries = true
+	}
+}
+
+// WithHeader sets a header on the call's requests, replacing any value set
+// with WithHeaders. Headers the SDK manages cannot be set this way:
+// Authorization, the public key headers, Content-Type, User-Agent, and
+// Idempotency-Key are ignored.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Set(key, value)
+	}
+}
+
+// WithoutSandboxTag creates the call's wallets and payment links without
+// the WithSandboxTag prefix, for resources meant to outlive the run, such
+// as those kept by the provision package.
+func WithoutSandboxTag() RequestOption {
+	return func(o *requestOptions) {
+		o.untagged = true
+	}
+}
+
+// requestOptionsKey is the context key for the options of a call.
+type requestOptionsKey struct{}
+
+// withRequestOptions returns a context carrying opts on top of any options
+// already on ctx, so helpers called by a service method see them without
+// threading them through every signature.
+func withRequestOptions(ctx context.Context, opts []RequestOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	var o requestOptions
+	if prev := requestOptionsFromContext(ctx); prev != nil {
+		o = *prev
+		o.header = prev.header.Clone()
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, &o)
+}
+
+// requestOptionsFromContext returns the options set by withRequestOptions,
+// or nil.
+func requestOptionsFromContext(ctx context.Context) *requestOptions {
+	o, _ := ctx.Value(requestOptionsKey{}).(*requestOptions)
+	return o
+}
@@ -0,0 +1,113 @@
+package intasend
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// SandboxService helps keep the sandbox account tidy when tests and CI runs
+// create resources with test keys.
+type SandboxService struct {
+	client *Client
+}
+
+// LeftoverOptions selects the tagged resources Leftovers returns.
+type LeftoverOptions struct {
+	// Tag is the tag to look for. Defaults to the client's WithSandboxTag.
+	Tag string
+
+	// Before limits results to resources last changed before this time,
+	// leaving those of runs still in progress alone. Zero means no limit.
+	Before time.Time
+}
+
+// Leftovers lists the tagged resources in a sandbox account.
+type Leftovers struct {
+	Wallets      []Wallet
+	PaymentLinks []PaymentLink
+}
+
+// Leftovers finds wallets and payment links created with WithSandboxTag.
+// IntaSend offers no API to delete wallets, payment links, or invoices, so
+// they cannot be purged; use the result to reuse resources across runs
+// instead of creating new ones, or to clean up in the dashboard.
+//
+// Leftovers returns ErrNotSandbox for clients using live keys.
+//
+// Example:
+//
+//	left, err := client.Sandbox().Leftovers(ctx, &intasend.LeftoverOptions{
+//	    Before: time.Now().Add(-24 * time.Hour),
+//	})
+//	for _, w := range left.Wallets {
+//	    log.Printf("stale test wallet %s (%s)", w.WalletID, w.Label)
+//	}
+func (s *SandboxService) Leftovers(ctx context.Context, opts *LeftoverOptions, reqOpts ...RequestOption) (*Leftovers, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if !s.client.usesTestKeys() {
+		return nil, ErrNotSandbox
+	}
+	var o LeftoverOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Tag == "" {
+		o.Tag = s.client.sandboxTag
+	}
+	if o.Tag == "" {
+		return nil, ErrNoSandboxTag
+	}
+	prefix := sandboxTagPrefix(o.Tag)
+	old := func(t time.Time) bool { return o.Before.IsZero() || t.Before(o.Before) }
+
+	wallets, err := s.client.Wallet().ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.client.PaymentLink().ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Leftovers
+	for _, w := range wallets {
+		if strings.HasPrefix(w.Label, prefix) && old(w.UpdatedAt) {
+			result.Wallets = append(result.Wallets, w)
+		}
+	}
+	for _, l := range links {
+		if strings.HasPrefix(l.Title, prefix) && old(l.UpdatedAt) {
+			result.PaymentLinks = append(result.PaymentLinks, l)
+		}
+	}
+	return &result, nil
+}
+
+// usesTestKeys reports whether the client's keys are sandbox keys.
+func (c *Client) usesTestKeys() bool {
+	creds := c.keys()
+	return strings.HasPrefix(creds.publishableKey, "ISPubKey_test") ||
+		strings.HasPrefix(creds.secretKey, "ISSecretKey_test")
+}
+
+// sandboxTagged prefixes name with the sandbox tag when one is configured,
+// the client uses test keys, and the call did not opt out with
+// WithoutSandboxTag.
+func (c *Client) sandboxTagged(ctx context.Context, name string) string {
+	if c.sandboxTag == "" || !c.usesTestKeys() {
+		return name
+	}
+	if o := requestOptionsFromContext(ctx); o != nil && o.untagged {
+		return name
+	}
+	prefix := sandboxTagPrefix(c.sandboxTag)
+	if strings.HasPrefix(name, prefix) {
+		return name
+	}
+	return prefix + name
+}
+
+func sandboxTagPrefix(tag string) string {
+	return "[" + tag + "] "
+}
@@ -0,0 +1,150 @@
+package intasend
+
+import (
+	"context"
+	"net/http"
+)
+
+// CollectionAPI is the core of CollectionService, for code that accepts
+// payments and wants to depend on an interface.
+type CollectionAPI interface {
+	Charge(ctx context.Context, req *ChargeRequest, reqOpts ...RequestOption) (*ChargeResponse, error)
+	MPesaSTKPush(ctx context.Context, req *STKPushRequest, reqOpts ...RequestOption) (*STKPushResponse, error)
+	Status(ctx context.Context, invoiceID string, opts *StatusOptions, reqOpts ...RequestOption) (*StatusResponse, error)
+}
+
+// PayoutAPI is the core of PayoutService.
+type PayoutAPI interface {
+	Initiate(ctx context.Context, req *InitiateRequest, reqOpts ...RequestOption) (*InitiateResponse, error)
+	Approve(ctx context.Context, req *ApproveRequest, reqOpts ...RequestOption) (*ApproveResponse, error)
+	Status(ctx context.Context, trackingID string, reqOpts ...RequestOption) (*PayoutStatusResponse, error)
+}
+
+// WalletAPI is the core of WalletService.
+type WalletAPI interface {
+	List(ctx context.Context, reqOpts ...RequestOption) (*WalletListResponse, error)
+	Create(ctx context.Context, req *CreateWalletRequest, reqOpts ...RequestOption) (*Wallet, error)
+	Get(ctx context.Context, walletID string, reqOpts ...RequestOption) (*Wallet, error)
+	ListTransactions(ctx context.Context, walletID string, opts *WalletTransactionListOptions, reqOpts ...RequestOption) (*WalletTransactionsResponse, error)
+	IntraTransfer(ctx context.Context, req *IntraTransferRequest, reqOpts ...RequestOption) (*IntraTransferResponse, error)
+}
+
+// RefundAPI is the core of RefundService.
+type RefundAPI interface {
+	List(ctx context.Context, reqOpts ...RequestOption) (*ChargebackListResponse, error)
+	Create(ctx context.Context, req *CreateChargebackRequest, reqOpts ...RequestOption) (*Chargeback, error)
+	Get(ctx context.Context, chargebackID string, reqOpts ...RequestOption) (*Chargeback, error)
+}
+
+// CheckoutAPI is the core of CheckoutService.
+type CheckoutAPI interface {
+	Create(ctx context.Context, req *CreateCheckoutRequest, reqOpts ...RequestOption) (*CreateCheckoutResponse, error)
+	CheckStatus(ctx context.Context, req *CheckoutStatusRequest, reqOpts ...RequestOption) (*CheckoutStatusResponse, error)
+}
+
+// PaymentLinkAPI is the core of PaymentLinkService.
+type PaymentLinkAPI interface {
+	List(ctx context.Context, reqOpts ...RequestOption) (*PaymentLinkListResponse, error)
+	Create(ctx context.Context, req *CreatePaymentLinkRequest, reqOpts ...RequestOption) (*PaymentLink, error)
+	Get(ctx context.Context, linkID string, reqOpts ...RequestOption) (*PaymentLink, error)
+}
+
+var (
+	_ CollectionAPI  = (*CollectionService)(nil)
+	_ PayoutAPI      = (*PayoutService)(nil)
+	_ WalletAPI      = (*WalletService)(nil)
+	_ RefundAPI      = (*RefundService)(nil)
+	_ CheckoutAPI    = (*CheckoutService)(nil)
+	_ PaymentLinkAPI = (*PaymentLinkService)(nil)
+)
+
+// Dependencies is everything NewServices needs, as plain fields so a
+// dependency injection framework can provide them.
+type Dependencies struct {
+	PublishableKey string
+	SecretKey      string
+
+	// BaseURL overrides the environment detected from the keys.
+	BaseURL string
+
+	// HTTPClient sends the requests. Defaults to a client without its own
+	// timeout; DefaultTimeout bounds each request through its context.
+	HTTPClient *http.Client
+
+	// Logger receives log output. Defaults to the standard logger.
+	Logger Logger
+
+	// StructuredLogger, if set, receives log records instead of Logger.
+	StructuredLogger StructuredLogger
+
+	// Metrics, if set, observes every request.
+	Metrics MetricsCollector
+
+	// Debug enables debug logging.
+	Debug bool
+}
+
+// Services holds a client's services behind narrow interfaces, so
+// consumers can depend on just the operations they use and substitute
+// fakes in tests. Client gives access to everything else.
+type Services struct {
+	Client      *Client
+	Collection  CollectionAPI
+	Payout      PayoutAPI
+	Wallet      WalletAPI
+	Refund      RefundAPI
+	Checkout    CheckoutAPI
+	PaymentLink PaymentLinkAPI
+}
+
+// NewServices creates a client from explicit dependencies, without
+// functional options, and returns its services as interfaces. It is meant
+// as a provider for dependency injection frameworks such as Wire or
+// Go kit; use New for anything it does not cover.
+//
+// Example:
+//
+//	func provideIntaSend(hc *http.Client, logger *log.Logger, m intasend.MetricsCollector) (*intasend.Services, error) {
+//	    return intasend.NewServices(intasend.Dependencies{
+//	        SecretKey:  os.Getenv("INTASEND_SECRET_KEY"),
+//	        HTTPClient: hc,
+//	        Logger:     logger,
+//	        Metrics:    m,
+//	    })
+//	}
+func NewServices(deps Dependencies) (*Services, error) {
+	opts := []Option{
+		WithPublishableKey(deps.PublishableKey),
+		WithSecretKey(deps.SecretKey),
+		WithDebug(deps.Debug),
+	}
+	if deps.BaseURL != "" {
+		opts = append(opts, WithBaseURL(deps.BaseURL))
+	}
+	if deps.HTTPClient != nil {
+		opts = append(opts, WithHTTPClient(deps.HTTPClient))
+	}
+	if deps.Logger != nil {
+		opts = append(opts, WithLogger(deps.Logger))
+	}
+	if deps.StructuredLogger != nil {
+		opts = append(opts, WithStructuredLogger(deps.StructuredLogger))
+	}
+	if deps.Metrics != nil {
+		opts = append(opts, WithMetricsCollector(deps.Metrics))
+	}
+
+	c, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Services{
+		Client:      c,
+		Collection:  c.collection,
+		Payout:      c.payout,
+		Wallet:      c.wallet,
+		Refund:      c.refund,
+		Checkout:    c.checkout,
+		PaymentLink: c.paymentLink,
+	}, nil
+}
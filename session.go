@@ -0,0 +1,238 @@
+package intasend
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultPaymentSessionTimeout is how long CreateAndWait waits for payment
+// when no timeout is set.
+const DefaultPaymentSessionTimeout = 10 * time.Minute
+
+// PaymentSessionOutcome is the terminal result of a time-boxed payment
+// session.
+type PaymentSessionOutcome string
+
+const (
+	// PaymentSessionPaid means the customer paid before the session
+	// expired.
+	PaymentSessionPaid PaymentSessionOutcome = "PAID"
+
+	// PaymentSessionFailed means the session ran out of time with the last
+	// payment attempt failed, for example because it was declined.
+	PaymentSessionFailed PaymentSessionOutcome = "FAILED"
+
+	// PaymentSessionExpired means the session ran out of time unpaid.
+	// Release any inventory held for it.
+	PaymentSessionExpired PaymentSessionOutcome = "EXPIRED"
+)
+
+// PaymentSessionResult is returned by CreateAndWait.
+type PaymentSessionResult struct {
+	// Outcome is the terminal result. It is empty when waiting stopped
+	// early because of an error or a cancelled context.
+	Outcome PaymentSessionOutcome
+
+	// Checkout is the created checkout session; send the customer to its
+	// URL.
+	Checkout *CreateCheckoutResponse
+
+	// Invoice is the last invoice state observed, if any.
+	Invoice *Invoice
+}
+
+// sessionWaiters routes invoice updates passed to Checkout().Notify to the
+// CreateAndWait calls waiting on the same api_ref.
+type sessionWaiters struct {
+	mu      sync.Mutex
+	waiters map[string]map[chan *Invoice]struct{}
+}
+
+// add registers a channel for updates to apiRef and returns a function
+// removing it.
+func (w *sessionWaiters) add(apiRef string) (chan *Invoice, func()) {
+	ch := make(chan *Invoice, 1)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.waiters == nil {
+		w.waiters = make(map[string]map[chan *Invoice]struct{})
+	}
+	if w.waiters[apiRef] == nil {
+		w.waiters[apiRef] = make(map[chan *Invoice]struct{})
+	}
+	w.waiters[apiRef][ch] = struct{}{}
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.waiters[apiRef], ch)
+		if len(w.waiters[apiRef]) == 0 {
+			delete(w.waiters, apiRef)
+		}
+	}
+}
+
+// notify delivers inv to the waiters on its api_ref, replacing any update
+// they have not read yet.
+func (w *sessionWaiters) notify(inv *Invoice) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.waiters[inv.APIRef] {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- inv
+	}
+	return len(w.waiters[inv.APIRef]) > 0
+}
+
+// Notify passes an invoice update, typically from a webhook, to any
+// CreateAndWait call waiting on the invoice's api_ref, so it finishes as
+// soon as the update arrives instead of at its next poll. It reports
+// whether a session was waiting.
+//
+// Example:
+//
+//	event, err := webhook.ParseRequest(r, client.WebhookChallenge())
+//	if err == nil && event.Type == webhook.EventInvoice {
+//	    invoice, _ := event.Invoice()
+//	    client.Checkout().Notify(invoice)
+//	}
+func (s *CheckoutService) Notify(inv *Invoice) bool {
+	if inv == nil || inv.APIRef == "" {
+		return false
+	}
+	return s.sessions.notify(inv)
+}
+
+// CreateAndWait creates a checkout and waits until it is paid or the
+// timeout passes, for synchronous flows such as ticket purchases that hold
+// inventory while the customer pays. The timeout defaults to
+// DefaultPaymentSessionTimeout.
+//
+// A failed attempt does not end the wait, since the customer can retry from
+// the checkout page, for example with another card or M-Pesa. The outcome
+// is PaymentSessionFailed only if the last attempt had failed when the
+// timeout passed.
+//
+// Status is polled as described by PollOptions; updates passed to Notify
+// for the request's APIRef end the wait without waiting for the next poll.
+// The status is checked once more when the timeout passes, so a payment
+// made at the last moment is not reported as expired. IntaSend cannot
+// cancel a checkout, so a payment can still arrive after
+// PaymentSessionExpired; handle it from the webhook by refunding or
+// honouring it.
+//
+// onCreate, if not nil, is called with the checkout as soon as it is
+// created, so the customer can be redirected to its URL while waiting.
+//
+// Example:
+//
+//	result, err := client.Checkout().CreateAndWait(ctx, req, &intasend.PollOptions{Timeout: 5 * time.Minute},
+//	    func(c *intasend.CreateCheckoutResponse) { redirect <- c.URL })
+//	switch {
+//	case err != nil:
+//	    return err
+//	case result.Outcome == intasend.PaymentSessionPaid:
+//	    tickets.Confirm(hold)
+//	default:
+//	    tickets.Release(hold)
+//	}
+func (s *CheckoutService) CreateAndWait(ctx context.Context, req *CreateCheckoutRequest, opts *PollOptions, onCreate func(*CreateCheckoutResponse), reqOpts ...RequestOption) (*PaymentSessionResult, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	var updates chan *Invoice
+	if req.APIRef != "" {
+		var done func()
+		updates, done = s.sessions.add(req.APIRef)
+		defer done()
+	}
+
+	checkout, err := s.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &PaymentSessionResult{Checkout: checkout}
+	if onCreate != nil {
+		onCreate(checkout)
+	}
+
+	var header http.Header
+	pollCtx := withResponseHeader(ctx, &header)
+	check := func() error {
+		header = nil
+		status, err := s.CheckStatus(pollCtx, &CheckoutStatusRequest{
+			Signature:  checkout.Signature,
+			CheckoutID: checkout.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if status.Invoice != nil {
+			result.Invoice = status.Invoice
+		}
+		return nil
+	}
+
+	deadline := time.NewTimer(opts.timeout(DefaultPaymentSessionTimeout))
+	defer deadline.Stop()
+	poll := time.NewTimer(opts.next(StatePending, 0))
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case inv := <-updates:
+			result.Invoice = inv
+		case <-poll.C:
+			if err := check(); err != nil {
+				return result, err
+			}
+		case <-deadline.C:
+			if err := check(); err != nil {
+				return result, err
+			}
+			if result.Outcome = sessionOutcome(result.Invoice); result.Outcome == "" {
+				result.Outcome = PaymentSessionExpired
+			}
+			return result, nil
+		}
+
+		if result.Outcome = sessionOutcome(result.Invoice); result.Outcome == PaymentSessionPaid {
+			return result, nil
+		}
+		result.Outcome = ""
+		state := StatePending
+		if result.Invoice != nil && result.Invoice.State != StateFailed {
+			state = result.Invoice.State
+		}
+		if !poll.Stop() {
+			select {
+			case <-poll.C:
+			default:
+			}
+		}
+		poll.Reset(opts.next(state, parseRetryAfter(header.Get("Retry-After"), time.Now())))
+	}
+}
+
+// sessionOutcome maps a terminal invoice state to an outcome, or returns an
+// empty outcome while the payment is still open. A failed invoice is only
+// final once the session's time is up.
+func sessionOutcome(inv *Invoice) PaymentSessionOutcome {
+	if inv == nil {
+		return ""
+	}
+	switch inv.State {
+	case StateComplete:
+		return PaymentSessionPaid
+	case StateFailed:
+		return PaymentSessionFailed
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package intasend
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SignatureClaims are the claims carried by a checkout signature, the JWT
+// returned as Signature when a checkout is created.
+type SignatureClaims struct {
+	// Algorithm is the JWT "alg" header.
+	Algorithm string
+
+	// CheckoutID is the checkout session the signature was issued for.
+	CheckoutID string
+
+	// IssuedAt and ExpiresAt are the "iat" and "exp" claims. They are zero
+	// if absent.
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+
+	// Claims holds every claim in the token.
+	Claims map[string]any
+}
+
+// ParseSignature decodes a checkout signature without contacting the API.
+// The token is signed with a key only IntaSend holds, so its cryptographic
+// signature is checked by the API; ParseSignature rejects values that are
+// not well-formed JWTs, and Validate rejects expired signatures and
+// signatures issued for a different checkout. Together they catch
+// truncated, tampered, or mixed-up values before a status request is made.
+//
+// Example:
+//
+//	claims, err := intasend.ParseSignature(session.Signature)
+//	if err == nil {
+//	    err = claims.Validate(session.ID, time.Now())
+//	}
+//	if err != nil {
+//	    return err // don't bother the API
+//	}
+//	status, err := client.Checkout().CheckStatus(ctx, &intasend.CheckoutStatusRequest{
+//	    Signature:  session.Signature,
+//	    CheckoutID: session.ID,
+//	    InvoiceID:  invoiceID,
+//	})
+func ParseSignature(sig string) (*SignatureClaims, error) {
+	parts := strings.Split(sig, ".")
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, fmt.Errorf("%w: not a JWT", ErrInvalidSignature)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidSignature, err)
+	}
+	if header.Alg == "" || strings.EqualFold(header.Alg, "none") {
+		return nil, fmt.Errorf("%w: unsigned token", ErrInvalidSignature)
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrInvalidSignature, err)
+	}
+
+	var claims map[string]any
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidSignature, err)
+	}
+
+	c := &SignatureClaims{Algorithm: header.Alg, Claims: claims}
+	for _, key := range []string{"checkout_id", "id", "sub"} {
+		if id, ok := claims[key].(string); ok && id != "" {
+			c.CheckoutID = id
+			break
+		}
+	}
+	var err error
+	if c.IssuedAt, err = numericDate(claims, "iat"); err != nil {
+		return nil, err
+	}
+	if c.ExpiresAt, err = numericDate(claims, "exp"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate checks that the signature was issued for checkoutID and has not
+// expired at now. An empty checkoutID skips the checkout check.
+func (c *SignatureClaims) Validate(checkoutID string, now time.Time) error {
+	if checkoutID != "" && c.CheckoutID != "" && c.CheckoutID != checkoutID {
+		return fmt.Errorf("%w: issued for checkout %s, not %s", ErrInvalidSignature, c.CheckoutID, checkoutID)
+	}
+	if !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt) {
+		return fmt.Errorf("%w at %s", ErrSignatureExpired, c.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// decodeJWTSegment decodes a base64url JSON segment into v.
+func decodeJWTSegment(seg string, v any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(seg, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// numericDate reads a JWT NumericDate claim, returning the zero time if it
+// is absent.
+func numericDate(claims map[string]any, key string) (time.Time, error) {
+	v, ok := claims[key]
+	if !ok {
+		return time.Time{}, nil
+	}
+	sec, ok := v.(float64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %q is not a number", ErrInvalidSignature, key)
+	}
+	return time.Unix(int64(sec), 0), nil
+}
@@ -0,0 +1,59 @@
+//go:build go1.21
+
+package intasend
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// slogLogger is the StructuredLogger returned by NewSlogLogger.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger adapts l to a StructuredLogger, mapping LogLevelDebug
+// through LogLevelError to the slog levels of the same names and fields
+// to attributes in key order.
+//
+// Example:
+//
+//	client, err := intasend.New(
+//	    intasend.WithSecretKey(secret),
+//	    intasend.WithStructuredLogger(intasend.NewSlogLogger(slog.Default())),
+//	)
+func NewSlogLogger(l *slog.Logger) StructuredLogger {
+	return slogLogger{l: l}
+}
+
+// Log implements StructuredLogger.
+func (s slogLogger) Log(ctx context.Context, level LogLevel, msg string, fields map[string]interface{}) {
+	lvl := slogLevel(level)
+	if !s.l.Enabled(ctx, lvl) {
+		return
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	s.l.LogAttrs(ctx, lvl, msg, attrs...)
+}
+
+// slogLevel converts a LogLevel to its slog equivalent.
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelError:
+		return slog.LevelError
+	}
+	return slog.LevelInfo
+}
@@ -0,0 +1,192 @@
+package intasend
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// supportBundleRequests is the number of recent requests a client keeps
+// for SupportBundle.
+const supportBundleRequests = 50
+
+// SupportBundle returns a JSON document describing the client for
+// attaching to an IntaSend support ticket: the SDK and Go versions, the
+// environment and configuration, the outcome of the client's last 50
+// requests with their request and Ray IDs, and a reachability probe of each
+// base URL.
+//
+// The bundle never contains API keys or request and response bodies.
+// Endpoints have resource IDs replaced by ":id", and secret keys and phone
+// numbers are masked in error messages. The probes use ctx, so bound it
+// with a timeout; a probe that fails or is cut short is reported in the
+// bundle rather than returned as an error.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	defer cancel()
+//	bundle, err := client.SupportBundle(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	err = os.WriteFile("intasend-support.json", bundle, 0o600)
+func (c *Client) SupportBundle(ctx context.Context) ([]byte, error) {
+	if c == nil {
+		return nil, ErrNilClient
+	}
+	creds := c.keys()
+	b := supportBundle{
+		GeneratedAt: time.Now().UTC(),
+		SDK: bundleSDK{
+			Version:   Version,
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+		},
+		Environment:  "custom",
+		BaseURL:      c.baseURL,
+		FallbackURLs: c.fallbackURLs,
+		Config: bundleConfig{
+			Timeout:           c.timeout.String(),
+			MaxRetries:        c.maxRetries,
+			RetryWait:         c.retryWait.String(),
+			MaxConcurrent:     cap(c.inflight),
+			Debug:             c.debug.Load(),
+			PublishableKeySet: creds.publishableKey != "",
+			SecretKeySet:      creds.secretKey != "",
+		},
+	}
+	if c.endpoints != nil {
+		// Report the URLs in the order requests currently try them.
+		urls := c.endpoints.order()
+		b.BaseURL, b.FallbackURLs = urls[0], urls[1:]
+	}
+	switch {
+	case c.IsSandbox():
+		b.Environment = string(Sandbox)
+	case c.IsProduction():
+		b.Environment = string(Production)
+	}
+	if c.limiter != nil {
+		b.Config.RateLimit = c.limiter.rps
+	}
+
+	for _, r := range c.recent.snapshot() {
+		br := bundleRequest{
+			Time:        r.time.UTC(),
+			Method:      r.m.Method,
+			Endpoint:    endpointLabel(r.path),
+			StatusCode:  r.m.StatusCode,
+			RequestID:   r.m.RequestID,
+			DurationMS:  r.m.Duration.Milliseconds(),
+			Retries:     r.m.Retries,
+			RateLimited: r.m.RateLimited,
+		}
+		if r.m.Err != nil {
+			// Network errors quote the request URL, whatever base URL it
+			// went to, so its query is redacted through the path.
+			br.Error = redactError(r.m.Err, r.path)
+			if apiErr := AsAPIError(r.m.Err); apiErr != nil {
+				br.RayID = apiErr.RayID()
+			}
+		}
+		b.RecentRequests = append(b.RecentRequests, br)
+	}
+
+	for _, p := range c.probeAll(ctx) {
+		bp := bundleProbe{BaseURL: p.BaseURL, Reachable: p.Err == nil, LatencyMS: p.Latency.Milliseconds()}
+		if p.Err != nil {
+			bp.Error = redact([]byte(p.Err.Error()))
+		}
+		b.Probes = append(b.Probes, bp)
+	}
+
+	return json.MarshalIndent(b, "", "  ")
+}
+
+type supportBundle struct {
+	GeneratedAt    time.Time       `json:"generated_at"`
+	SDK            bundleSDK       `json:"sdk"`
+	Environment    string          `json:"environment"`
+	BaseURL        string          `json:"base_url"`
+	FallbackURLs   []string        `json:"fallback_urls,omitempty"`
+	Config         bundleConfig    `json:"config"`
+	RecentRequests []bundleRequest `json:"recent_requests"`
+	Probes         []bundleProbe   `json:"probes"`
+}
+
+type bundleSDK struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+type bundleConfig struct {
+	Timeout           string  `json:"timeout"`
+	MaxRetries        int     `json:"max_retries"`
+	RetryWait         string  `json:"retry_wait"`
+	MaxConcurrent     int     `json:"max_concurrent_requests,omitempty"`
+	RateLimit         float64 `json:"rate_limit,omitempty"`
+	Debug             bool    `json:"debug"`
+	PublishableKeySet bool    `json:"publishable_key_set"`
+	SecretKeySet      bool    `json:"secret_key_set"`
+}
+
+type bundleRequest struct {
+	Time        time.Time `json:"time"`
+	Method      string    `json:"method"`
+	Endpoint    string    `json:"endpoint"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	RequestID   string    `json:"request_id,omitempty"`
+	RayID       string    `json:"ray_id,omitempty"`
+	DurationMS  int64     `json:"duration_ms"`
+	Retries     int       `json:"retries,omitempty"`
+	RateLimited int       `json:"rate_limited,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type bundleProbe struct {
+	BaseURL   string `json:"base_url"`
+	Reachable bool   `json:"reachable"`
+	LatencyMS int64  `json:"latency_ms,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// recentRequests is a ring buffer of the client's latest requests.
+type recentRequests struct {
+	mu   sync.Mutex
+	buf  []recentRequest
+	next int
+}
+
+type recentRequest struct {
+	time time.Time
+	// path is the request path, including its query.
+	path string
+	m    RequestMetrics
+}
+
+// add records a finished request, replacing the oldest once full.
+func (r *recentRequests) add(start time.Time, path string, m RequestMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.buf) < supportBundleRequests {
+		r.buf = append(r.buf, recentRequest{time: start, path: path, m: m})
+		return
+	}
+	r.buf[r.next] = recentRequest{time: start, path: path, m: m}
+	r.next = (r.next + 1) % len(r.buf)
+}
+
+// snapshot returns the recorded requests, oldest first.
+func (r *recentRequests) snapshot() []recentRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]recentRequest, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
@@ -0,0 +1,340 @@
+// Package sweep moves a wallet's balance above a floor to a settlement
+// account, such as a daily settlement to the merchant's M-Pesa number or
+// bank account.
+//
+//	s, err := sweep.New(client, sweep.Config{
+//	    WalletID: "WALLET-123",
+//	    Floor:    5000,
+//	    Destination: sweep.Destination{
+//	        Provider: intasend.ProviderPesaLink,
+//	        Name:     "Acme Ltd",
+//	        Account:  "0123456789",
+//	        BankCode: "2",
+//	    },
+//	    RequireApproval: true,
+//	    Approver: func(ctx context.Context, p sweep.Plan) (bool, error) {
+//	        return p.Amount <= 500000, nil
+//	    },
+//	    Audit: func(ctx context.Context, e sweep.Event) {
+//	        log.Printf("sweep %s: %s %.2f %v", e.WalletID, e.Stage, e.Amount, e.Err)
+//	    },
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// On demand:
+//	result, err := s.Run(ctx)
+//
+//	// Or on a schedule, until ctx is cancelled:
+//	err = s.RunEvery(ctx, 24*time.Hour)
+//
+// With RequireApproval set, payouts are initiated as requiring approval. If
+// an Approver is configured it is consulted and approved payouts are
+// released immediately; otherwise they wait for approval in the dashboard.
+package sweep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// Destination is the account swept funds are paid out to.
+type Destination struct {
+	// Provider is intasend.ProviderMPesaB2C, ProviderMPesaB2B, or
+	// ProviderPesaLink.
+	Provider intasend.Provider
+
+	// Name is the account holder's name.
+	Name string
+
+	// Account is the phone number, PayBill/Till number, or bank account.
+	Account string
+
+	// BankCode is required for PesaLink.
+	BankCode string
+
+	// AccountType and AccountReference are used for M-Pesa B2B.
+	AccountType      intasend.AccountType
+	AccountReference string
+
+	// Narrative describes the payout. Defaults to "Settlement sweep".
+	Narrative string
+}
+
+// Plan describes the payout a sweep is about to make.
+type Plan struct {
+	WalletID string
+	Currency string
+
+	// Balance is the wallet's available balance when the sweep ran.
+	Balance float64
+
+	// Floor is the balance left in the wallet.
+	Floor float64
+
+	// Amount is the amount to pay out.
+	Amount float64
+
+	Destination Destination
+}
+
+// Approver decides whether a sweep payout may be released.
+type Approver func(ctx context.Context, plan Plan) (bool, error)
+
+// Stage identifies the point in a sweep an audit event records.
+type Stage string
+
+const (
+	// StageSkipped means the balance did not exceed the floor by at least
+	// MinAmount, so nothing was paid out.
+	StageSkipped Stage = "skipped"
+
+	// StageInitiated means the payout was created.
+	StageInitiated Stage = "initiated"
+
+	// StageApproved means the Approver accepted the payout and it was
+	// released.
+	StageApproved Stage = "approved"
+
+	// StageDeclined means the Approver rejected the payout. It stays
+	// pending in the dashboard.
+	StageDeclined Stage = "declined"
+
+	// StageFailed means the sweep failed; Err holds the reason.
+	StageFailed Stage = "failed"
+)
+
+// Event is passed to the audit hook at each stage of a sweep.
+type Event struct {
+	Time       time.Time
+	Stage      Stage
+	WalletID   string
+	Balance    float64
+	Amount     float64
+	TrackingID string
+	Err        error
+}
+
+// Config configures a Sweeper.
+type Config struct {
+	// WalletID is the wallet to sweep.
+	WalletID string
+
+	// Floor is the available balance to keep in the wallet.
+	Floor float64
+
+	// MinAmount skips sweeps smaller than this amount, avoiding fees on
+	// tiny payouts.
+	MinAmount float64
+
+	// Destination is where swept funds are sent.
+	Destination Destination
+
+	// CallbackURL receives payout status updates.
+	CallbackURL string
+
+	// RequireApproval initiates payouts as requiring approval.
+	RequireApproval bool
+
+	// Approver, if set with RequireApproval, is asked to approve each
+	// payout, which is then released via the API.
+	Approver Approver
+
+	// Audit, if set, is called at each stage of every sweep.
+	Audit func(ctx context.Context, e Event)
+}
+
+func (c *Config) validate() error {
+	if c.WalletID == "" {
+		return errors.New("sweep: WalletID is required")
+	}
+	if c.Floor < 0 || c.MinAmount < 0 {
+		return errors.New("sweep: Floor and MinAmount must not be negative")
+	}
+	d := c.Destination
+	if d.Account == "" {
+		return errors.New("sweep: destination account is required")
+	}
+	switch d.Provider {
+	case intasend.ProviderMPesaB2C:
+	case intasend.ProviderMPesaB2B:
+		if d.AccountType == "" {
+			return errors.New("sweep: M-Pesa B2B destination requires AccountType")
+		}
+	case intasend.ProviderPesaLink:
+		if d.BankCode == "" {
+			return errors.New("sweep: PesaLink destination requires BankCode")
+		}
+	default:
+		return fmt.Errorf("sweep: unsupported destination provider %q", d.Provider)
+	}
+	return nil
+}
+
+// Result is the outcome of a single sweep.
+type Result struct {
+	Plan Plan
+
+	// Skipped reports that nothing was paid out.
+	Skipped bool
+
+	// Payout is the initiated payout, if any.
+	Payout *intasend.InitiateResponse
+
+	// Approved reports that the payout was released by the Approver.
+	Approved bool
+}
+
+// Sweeper sweeps a wallet according to its Config.
+type Sweeper struct {
+	client *intasend.Client
+	cfg    Config
+
+	// mu keeps sweeps of the same wallet from overlapping, which would
+	// pay out the same balance twice.
+	mu sync.Mutex
+}
+
+// New returns a Sweeper for cfg.
+func New(client *intasend.Client, cfg Config) (*Sweeper, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Destination.Narrative == "" {
+		cfg.Destination.Narrative = "Settlement sweep"
+	}
+	return &Sweeper{client: client, cfg: cfg}, nil
+}
+
+// Run performs one sweep: it reads the wallet's available balance and pays
+// out everything above the floor.
+func (s *Sweeper) Run(ctx context.Context) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wallet, err := s.client.Wallet().Get(ctx, s.cfg.WalletID)
+	if err != nil {
+		s.audit(ctx, Event{Stage: StageFailed, Err: err})
+		return nil, err
+	}
+
+	plan := Plan{
+		WalletID:    wallet.WalletID,
+		Currency:    wallet.Currency,
+		Balance:     wallet.AvailableBalance,
+		Floor:       s.cfg.Floor,
+		Amount:      floorCents(wallet.AvailableBalance - s.cfg.Floor),
+		Destination: s.cfg.Destination,
+	}
+	result := &Result{Plan: plan}
+	if plan.Amount <= 0 || plan.Amount < s.cfg.MinAmount {
+		result.Skipped = true
+		s.audit(ctx, Event{Stage: StageSkipped, Balance: plan.Balance, Amount: plan.Amount})
+		return result, nil
+	}
+
+	payout, err := s.initiate(ctx, plan)
+	if err != nil {
+		s.audit(ctx, Event{Stage: StageFailed, Balance: plan.Balance, Amount: plan.Amount, Err: err})
+		return result, err
+	}
+	result.Payout = payout
+	s.audit(ctx, Event{Stage: StageInitiated, Balance: plan.Balance, Amount: plan.Amount, TrackingID: payout.TrackingID})
+
+	if !s.cfg.RequireApproval || s.cfg.Approver == nil {
+		return result, nil
+	}
+
+	ev := Event{Balance: plan.Balance, Amount: plan.Amount, TrackingID: payout.TrackingID}
+	ok, err := s.cfg.Approver(ctx, plan)
+	if err == nil && ok {
+		_, err = s.client.Payout().Approve(ctx, &intasend.ApproveRequest{
+			TrackingID: payout.TrackingID,
+			Nonce:      payout.Nonce,
+			WalletID:   payout.WalletID,
+		})
+	}
+	switch {
+	case err != nil:
+		ev.Stage, ev.Err = StageFailed, err
+		s.audit(ctx, ev)
+		return result, err
+	case ok:
+		result.Approved = true
+		ev.Stage = StageApproved
+	default:
+		ev.Stage = StageDeclined
+	}
+	s.audit(ctx, ev)
+	return result, nil
+}
+
+// RunEvery sweeps immediately and then at every interval until ctx is
+// cancelled, returning ctx.Err(). Failed sweeps are reported to the audit
+// hook and do not stop the schedule.
+func (s *Sweeper) RunEvery(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_, _ = s.Run(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// initiate creates the sweep payout.
+func (s *Sweeper) initiate(ctx context.Context, plan Plan) (*intasend.InitiateResponse, error) {
+	amount, err := intasend.FormatAmount(plan.Amount)
+	if err != nil {
+		return nil, err
+	}
+	d := plan.Destination
+	txn := intasend.Transaction{
+		Name:             d.Name,
+		Account:          d.Account,
+		Amount:           amount,
+		Narrative:        d.Narrative,
+		BankCode:         d.BankCode,
+		AccountType:      string(d.AccountType),
+		AccountReference: d.AccountReference,
+	}
+
+	approval := intasend.ApprovalNotRequired
+	if s.cfg.RequireApproval {
+		approval = intasend.ApprovalRequired
+	}
+	return s.client.Payout().Initiate(ctx, &intasend.InitiateRequest{
+		Provider:         d.Provider,
+		Currency:         plan.Currency,
+		Transactions:     []intasend.Transaction{txn},
+		CallbackURL:      s.cfg.CallbackURL,
+		WalletID:         plan.WalletID,
+		RequiresApproval: approval,
+	})
+}
+
+// floorCents rounds v down to whole cents, ignoring float noise such as
+// 100.1 being stored as 100.0999….
+func floorCents(v float64) float64 {
+	return math.Floor(math.Round(v*1e4)/100) / 100
+}
+
+// audit reports e to the audit hook, if any.
+func (s *Sweeper) audit(ctx context.Context, e Event) {
+	if s.cfg.Audit == nil {
+		return
+	}
+	e.Time = time.Now()
+	e.WalletID = s.cfg.WalletID
+	s.cfg.Audit(ctx, e)
+}
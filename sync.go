@@ -0,0 +1,45 @@
+package intasend
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// SyncToken is an opaque cursor for incremental sync. It records the point
+// up to which changes have been fetched; pass it to the next sync call to
+// fetch only what changed since. The zero value starts a full sync.
+//
+// Tokens are safe to store as text between runs.
+type SyncToken string
+
+// newSyncToken returns a token for the high-water mark t.
+func newSyncToken(t time.Time) SyncToken {
+	return SyncToken(base64.RawURLEncoding.EncodeToString([]byte(t.UTC().Format(time.RFC3339Nano))))
+}
+
+// since decodes the token's high-water mark. The zero token decodes to the
+// zero time.
+func (t SyncToken) since() (time.Time, error) {
+	if t == "" {
+		return time.Time{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(string(t))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidSyncToken, err)
+	}
+	since, err := time.Parse(time.RFC3339Nano, string(raw))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidSyncToken, err)
+	}
+	return since, nil
+}
+
+// advance returns a token for mark if it is later than the token's own
+// high-water mark, and the token itself otherwise.
+func (t SyncToken) advance(since, mark time.Time) SyncToken {
+	if mark.After(since) {
+		return newSyncToken(mark)
+	}
+	return t
+}
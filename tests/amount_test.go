@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"testing/quick"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// plainAmount is what the API accepts: a plain decimal with at most two
+// decimal places.
+var plainAmount = regexp.MustCompile(`^-?[0-9]+(\.[0-9]{1,2})?$`)
+
+// checkAmount reports whether raw is a plain amount within half a cent of v.
+func checkAmount(t *testing.T, raw string, v float64) bool {
+	t.Helper()
+	if !plainAmount.MatchString(raw) {
+		t.Errorf("amount %v encoded as %s", v, raw)
+		return false
+	}
+	got, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.Abs(got-v) > 0.005+math.Abs(v)*1e-15 {
+		t.Errorf("amount %v encoded as %s, off by more than half a cent", v, raw)
+		return false
+	}
+	return true
+}
+
+func TestAmount_RequestsNeverUseExponentsOrExtraDecimals(t *testing.T) {
+	var mu sync.Mutex
+	var raw json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Amount json.RawMessage `json:"amount"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		raw = body.Amount
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	ctx := context.Background()
+	calls := map[string]func(v float64) error{
+		"Charge": func(v float64) error {
+			_, err := client.Collection().Charge(ctx, &intasend.ChargeRequest{Email: "a@example.com", Host: "https://example.com", Amount: v, Currency: "KES"})
+			return err
+		},
+		"MPesaSTKPush": func(v float64) error {
+			_, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: v})
+			return err
+		},
+		"Checkout.Create": func(v float64) error {
+			_, err := client.Checkout().Create(ctx, &intasend.CreateCheckoutRequest{Amount: v, Currency: "KES", Customer: intasend.CheckoutCustomer{Email: "a@example.com"}})
+			return err
+		},
+		"PaymentLink.Create": func(v float64) error {
+			_, err := client.PaymentLink().Create(ctx, &intasend.CreatePaymentLinkRequest{Title: "Dues", Currency: "KES", Amount: v})
+			return err
+		},
+		"Refund.Create": func(v float64) error {
+			_, err := client.Refund().Create(ctx, &intasend.CreateChargebackRequest{Invoice: "INV-1", Amount: v, Reason: intasend.RefundReasonServiceUnavailable})
+			return err
+		},
+		"Wallet.IntraTransfer": func(v float64) error {
+			_, err := client.Wallet().IntraTransfer(ctx, &intasend.IntraTransferRequest{SourceID: "W-1", DestinationID: "W-2", Amount: v, Narrative: "float"})
+			return err
+		},
+		"Wallet.FundMPesa": func(v float64) error {
+			_, err := client.Wallet().FundMPesa(ctx, &intasend.FundMPesaRequest{WalletID: "W-1", PhoneNumber: "254712345678", Amount: v})
+			return err
+		},
+		"Wallet.FundCheckout": func(v float64) error {
+			_, err := client.Wallet().FundCheckout(ctx, &intasend.FundCheckoutRequest{WalletID: "W-1", Amount: v, Currency: "KES", Customer: intasend.WalletCustomer{Email: "a@example.com"}})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		call := call
+		property := func(mantissa float64, exp int8) bool {
+			// Spread amounts from fractions of a cent to beyond 1e21, where
+			// encoding/json switches to exponents.
+			v := math.Abs(mantissa) * math.Pow(10, float64(exp%26))
+			mu.Lock()
+			raw = nil
+			mu.Unlock()
+			if err := call(v); err != nil {
+				return true // rejected before anything was sent
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if raw == nil {
+				return true
+			}
+			return checkAmount(t, string(raw), v)
+		}
+		if err := quick.Check(property, &quick.Config{MaxCount: 200}); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestAmount_Rounding(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{1.005, "1.01"}, // half rounds up, not down to the float's binary value
+		{2.675, "2.68"},
+		{0.125, "0.13"},
+		{1e21, "1000000000000000000000"},
+		{123456789012.345, "123456789012.35"},
+	}
+	for _, tt := range tests {
+		got, err := intasend.FormatAmount(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("FormatAmount(%v) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+		body, err := json.Marshal(intasend.CreateChargebackRequest{Amount: tt.in})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var decoded struct {
+			Amount json.RawMessage `json:"amount"`
+		}
+		json.Unmarshal(body, &decoded)
+		if string(decoded.Amount) != tt.want {
+			t.Errorf("CreateChargebackRequest amount %v encoded as %s, want %s", tt.in, decoded.Amount, tt.want)
+		}
+	}
+	if _, err := json.Marshal(intasend.CreateChargebackRequest{Amount: math.NaN()}); err == nil {
+		t.Error("expected NaN amounts to fail encoding")
+	}
+}
+
+func TestAmount_Decimal(t *testing.T) {
+	parsed := []struct {
+		in, want string
+	}{
+		{"1500.50", "1500.50"},
+		{" 007 ", "7"},
+		{"+0.10", "0.10"},
+		{"-20", "-20"},
+		{"-0.00", "0.00"},
+	}
+	for _, tt := range parsed {
+		got, err := intasend.ParseAmount(tt.in)
+		if err != nil || got != intasend.Amount(tt.want) {
+			t.Errorf("ParseAmount(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1e3", "1.", ".5", "1,000", "NaN"} {
+		if _, err := intasend.ParseAmount(in); !errors.Is(err, intasend.ErrInvalidAmount) {
+			t.Errorf("ParseAmount(%q): expected ErrInvalidAmount, got %v", in, err)
+		}
+	}
+
+	var decoded struct {
+		A, B, C, D intasend.Amount
+	}
+	if err := json.Unmarshal([]byte(`{"A":100.50,"B":"2500.00","C":1.5e3,"D":null}`), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.A != "100.50" || decoded.B != "2500.00" || decoded.C != "1500" || decoded.D != "" {
+		t.Errorf("unexpected decoded amounts: %+v", decoded)
+	}
+	if err := json.Unmarshal([]byte(`{"A":"ten"}`), &decoded); err == nil {
+		t.Error("expected an error for a non-numeric amount")
+	}
+	body, err := json.Marshal(decoded)
+	if err != nil || string(body) != `{"A":100.50,"B":2500.00,"C":1500,"D":null}` {
+		t.Errorf("unexpected encoding %s, %v", body, err)
+	}
+	if _, err := json.Marshal(intasend.Amount("ten")); !errors.Is(err, intasend.ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount encoding an invalid amount, got %v", err)
+	}
+
+	var total intasend.Amount
+	for i := 0; i < 10; i++ {
+		total = total.Add(intasend.AmountFromFloat(0.1))
+	}
+	if total != "1.0" || total.Cmp("1") != 0 || total.Float64() != 1 {
+		t.Errorf("expected ten 0.1s to sum to exactly 1.0, got %s", total)
+	}
+	if got := intasend.MustAmount("100").Sub("33.33").Sub("66.67"); !got.IsZero() || got != "0.00" {
+		t.Errorf("expected 0.00, got %s", got)
+	}
+	if cents, err := intasend.MustAmount("1500.5").Cents(); err != nil || cents != 150050 {
+		t.Errorf("Cents() = %d, %v; want 150050", cents, err)
+	}
+	if _, err := intasend.MustAmount("0.005").Cents(); !errors.Is(err, intasend.ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount for a fraction of a cent, got %v", err)
+	}
+	if got := intasend.AmountFromCents(-150050); got != "-1500.50" {
+		t.Errorf("AmountFromCents(-150050) = %s", got)
+	}
+	if got := (&intasend.Invoice{Value: 123456789012.34}).ValueDecimal(); got != "123456789012.34" {
+		t.Errorf("ValueDecimal() = %s", got)
+	}
+}
+
+func TestAmount_AmountDecimalSentExactly(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Amount json.RawMessage `json:"amount"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, string(body.Amount))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	if _, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{
+		PhoneNumber:   "254712345678",
+		Amount:        1,
+		AmountDecimal: intasend.MustAmount("9007199254740993.10"),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.PaymentLink().Create(ctx, &intasend.CreatePaymentLinkRequest{Title: "Donations", Currency: "KES"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != "9007199254740993.10" || bodies[1] != "" {
+		t.Errorf("expected the decimal verbatim and no amount for an open link, got %q", bodies)
+	}
+}
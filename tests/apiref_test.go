@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestAPIRef_RoundTrip(t *testing.T) {
+	meta := intasend.APIRefMetadata{
+		OrderID:    "ORD 1&2",
+		CustomerID: "C=9",
+		Attempt:    2,
+		Extra:      map[string]string{"plan": "gold"},
+	}
+	ref, err := intasend.EncodeAPIRef(meta)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref != "attempt=2&customer=C%3D9&order=ORD+1%262&plan=gold" {
+		t.Errorf("unexpected encoding %q", ref)
+	}
+
+	got, err := intasend.DecodeAPIRef(ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, meta) {
+		t.Errorf("expected %+v, got %+v", meta, got)
+	}
+}
+
+func TestAPIRef_EncodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		meta intasend.APIRefMetadata
+	}{
+		{"empty", intasend.APIRefMetadata{}},
+		{"reserved extra key", intasend.APIRefMetadata{OrderID: "1", Extra: map[string]string{"order": "2"}}},
+		{"negative attempt", intasend.APIRefMetadata{OrderID: "1", Attempt: -1}},
+		{"too long", intasend.APIRefMetadata{OrderID: strings.Repeat("x", intasend.MaxAPIRefLength)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := intasend.EncodeAPIRef(tt.meta); !errors.Is(err, intasend.ErrInvalidAPIRef) {
+				t.Errorf("expected ErrInvalidAPIRef, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAPIRef_DecodeErrors(t *testing.T) {
+	for _, ref := range []string{"order-123", "order=1&order=2", "order=1&attempt=two", "order=%zz"} {
+		if _, err := intasend.DecodeAPIRef(ref); !errors.Is(err, intasend.ErrInvalidAPIRef) {
+			t.Errorf("%q: expected ErrInvalidAPIRef, got %v", ref, err)
+		}
+	}
+}
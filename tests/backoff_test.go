@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/emilio-kariuki/intasend-go/backoff"
+)
+
+func TestBackoff_Exponential(t *testing.T) {
+	tests := []struct {
+		attempt int
+		max     time.Duration
+		want    time.Duration
+	}{
+		{0, 0, 0},
+		{1, 0, 100 * time.Millisecond},
+		{3, 0, 400 * time.Millisecond},
+		{3, 300 * time.Millisecond, 300 * time.Millisecond},
+		{80, time.Minute, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := backoff.Exponential(100*time.Millisecond, tt.max, tt.attempt); got != tt.want {
+			t.Errorf("Exponential(100ms, %v, %d) = %v, want %v", tt.max, tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff_DecorrelatedBounds(t *testing.T) {
+	d := &backoff.Decorrelated{Base: 10 * time.Millisecond, Max: 200 * time.Millisecond}
+	prev := d.Base
+	for i := 0; i < 1000; i++ {
+		next := d.Next()
+		if next < d.Base || next > d.Max || next > 3*prev {
+			t.Fatalf("delay %v outside [%v, min(%v, 3*%v)]", next, d.Base, d.Max, prev)
+		}
+		prev = next
+		if prev < d.Base {
+			prev = d.Base
+		}
+	}
+	d.Reset()
+	if next := d.Next(); next > 3*d.Base {
+		t.Errorf("expected reset sequence to start near base, got %v", next)
+	}
+}
+
+func TestBackoff_Retry(t *testing.T) {
+	calls := 0
+	err := backoff.Retry(context.Background(), backoff.Policy{Base: time.Millisecond, MaxAttempts: 5}, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("not yet")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected success on third call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestBackoff_RetryStops(t *testing.T) {
+	boom := errors.New("boom")
+
+	calls := 0
+	err := backoff.Retry(context.Background(), backoff.Policy{Base: time.Millisecond, MaxAttempts: 5}, func(ctx context.Context) error {
+		calls++
+		return backoff.Permanent(boom)
+	})
+	if err != boom || calls != 1 {
+		t.Errorf("expected permanent error after 1 call, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = backoff.Retry(context.Background(), backoff.Policy{Base: time.Millisecond, MaxAttempts: 3, Jitter: true}, func(ctx context.Context) error {
+		calls++
+		return boom
+	})
+	if !errors.Is(err, boom) || calls != 3 {
+		t.Errorf("expected last error after 3 calls, got %v after %d calls", err, calls)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = backoff.Retry(ctx, backoff.Policy{Base: time.Hour}, func(ctx context.Context) error { return boom })
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, boom) {
+		t.Errorf("expected deadline and last error, got %v", err)
+	}
+}
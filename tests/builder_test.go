@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestCheckoutBuilder(t *testing.T) {
+	req, err := intasend.NewCheckout(1000, "KES").
+		WithCustomer(intasend.CheckoutCustomer{Email: "john@example.com"}).
+		WithRedirect("https://shop.example.com/callback?order=1").
+		WithAPIRef("order-1").
+		WithMethods(intasend.PaymentMethodMPesa).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Host != "https://shop.example.com" {
+		t.Errorf("expected host from redirect URL, got %q", req.Host)
+	}
+	if req.Amount != 1000 || req.APIRef != "order-1" || len(req.Methods) != 1 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestCheckoutBuilder_MissingFields(t *testing.T) {
+	_, err := intasend.NewCheckout(1000, "").Build()
+	if !errors.Is(err, intasend.ErrIncompleteRequest) {
+		t.Fatalf("expected ErrIncompleteRequest, got %v", err)
+	}
+	verr := intasend.AsValidationError(err)
+	if verr == nil {
+		t.Fatalf("expected *ValidationError, got %T", err)
+	}
+	for _, field := range []string{"currency", "host", "customer"} {
+		if verr.Field(field) == nil {
+			t.Errorf("expected %q to be reported, got %v", field, err)
+		}
+	}
+}
+
+func TestCheckoutBuilder_InvalidValues(t *testing.T) {
+	base := func() *intasend.CheckoutBuilder {
+		return intasend.NewCheckout(1000, "USD").
+			WithHost("https://shop.example.com").
+			WithCustomer(intasend.CheckoutCustomer{PhoneNumber: "254712345678"})
+	}
+	_, err := intasend.NewCheckout(-5, "KES").WithHost("https://a.b").WithCustomer(intasend.CheckoutCustomer{Email: "a@b.c"}).Build()
+	if !hasFieldError(err, "amount", intasend.ErrInvalidAmount) {
+		t.Errorf("expected an invalid amount field, got %v", err)
+	}
+	if _, err := base().WithCustomer(intasend.CheckoutCustomer{}).Build(); !hasFieldError(err, "customer", intasend.ErrIncompleteRequest) {
+		t.Errorf("expected a missing customer contact, got %v", err)
+	}
+	if _, err := base().WithMethods(intasend.PaymentMethodMPesa).Build(); !errors.Is(err, intasend.ErrInvalidPaymentMethods) {
+		t.Errorf("expected ErrInvalidPaymentMethods, got %v", err)
+	}
+	if _, err := base().WithLocale("fr").Build(); !errors.Is(err, intasend.ErrUnsupportedLocale) {
+		t.Errorf("expected ErrUnsupportedLocale, got %v", err)
+	}
+}
+
+func TestPayoutBuilder(t *testing.T) {
+	req, err := intasend.NewPayout(intasend.ProviderMPesaB2C, "KES").
+		Add("254712345678", 1500.5, "Salary").
+		RequireApproval(false).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Transactions) != 1 || req.Transactions[0].Amount != "1500.5" || req.RequiresApproval != intasend.ApprovalNotRequired {
+		t.Errorf("unexpected request: %+v", req)
+	}
+}
+
+func TestPayoutBuilder_ProviderFields(t *testing.T) {
+	_, err := intasend.NewPayout(intasend.ProviderPesaLink, "KES").
+		AddTransaction(intasend.Transaction{Account: "0123456789", Amount: "5000"}).
+		Build()
+	if !hasFieldError(err, "transactions[0].bank_code", intasend.ErrIncompleteRequest) {
+		t.Errorf("expected missing bank code, got %v", err)
+	}
+
+	_, err = intasend.NewPayout(intasend.ProviderMPesaB2B, "KES").
+		AddTransaction(intasend.Transaction{Account: "247247", Amount: "100"}).
+		Build()
+	if !hasFieldError(err, "transactions[0].account_type", intasend.ErrIncompleteRequest) {
+		t.Errorf("expected missing account type, got %v", err)
+	}
+
+	_, err = intasend.NewPayout(intasend.ProviderMPesaB2C, "KES").
+		Add("254712345678", 0, "").
+		Add("0712", 100, "").
+		Build()
+	if !hasFieldError(err, "transactions[0].amount", intasend.ErrInvalidAmount) ||
+		!hasFieldError(err, "transactions[1].account", intasend.ErrInvalidPhoneNumber) {
+		t.Errorf("expected an invalid amount and phone number, got %v", err)
+	}
+}
+
+// hasFieldError reports whether err is a *ValidationError whose field
+// matches target.
+func hasFieldError(err error, field string, target error) bool {
+	verr := intasend.AsValidationError(err)
+	if verr == nil {
+		return false
+	}
+	f := verr.Field(field)
+	return f != nil && errors.Is(f, target)
+}
@@ -2,10 +2,13 @@ package tests
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	intasend "github.com/emilio-kariuki/intasend-go"
 )
@@ -188,3 +191,142 @@ func TestCheckout_CreateWithAllFields(t *testing.T) {
 		t.Errorf("expected CHK-FULL, got %s", resp.ID)
 	}
 }
+
+func TestCheckout_CreateWithMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body createCheckoutRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Method != "M-PESA,CARD-PAYMENT" {
+			t.Errorf("expected method M-PESA,CARD-PAYMENT, got %q", body.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(intasend.CreateCheckoutResponse{ID: "CHK-1"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := client.Checkout().Create(context.Background(), &intasend.CreateCheckoutRequest{
+		Amount:   100,
+		Currency: "KES",
+		Customer: intasend.CheckoutCustomer{Email: "test@example.com"},
+		Host:     "https://example.com",
+		Methods:  []intasend.PaymentMethod{intasend.PaymentMethodMPesa, intasend.PaymentMethodCard},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckout_CreateInvalidMethods(t *testing.T) {
+	client, _ := intasend.New(intasend.WithPublishableKey("ISPubKey_test_abc"))
+
+	tests := []struct {
+		name string
+		req  intasend.CreateCheckoutRequest
+	}{
+		{"unknown", intasend.CreateCheckoutRequest{Currency: "KES", Methods: []intasend.PaymentMethod{"PAYPAL"}}},
+		{"invoice only", intasend.CreateCheckoutRequest{Currency: "KES", Methods: []intasend.PaymentMethod{intasend.PaymentMethodIntaSend}}},
+		{"duplicate", intasend.CreateCheckoutRequest{Currency: "KES", Methods: []intasend.PaymentMethod{intasend.PaymentMethodCard, intasend.PaymentMethodCard}}},
+		{"mpesa non-KES", intasend.CreateCheckoutRequest{Currency: "USD", Methods: []intasend.PaymentMethod{intasend.PaymentMethodMPesa}}},
+		{"method and methods", intasend.CreateCheckoutRequest{Currency: "KES", Method: "M-PESA", Methods: []intasend.PaymentMethod{intasend.PaymentMethodCard}}},
+	}
+	for _, tt := range tests {
+		req := tt.req
+		if _, err := client.Checkout().Create(context.Background(), &req); !errors.Is(err, intasend.ErrInvalidPaymentMethods) {
+			t.Errorf("%s: expected ErrInvalidPaymentMethods, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestCheckout_CreateWithLocale(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body createCheckoutRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Locale != "sw" {
+			t.Errorf("expected locale sw, got %q", body.Locale)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(intasend.CreateCheckoutResponse{ID: "CHK-1"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := client.Checkout().Create(context.Background(), &intasend.CreateCheckoutRequest{
+		Amount:   100,
+		Currency: "KES",
+		Customer: intasend.CheckoutCustomer{Email: "test@example.com"},
+		Host:     "https://example.com",
+		Locale:   intasend.LocaleSwahili,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckout_CreateUnsupportedLocale(t *testing.T) {
+	client, _ := intasend.New(intasend.WithPublishableKey("ISPubKey_test_abc"))
+	_, err := client.Checkout().Create(context.Background(), &intasend.CreateCheckoutRequest{
+		Amount:   100,
+		Currency: "KES",
+		Locale:   "fr",
+	})
+	if !errors.Is(err, intasend.ErrUnsupportedLocale) {
+		t.Errorf("expected ErrUnsupportedLocale, got %v", err)
+	}
+}
+
+// testJWT builds an HS256-shaped token with the given claims. The
+// signature segment is not a real MAC; ParseSignature does not verify it.
+func testJWT(t *testing.T, header string, claims map[string]any) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(header)) + "." + enc(payload) + "." + enc([]byte("sig"))
+}
+
+func TestParseSignature(t *testing.T) {
+	exp := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	sig := testJWT(t, `{"alg":"HS256","typ":"JWT"}`, map[string]any{
+		"checkout_id": "CHK-123",
+		"iat":         exp.Add(-time.Hour).Unix(),
+		"exp":         exp.Unix(),
+	})
+
+	claims, err := intasend.ParseSignature(sig)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.CheckoutID != "CHK-123" || claims.Algorithm != "HS256" || !claims.ExpiresAt.Equal(exp) {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	if err := claims.Validate("CHK-123", exp.Add(-time.Minute)); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := claims.Validate("CHK-999", exp.Add(-time.Minute)); !errors.Is(err, intasend.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for other checkout, got %v", err)
+	}
+	if err := claims.Validate("CHK-123", exp); !errors.Is(err, intasend.ErrSignatureExpired) {
+		t.Errorf("expected ErrSignatureExpired, got %v", err)
+	}
+}
+
+func TestParseSignature_Malformed(t *testing.T) {
+	tests := map[string]string{
+		"not a jwt":   "sig-xyz",
+		"unsigned":    testJWT(t, `{"alg":"none"}`, map[string]any{"checkout_id": "CHK-1"}),
+		"bad claims":  "eyJhbGciOiJIUzI1NiJ9.!!!.c2ln",
+		"exp string":  testJWT(t, `{"alg":"HS256"}`, map[string]any{"exp": "tomorrow"}),
+		"empty parts": "a.b.",
+	}
+	for name, sig := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := intasend.ParseSignature(sig); !errors.Is(err, intasend.ErrInvalidSignature) {
+				t.Errorf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
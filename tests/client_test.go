@@ -1,6 +1,12 @@
 package tests
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	intasend "github.com/emilio-kariuki/intasend-go"
@@ -206,6 +212,57 @@ func TestClient_PublishableKey(t *testing.T) {
 	}
 }
 
+func TestClient_UpdateKeys(t *testing.T) {
+	var auth, pub string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		pub = r.Header.Get("X-IntaSend-Public-API-Key")
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_old"),
+		intasend.WithSecretKey("ISSecretKey_test_old"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.UpdateKeys("ISPubKey_test_new", "ISSecretKey_test_new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer ISSecretKey_test_new" || pub != "ISPubKey_test_new" {
+		t.Errorf("expected the new keys to be sent, got %q and %q", auth, pub)
+	}
+	if client.PublishableKey() != "ISPubKey_test_new" {
+		t.Errorf("expected the new publishable key, got %q", client.PublishableKey())
+	}
+
+	if err := client.UpdateKeys("", ""); !errors.Is(err, intasend.ErrNoKeysProvided) {
+		t.Errorf("expected ErrNoKeysProvided, got %v", err)
+	}
+}
+
+func TestClient_UpdateKeysEnvironmentMismatch(t *testing.T) {
+	client, err := intasend.New(intasend.WithSecretKey("ISSecretKey_test_old"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = client.UpdateKeys("", "ISSecretKey_live_new")
+	if !errors.Is(err, intasend.ErrEnvironmentMismatch) {
+		t.Fatalf("expected ErrEnvironmentMismatch, got %v", err)
+	}
+	if err := client.UpdateKeys("", "ISSecretKey_test_new"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestClient_BaseURL(t *testing.T) {
 	client, err := intasend.New(
 		intasend.WithPublishableKey("ISPubKey_test_abc"),
@@ -217,3 +274,57 @@ func TestClient_BaseURL(t *testing.T) {
 		t.Errorf("expected %s, got %s", intasend.SandboxBaseURL, client.BaseURL())
 	}
 }
+
+type bufferLogger struct{ lines []string }
+
+func (l *bufferLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+type countingCollector struct{ n int }
+
+func (c *countingCollector) ObserveRequest(intasend.RequestMetrics) { c.n++ }
+
+// fakeCollection shows that consumers of NewServices can substitute fakes.
+type fakeCollection struct{ intasend.CollectionAPI }
+
+func TestNewServices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"invoice":{"invoice_id":"INV-1","state":"COMPLETE"}}`))
+	}))
+	defer server.Close()
+
+	logger := &bufferLogger{}
+	metrics := &countingCollector{}
+	svc, err := intasend.NewServices(intasend.Dependencies{
+		PublishableKey: "ISPubKey_test_abc",
+		BaseURL:        server.URL,
+		HTTPClient:     server.Client(),
+		Logger:         logger,
+		Metrics:        metrics,
+		Debug:          true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	status, err := svc.Collection.Status(context.Background(), "INV-1", nil)
+	if err != nil || status.Invoice.InvoiceID != "INV-1" {
+		t.Fatalf("unexpected result %+v, %v", status, err)
+	}
+	if metrics.n != 1 {
+		t.Errorf("expected 1 observed request, got %d", metrics.n)
+	}
+	if len(logger.lines) == 0 || !strings.HasPrefix(logger.lines[0], "[IntaSend] request sent") || !strings.Contains(logger.lines[0], "method=POST") {
+		t.Errorf("expected debug output in injected logger, got %q", logger.lines)
+	}
+	if svc.Client.Collection() != svc.Collection {
+		t.Error("expected Collection to be the client's service")
+	}
+
+	svc.Collection = fakeCollection{}
+
+	if _, err := intasend.NewServices(intasend.Dependencies{}); !errors.Is(err, intasend.ErrNoKeysProvided) {
+		t.Errorf("expected ErrNoKeysProvided, got %v", err)
+	}
+}
@@ -3,9 +3,12 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	intasend "github.com/emilio-kariuki/intasend-go"
 )
@@ -101,6 +104,9 @@ func TestCollection_MPesaSTKPush(t *testing.T) {
 		if body.PublicKey != "ISPubKey_test_abc123" {
 			t.Errorf("expected public key in body, got %q", body.PublicKey)
 		}
+		if body.Narrative != "ACME Order 1234" {
+			t.Errorf("expected narrative ACME Order 1234, got %q", body.Narrative)
+		}
 
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(intasend.STKPushResponse{
@@ -121,6 +127,7 @@ func TestCollection_MPesaSTKPush(t *testing.T) {
 		APIRef:      "test-ref",
 		Name:        "Test User",
 		Email:       "test@example.com",
+		Narrative:   "ACME Order 1234",
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -221,7 +228,7 @@ func TestCollection_ChargeAPIError(t *testing.T) {
 	_, err := client.Collection().Charge(context.Background(), &intasend.ChargeRequest{
 		Email:    "test@example.com",
 		Amount:   100,
-		Currency: "INVALID",
+		Currency: "XYZ",
 		Host:     "https://example.com",
 	})
 	if err == nil {
@@ -235,3 +242,525 @@ func TestCollection_ChargeAPIError(t *testing.T) {
 		t.Errorf("expected 400, got %d", apiErr.HTTPStatusCode)
 	}
 }
+
+func TestCollection_ChargeWithMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body chargeRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Method != "CARD-PAYMENT,BANK-ACH" {
+			t.Errorf("expected method CARD-PAYMENT,BANK-ACH, got %q", body.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(intasend.ChargeResponse{ID: "CHK-1"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := client.Collection().Charge(context.Background(), &intasend.ChargeRequest{
+		Email:    "test@example.com",
+		Host:     "https://example.com",
+		Amount:   100,
+		Currency: "USD",
+		Methods:  []intasend.PaymentMethod{intasend.PaymentMethodCard, intasend.PaymentMethodBankACH},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCollection_StatusBatch(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var body statusRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.InvoiceID == "INV-BAD" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"detail": "Not found"})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(intasend.StatusResponse{
+			Invoice: &intasend.Invoice{InvoiceID: body.InvoiceID, State: intasend.StateComplete},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	results, err := client.Collection().StatusBatch(context.Background(),
+		[]string{"INV-1", "INV-2", "INV-1", "INV-BAD"},
+		&intasend.BatchOptions{Concurrency: 2},
+	)
+
+	var batchErr *intasend.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected BatchError, got %v", err)
+	}
+	if batchErr.Total != 3 || len(batchErr.Errors) != 1 {
+		t.Errorf("expected 1 of 3 failures, got %d of %d", len(batchErr.Errors), batchErr.Total)
+	}
+	if apiErr := intasend.AsAPIError(batchErr.Errors["INV-BAD"]); apiErr == nil || !apiErr.IsNotFound() {
+		t.Errorf("expected not found error for INV-BAD, got %v", batchErr.Errors["INV-BAD"])
+	}
+	if !intasend.IsAPIError(err) {
+		t.Error("expected BatchError to unwrap to the item APIError")
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results["INV-2"].Invoice.InvoiceID != "INV-2" {
+		t.Errorf("expected result for INV-2, got %+v", results["INV-2"])
+	}
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("expected 3 calls (duplicates skipped), got %d", calls)
+	}
+}
+
+func TestCollection_StatusBatchAllSucceed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body statusRequestBody
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(intasend.StatusResponse{
+			Invoice: &intasend.Invoice{InvoiceID: body.InvoiceID, State: intasend.StatePending},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	results, err := client.Collection().StatusBatch(context.Background(), []string{"INV-1", "INV-2", "INV-3"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("expected 3 results, got %d", len(results))
+	}
+}
+
+func TestInvoice_ProviderType(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want intasend.PaymentMethod
+	}{
+		{"M-PESA", intasend.PaymentMethodMPesa},
+		{"mpesa", intasend.PaymentMethodMPesa},
+		{" M PESA ", intasend.PaymentMethodMPesa},
+		{"CARD-PAYMENT", intasend.PaymentMethodCard},
+		{"Card Payment", intasend.PaymentMethodCard},
+		{"card", intasend.PaymentMethodCard},
+		{"bank_ach", intasend.PaymentMethodBankACH},
+		{"BTC", intasend.PaymentMethodBitcoin},
+		{"new provider", intasend.PaymentMethod("NEW-PROVIDER")},
+		{"", intasend.PaymentMethod("")},
+	}
+	for _, tt := range tests {
+		inv := &intasend.Invoice{Provider: tt.raw}
+		if got := inv.ProviderType(); got != tt.want {
+			t.Errorf("ProviderType(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+// stkWaitServer answers an STK push with INV-STK and reports the given
+// states for successive status checks, repeating the last one.
+func stkWaitServer(t *testing.T, states ...string) (*httptest.Server, *int32) {
+	t.Helper()
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/payment/mpesa-stk-push/":
+			json.NewEncoder(w).Encode(intasend.STKPushResponse{
+				Invoice: &intasend.Invoice{InvoiceID: "INV-STK", State: intasend.StatePending},
+			})
+		case "/payment/status/":
+			n := int(atomic.AddInt32(&polls, 1))
+			if n > len(states) {
+				n = len(states)
+			}
+			json.NewEncoder(w).Encode(intasend.StatusResponse{
+				Invoice: &intasend.Invoice{InvoiceID: "INV-STK", State: states[n-1]},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	return server, &polls
+}
+
+func TestCollection_MPesaSTKPushAndWait(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []string
+		want   intasend.STKOutcome
+	}{
+		{"complete", []string{intasend.StatePending, intasend.StateProcessing, intasend.StateComplete}, intasend.STKOutcomeComplete},
+		{"failed", []string{intasend.StatePending, intasend.StateFailed}, intasend.STKOutcomeFailed},
+		{"expired", []string{intasend.StatePending}, intasend.STKOutcomePromptExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := stkWaitServer(t, tt.states...)
+			defer server.Close()
+
+			client := newTestClient(t, server)
+			result, err := client.Collection().MPesaSTKPushAndWait(context.Background(), &intasend.STKPushRequest{
+				PhoneNumber: "254712345678",
+				Amount:      100,
+			}, &intasend.PollOptions{Interval: 5 * time.Millisecond, Timeout: 50 * time.Millisecond})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Outcome != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, result.Outcome)
+			}
+			if result.PromptExpired() != (tt.want == intasend.STKOutcomePromptExpired) {
+				t.Errorf("PromptExpired() = %v for outcome %s", result.PromptExpired(), result.Outcome)
+			}
+			if result.Push == nil || result.Status == nil {
+				t.Error("expected push response and last status")
+			}
+		})
+	}
+}
+
+func TestCollection_MPesaSTKPushAndWaitCanceled(t *testing.T) {
+	server, _ := stkWaitServer(t, intasend.StatePending)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	client := newTestClient(t, server)
+	result, err := client.Collection().MPesaSTKPushAndWait(ctx, &intasend.STKPushRequest{
+		PhoneNumber: "254712345678",
+		Amount:      100,
+	}, &intasend.PollOptions{Interval: 5 * time.Millisecond, Timeout: time.Minute})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if result == nil || result.Outcome != "" {
+		t.Errorf("expected partial result without outcome, got %+v", result)
+	}
+}
+
+func TestCollection_ResendSTKPush(t *testing.T) {
+	var pushed intasend.STKPushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/payment/status/":
+			json.NewEncoder(w).Encode(intasend.StatusResponse{
+				Invoice: &intasend.Invoice{
+					InvoiceID: "INV-OLD",
+					State:     intasend.StateFailed,
+					Provider:  "M-PESA",
+					Account:   "254712345678",
+					Value:     250,
+					APIRef:    "order-9",
+					CreatedAt: time.Now(),
+				},
+				Customer: &intasend.CustomerInfo{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"},
+			})
+		case "/payment/mpesa-stk-push/":
+			json.NewDecoder(r.Body).Decode(&pushed)
+			json.NewEncoder(w).Encode(intasend.STKPushResponse{
+				Invoice: &intasend.Invoice{InvoiceID: "INV-NEW", State: intasend.StatePending},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	resp, err := client.Collection().ResendSTKPush(context.Background(), "INV-OLD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Previous.InvoiceID != "INV-OLD" || resp.Invoice.InvoiceID != "INV-NEW" {
+		t.Errorf("expected INV-OLD superseded by INV-NEW, got %s -> %s", resp.Previous.InvoiceID, resp.Invoice.InvoiceID)
+	}
+	if pushed.PhoneNumber != "254712345678" || pushed.Amount != 250 || pushed.APIRef != "order-9" {
+		t.Errorf("expected original phone, amount and ref, got %+v", pushed)
+	}
+	if pushed.Name != "Jane Doe" || pushed.Email != "jane@example.com" {
+		t.Errorf("expected customer details, got %+v", pushed)
+	}
+}
+
+func TestCollection_ResendSTKPushNotResendable(t *testing.T) {
+	tests := []struct {
+		name    string
+		invoice intasend.Invoice
+	}{
+		{"complete", intasend.Invoice{State: intasend.StateComplete, Provider: "M-PESA"}},
+		{"processing", intasend.Invoice{State: intasend.StateProcessing, Provider: "M-PESA"}},
+		{"prompt open", intasend.Invoice{State: intasend.StatePending, Provider: "M-PESA", CreatedAt: time.Now()}},
+		{"card", intasend.Invoice{State: intasend.StateFailed, Provider: "CARD-PAYMENT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/payment/status/" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				inv := tt.invoice
+				inv.InvoiceID = "INV-OLD"
+				inv.Account = "254712345678"
+				json.NewEncoder(w).Encode(intasend.StatusResponse{Invoice: &inv})
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server)
+			_, err := client.Collection().ResendSTKPush(context.Background(), "INV-OLD")
+			if !errors.Is(err, intasend.ErrNotResendable) {
+				t.Errorf("expected ErrNotResendable, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseFailureReason(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want intasend.FailureReason
+	}{
+		{"Request cancelled by user", intasend.FailureReasonUserCancelled},
+		{"Request Cancelled by user.", intasend.FailureReasonUserCancelled},
+		{"DS timeout", intasend.FailureReasonTimeout},
+		{"DS timeout user cannot be reached", intasend.FailureReasonTimeout},
+		{"Insufficient balance", intasend.FailureReasonInsufficientFunds},
+		{"The balance is insufficient for the transaction.", intasend.FailureReasonInsufficientFunds},
+		{"The initiator information is invalid.", intasend.FailureReasonInvalidPIN},
+		{"System busy", intasend.FailureReasonUnknown},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := intasend.ParseFailureReason(tt.raw); got != tt.want {
+			t.Errorf("ParseFailureReason(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+
+	inv := &intasend.Invoice{FailedReason: "Request cancelled by user"}
+	if r := inv.FailureReason(); !r.IsUserCancelled() || r.IsTimeout() || r.IsInsufficientFunds() {
+		t.Errorf("unexpected helpers for %q", r)
+	}
+}
+
+func TestCollection_MPesaSTKPushAndWaitTimeoutFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inv := &intasend.Invoice{InvoiceID: "INV-STK", State: intasend.StatePending}
+		if r.URL.Path == "/payment/status/" {
+			inv.State, inv.FailedReason = intasend.StateFailed, "DS timeout"
+		}
+		json.NewEncoder(w).Encode(intasend.StatusResponse{Invoice: inv})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	result, err := client.Collection().MPesaSTKPushAndWait(context.Background(), &intasend.STKPushRequest{
+		PhoneNumber: "254712345678",
+		Amount:      100,
+	}, &intasend.PollOptions{Interval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.PromptExpired() {
+		t.Errorf("expected prompt expired for DS timeout, got %s", result.Outcome)
+	}
+}
+
+func TestCollection_MPesaSTKPushAndWaitAdaptive(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      string
+		retryAfter string
+		opts       intasend.PollOptions
+		check      func(polls int32) bool
+	}{
+		{
+			name:  "pending polls slowly",
+			state: intasend.StatePending,
+			opts:  intasend.PollOptions{MinInterval: time.Millisecond, MaxInterval: 40 * time.Millisecond, Timeout: 100 * time.Millisecond},
+			check: func(polls int32) bool { return polls >= 2 && polls <= 4 },
+		},
+		{
+			name:  "processing polls quickly",
+			state: intasend.StateProcessing,
+			opts:  intasend.PollOptions{MinInterval: 2 * time.Millisecond, MaxInterval: time.Second, Timeout: 100 * time.Millisecond},
+			check: func(polls int32) bool { return polls >= 8 },
+		},
+		{
+			name:       "server hint wins",
+			state:      intasend.StateProcessing,
+			retryAfter: "1",
+			opts:       intasend.PollOptions{MinInterval: time.Millisecond, MaxInterval: time.Minute, Timeout: 100 * time.Millisecond},
+			check:      func(polls int32) bool { return polls <= 2 },
+		},
+		{
+			name:       "server hint beyond max interval",
+			state:      intasend.StatePending,
+			retryAfter: "1",
+			opts:       intasend.PollOptions{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, Timeout: 100 * time.Millisecond},
+			check:      func(polls int32) bool { return polls <= 2 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/payment/mpesa-stk-push/":
+					json.NewEncoder(w).Encode(intasend.STKPushResponse{
+						Invoice: &intasend.Invoice{InvoiceID: "INV-STK", State: tt.state},
+					})
+				case "/payment/status/":
+					atomic.AddInt32(&polls, 1)
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					json.NewEncoder(w).Encode(intasend.StatusResponse{
+						Invoice: &intasend.Invoice{InvoiceID: "INV-STK", State: tt.state},
+					})
+				}
+			}))
+			defer server.Close()
+
+			opts := tt.opts
+			client := newTestClient(t, server)
+			result, err := client.Collection().MPesaSTKPushAndWait(context.Background(), &intasend.STKPushRequest{
+				PhoneNumber: "254712345678",
+				Amount:      100,
+			}, &opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.PromptExpired() {
+				t.Errorf("expected prompt to expire, got %s", result.Outcome)
+			}
+			if n := atomic.LoadInt32(&polls); !tt.check(n) {
+				t.Errorf("unexpected number of status checks: %d", n)
+			}
+		})
+	}
+}
+
+func TestStatusResponse_ReceiptSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"invoice": {
+				"invoice_id": "INV-1", "state": "COMPLETE", "provider": "M-PESA",
+				"value": "1500.00", "charges": "45.00", "net_amount": "1455.00", "currency": "KES",
+				"account": "254712345678", "api_ref": "order-1234", "mpesa_reference": "QK71ABC2DE",
+				"created_at": "2024-03-01T10:00:00Z", "updated_at": "2024-03-01T10:01:00Z"
+			},
+			"customer": {"first_name": "Jane", "last_name": "Doe", "email": "jane@example.com", "phone_number": ""}
+		}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	status, err := client.Collection().Status(context.Background(), "INV-1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Invoice.Value != 1500 || status.Invoice.Charges != 45 || status.Invoice.NetAmount != 1455 {
+		t.Fatalf("expected string amounts to decode, got %+v", status.Invoice)
+	}
+
+	summary, err := status.ReceiptSummary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Method != "M-Pesa" || summary.MPesaCode != "QK71ABC2DE" || summary.Reference != "order-1234" {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if summary.CustomerName != "Jane Doe" || summary.CustomerPhone != "254712345678" {
+		t.Errorf("unexpected customer details: %+v", summary)
+	}
+	if !summary.PaidAt.Equal(time.Date(2024, 3, 1, 10, 1, 0, 0, time.UTC)) {
+		t.Errorf("unexpected PaidAt %v", summary.PaidAt)
+	}
+	if got := summary.AmountText(); got != "KES 1,500.00" {
+		t.Errorf("expected KES 1,500.00, got %q", got)
+	}
+	if got := summary.FeesText(); got != "KES 45.00" {
+		t.Errorf("expected KES 45.00, got %q", got)
+	}
+	if got := summary.NetText(); got != "KES 1,455.00" {
+		t.Errorf("expected KES 1,455.00, got %q", got)
+	}
+}
+
+func TestNewReceiptSummary(t *testing.T) {
+	_, err := intasend.NewReceiptSummary(&intasend.Invoice{State: intasend.StatePending}, nil)
+	if !errors.Is(err, intasend.ErrNotPaid) {
+		t.Fatalf("expected ErrNotPaid, got %v", err)
+	}
+
+	summary, err := intasend.NewReceiptSummary(&intasend.Invoice{
+		State:     intasend.StateComplete,
+		Provider:  "CARD-PAYMENT",
+		Value:     1234567.5,
+		Charges:   100,
+		CreatedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Method != "Card" || summary.Net != 1234467.5 || summary.CustomerPhone != "" {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if got := summary.AmountText(); got != "1,234,567.50" {
+		t.Errorf("expected 1,234,567.50, got %q", got)
+	}
+	if summary.PaidAt.IsZero() {
+		t.Error("expected PaidAt to fall back to CreatedAt")
+	}
+}
+
+func TestCollection_WaitForComplete(t *testing.T) {
+	states := []string{"PENDING", "PROCESSING", "COMPLETE"}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := states[len(states)-1]
+		if calls < len(states) {
+			state = states[calls]
+		}
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"invoice": map[string]interface{}{"invoice_id": "INV-1", "state": state},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	status, err := client.Collection().WaitForComplete(context.Background(), "INV-1", &intasend.PollOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Invoice.State != intasend.StateComplete || calls != 3 {
+		t.Errorf("expected COMPLETE after 3 checks, got %s after %d", status.Invoice.State, calls)
+	}
+}
+
+func TestCollection_WaitForCompleteTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"invoice": map[string]interface{}{"invoice_id": "INV-1", "state": "PENDING"},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	status, err := client.Collection().WaitForComplete(context.Background(), "INV-1", &intasend.PollOptions{
+		Interval: 5 * time.Millisecond,
+		Timeout:  20 * time.Millisecond,
+	})
+	if !errors.Is(err, intasend.ErrWaitTimeout) {
+		t.Fatalf("expected ErrWaitTimeout, got %v", err)
+	}
+	if status == nil || status.Invoice.State != intasend.StatePending {
+		t.Errorf("expected the last status to be returned, got %+v", status)
+	}
+}
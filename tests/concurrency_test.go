@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// TestClient_ConcurrentUseAcrossServices hammers a single client from many
+// goroutines across every service. Run with -race to detect shared-state races.
+func TestClient_ConcurrentUseAcrossServices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"results":[],"invoice":{"invoice_id":"INV-1","state":"PENDING"}}`))
+	}))
+	defer server.Close()
+
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 64
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(&http.Client{Transport: transport}),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	// Shared request values must not be mutated by services.
+	walletReq := &intasend.CreateWalletRequest{Currency: "KES", Label: "Shared"}
+	stkReq := &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10}
+
+	calls := []func() error{
+		func() error { _, err := client.Collection().MPesaSTKPush(ctx, stkReq); return err },
+		func() error { _, err := client.Collection().Status(ctx, "INV-1", nil); return err },
+		func() error {
+			_, err := client.Collection().Charge(ctx, &intasend.ChargeRequest{Email: "a@b.c", Host: "h", Amount: 1, Currency: "KES"})
+			return err
+		},
+		func() error {
+			_, err := client.Payout().MPesa(ctx, &intasend.MPesaRequest{Currency: "KES", Transactions: []intasend.Transaction{{Account: "254712345678", Amount: "10"}}})
+			return err
+		},
+		func() error { _, err := client.Payout().Status(ctx, "TRK-1"); return err },
+		func() error { _, err := client.Wallet().List(ctx); return err },
+		func() error { _, err := client.Wallet().Create(ctx, walletReq); return err },
+		func() error { _, err := client.Wallet().Transactions(ctx, "W-1"); return err },
+		func() error { _, err := client.Refund().List(ctx); return err },
+		func() error {
+			_, err := client.Checkout().Create(ctx, &intasend.CreateCheckoutRequest{Amount: 1, Currency: "KES", Host: "h"})
+			return err
+		},
+		func() error { _, err := client.PaymentLink().List(ctx); return err },
+	}
+
+	const goroutines = 200
+	errs := make(chan error, goroutines*len(calls))
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := range calls {
+				if err := calls[(g+i)%len(calls)](); err != nil {
+					errs <- err
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if walletReq.WalletType != "" {
+		t.Errorf("expected shared request to be left unmodified, got wallet type %q", walletReq.WalletType)
+	}
+}
+
+func TestClient_MaxConcurrentRequests(t *testing.T) {
+	var inflight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inflight, -1)
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithMaxConcurrentRequests(2),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Wallet().List(context.Background()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(&peak); got != 2 {
+		t.Errorf("expected at most 2 requests in flight, peak was %d", got)
+	}
+}
+
+func TestClient_MaxConcurrentRequestsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithMaxConcurrentRequests(1),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	go client.Wallet().List(context.Background())
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Wallet().List(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a waiting request to give up with its context, got %v", err)
+	}
+}
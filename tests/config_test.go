@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestConfig_Load(t *testing.T) {
+	cfg, err := intasend.LoadConfig(strings.NewReader(`{
+		"publishable_key": "ISPubKey_test_abc",
+		"secret_key": "${TEST_INTASEND_SECRET}",
+		"environment": "production",
+		"timeout": "20s",
+		"retry": {"max_retries": 5, "wait": 0.5},
+		"defaults": {"currency": "kes"},
+		"webhook": {"challenge": "hook-secret"}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if time.Duration(cfg.Timeout) != 20*time.Second {
+		t.Errorf("expected 20s timeout, got %v", time.Duration(cfg.Timeout))
+	}
+	if cfg.Retry == nil || cfg.Retry.MaxRetries != 5 || time.Duration(cfg.Retry.Wait) != 500*time.Millisecond {
+		t.Errorf("unexpected retry config: %+v", cfg.Retry)
+	}
+
+	t.Setenv("TEST_INTASEND_SECRET", "ISSecretKey_test_env")
+	opts, err := cfg.Options()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client, err := intasend.New(opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !client.IsProduction() {
+		t.Errorf("expected explicit environment to override key detection, got %s", client.BaseURL())
+	}
+	if client.WebhookChallenge() != "hook-secret" {
+		t.Errorf("expected webhook challenge, got %q", client.WebhookChallenge())
+	}
+}
+
+func TestConfig_UnknownField(t *testing.T) {
+	_, err := intasend.LoadConfig(strings.NewReader(`{"secret_kye": "typo"}`))
+	if err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestConfig_InvalidEnvironment(t *testing.T) {
+	_, err := intasend.NewFromConfig(strings.NewReader(`{"secret_key": "ISSecretKey_test_x", "environment": "staging"}`))
+	if err == nil || !strings.Contains(err.Error(), "staging") {
+		t.Errorf("expected unknown environment error, got %v", err)
+	}
+}
+
+func TestConfig_NewFromConfigFileAppliesDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["currency"] != "KES" {
+			t.Errorf("expected default currency KES, got %v", body["currency"])
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(intasend.PaymentLink{LinkID: "L-1"})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "intasend.json")
+	data := `{"secret_key": "ISSecretKey_test_x", "base_url": "` + server.URL + `", "defaults": {"currency": "KES"}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := intasend.NewFromConfigFile(path, intasend.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := &intasend.CreatePaymentLinkRequest{Title: "Plan", Amount: 100}
+	if _, err := client.PaymentLink().Create(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Currency != "" {
+		t.Errorf("request should not be mutated, got currency %q", req.Currency)
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestContextWithFields_Merge(t *testing.T) {
+	ctx := intasend.ContextWithFields(context.Background(), map[string]interface{}{"order_id": "o-1", "attempt": 1})
+	ctx = intasend.ContextWithFields(ctx, map[string]interface{}{"attempt": 2})
+
+	fields := intasend.FieldsFromContext(ctx)
+	if fields["order_id"] != "o-1" {
+		t.Errorf("expected order_id o-1, got %v", fields["order_id"])
+	}
+	if fields["attempt"] != 2 {
+		t.Errorf("expected attempt 2 to override, got %v", fields["attempt"])
+	}
+}
+
+func TestFieldsFromContext_Empty(t *testing.T) {
+	if fields := intasend.FieldsFromContext(context.Background()); fields != nil {
+		t.Errorf("expected nil fields, got %v", fields)
+	}
+}
+
+func TestContextWithFields_DebugLog(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithDebug(true),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := intasend.ContextWithFields(context.Background(), map[string]interface{}{"order_id": "order-123"})
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "order_id=order-123") {
+		t.Errorf("expected debug log to include fields, got %q", buf.String())
+	}
+}
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/webhook"
+)
+
+// deadLetters collects dead letters from component goroutines.
+type deadLetters struct {
+	mu      sync.Mutex
+	letters []intasend.DeadLetter
+}
+
+func (d *deadLetters) HandleDeadLetter(l intasend.DeadLetter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.letters = append(d.letters, l)
+}
+
+func (d *deadLetters) all() []intasend.DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]intasend.DeadLetter(nil), d.letters...)
+}
+
+func newDeadLetterClient(t *testing.T, server *httptest.Server, h intasend.DeadLetterHandler) *intasend.Client {
+	t.Helper()
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithDeadLetterHandler(h),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestDeadLetter_CollectQueue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var dead deadLetters
+	client := newDeadLetterClient(t, server, &dead)
+	queue := client.Collection().NewQueue(context.Background(), &intasend.CollectQueueOptions{Backoff: time.Millisecond})
+	req := &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 100, APIRef: "sub-42"}
+	queue.Enqueue(req)
+	queue.Close()
+
+	letters := dead.all()
+	if len(letters) != 1 || letters[0].Source != intasend.DeadLetterCollectQueue || letters[0].Item != req || letters[0].Attempts != 1 || letters[0].Err == nil {
+		t.Errorf("unexpected dead letters: %+v", letters)
+	}
+}
+
+func TestDeadLetter_TransactionStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	var dead deadLetters
+	client := newDeadLetterClient(t, server, &dead)
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	stream := client.Wallet().StreamTransactions(context.Background(), "W-001", since, nil)
+	for range stream.C {
+	}
+
+	letters := dead.all()
+	if len(letters) != 1 || letters[0].Source != intasend.DeadLetterTransactionStream {
+		t.Fatalf("unexpected dead letters: %+v", letters)
+	}
+	pos, ok := letters[0].Item.(intasend.StreamPosition)
+	if !ok || pos.WalletID != "W-001" || !pos.Cursor.Equal(since) {
+		t.Errorf("unexpected stream position: %+v", letters[0].Item)
+	}
+}
+
+func TestDeadLetter_WebhookRelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var dead deadLetters
+	relay := webhook.NewRelay(webhook.RelayOptions{Secret: relaySecret, DeadLetterHandler: &dead})
+	if err := relay.Deliver(context.Background(), server.URL, []byte(`{}`)); err == nil {
+		t.Fatal("expected error")
+	}
+	letters := dead.all()
+	if len(letters) != 1 || letters[0].Source != intasend.DeadLetterWebhookRelay {
+		t.Fatalf("unexpected dead letters: %+v", letters)
+	}
+	if d, ok := letters[0].Item.(webhook.Delivery); !ok || d.URL != server.URL {
+		t.Errorf("unexpected item: %+v", letters[0].Item)
+	}
+}
@@ -0,0 +1,495 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+type harDoc struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method  string `json:"method"`
+				URL     string `json:"url"`
+				Headers []struct {
+					Name  string `json:"name"`
+					Value string `json:"value"`
+				} `json:"headers"`
+				PostData *struct {
+					Text string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Status  int `json:"status"`
+				Content struct {
+					Text string `json:"text"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+func TestDebug_HAR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"wallet_id":"W1"}]}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "intasend.har")
+	if err := client.Debug().StartHAR(path); err != nil {
+		t.Fatalf("StartHAR: %v", err)
+	}
+	if err := client.Debug().StartHAR(path); !errors.Is(err, intasend.ErrHARInProgress) {
+		t.Fatalf("expected ErrHARInProgress, got %v", err)
+	}
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = client.Collection().Charge(ctx, &intasend.ChargeRequest{
+		Email:    "jane@example.com",
+		Host:     "https://example.com",
+		Amount:   100,
+		Currency: "KES",
+	})
+	if err := client.Debug().StopHAR(); err != nil {
+		t.Fatalf("StopHAR: %v", err)
+	}
+	if err := client.Debug().StopHAR(); err != nil {
+		t.Fatalf("second StopHAR: %v", err)
+	}
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc harDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid HAR: %v", err)
+	}
+	if len(doc.Log.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(doc.Log.Entries))
+	}
+	list := doc.Log.Entries[0]
+	if list.Request.Method != http.MethodGet || !strings.HasSuffix(list.Request.URL, "/wallets/") {
+		t.Errorf("unexpected request %s %s", list.Request.Method, list.Request.URL)
+	}
+	if list.Response.Status != http.StatusOK || !strings.Contains(list.Response.Content.Text, "W1") {
+		t.Errorf("unexpected response %d %s", list.Response.Status, list.Response.Content.Text)
+	}
+	if strings.Contains(string(data), "ISSecretKey_test_secret") || strings.Contains(string(data), "ISPubKey_test_abc123") {
+		t.Error("expected API keys to be redacted")
+	}
+	charge := doc.Log.Entries[1]
+	if charge.Request.PostData == nil || !strings.Contains(charge.Request.PostData.Text, `"email":"j***@example.com"`) {
+		t.Fatalf("expected charge body to be recorded with the email masked, got %+v", charge.Request.PostData)
+	}
+	if !strings.Contains(charge.Request.PostData.Text, `"public_key":"ISPubKey_test_[REDACTED]"`) {
+		t.Errorf("expected public_key to be redacted, got %s", charge.Request.PostData.Text)
+	}
+}
+
+func TestClient_SetDebug(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.DebugEnabled() || buf.Len() != 0 {
+		t.Fatalf("expected no debug output, got %q", buf.String())
+	}
+
+	client.SetDebug(true)
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !client.DebugEnabled() || !strings.Contains(buf.String(), "[IntaSend] request sent method=GET") {
+		t.Fatalf("expected debug output, got %q", buf.String())
+	}
+
+	client.SetDebug(false)
+	buf.Reset()
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected no debug output after disabling, got %q", buf.String())
+	}
+
+	// Toggling while requests are in flight must be safe.
+	log.SetOutput(io.Discard)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = client.Wallet().List(ctx)
+		}()
+		go func(on bool) {
+			defer wg.Done()
+			client.SetDebug(on)
+		}(i%2 == 0)
+	}
+	wg.Wait()
+}
+
+// logRecord is a record received by a StructuredLogger.
+type logRecord struct {
+	level  intasend.LogLevel
+	msg    string
+	fields map[string]interface{}
+}
+
+func TestStructuredLogger_Redaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"invoice":{"invoice_id":"INV-1","account":"+254712345678"},"echo":"ISSecretKey_live_abc123"}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var records []logRecord
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithDebug(true),
+		intasend.WithStructuredLogger(intasend.StructuredLoggerFunc(func(ctx context.Context, level intasend.LogLevel, msg string, fields map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			records = append(records, logRecord{level, msg, fields})
+		})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := intasend.ContextWithFields(context.Background(), map[string]interface{}{"order_id": "order-1"})
+	_, err = client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10, Email: "a@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(records) != 2 || records[0].msg != "request sent" || records[1].msg != "response received" {
+		t.Fatalf("expected request and response records, got %+v", records)
+	}
+	for _, r := range records {
+		if r.level != intasend.LogLevelDebug || r.fields["order_id"] != "order-1" || r.fields["method"] != http.MethodPost {
+			t.Errorf("unexpected record %+v", r)
+		}
+	}
+	reqBody, _ := records[0].fields["body"].(string)
+	if strings.Contains(reqBody, "254712345678") || !strings.Contains(reqBody, `"254******678"`) {
+		t.Errorf("expected the phone number to be masked, got %s", reqBody)
+	}
+	respBody, _ := records[1].fields["body"].(string)
+	if strings.Contains(respBody, "abc123") || strings.Contains(respBody, "712345") ||
+		!strings.Contains(respBody, "ISSecretKey_live_[REDACTED]") || records[1].fields["status"] != http.StatusOK {
+		t.Errorf("expected the secret key and phone number to be masked, got %+v", records[1].fields)
+	}
+}
+
+func TestSupportBundle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/wallets/W1/" {
+			w.Header().Set("X-Request-ID", "req-404")
+			w.Header().Set("CF-Ray", "8a1b2c3d4e5f-NBO")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"no wallet for 254712345678"}`))
+			return
+		}
+		w.Header().Set("X-Request-ID", "req-ok")
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Wallet().Get(ctx, "W1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	data, err := client.SupportBundle(ctx)
+	if err != nil {
+		t.Fatalf("SupportBundle: %v", err)
+	}
+	if strings.Contains(string(data), "ISSecretKey") || strings.Contains(string(data), "712345") {
+		t.Errorf("expected keys and phone numbers to be left out, got %s", data)
+	}
+	var bundle struct {
+		SDK struct {
+			Version string `json:"version"`
+		} `json:"sdk"`
+		Environment string `json:"environment"`
+		Config      struct {
+			SecretKeySet bool `json:"secret_key_set"`
+		} `json:"config"`
+		RecentRequests []struct {
+			Endpoint   string `json:"endpoint"`
+			StatusCode int    `json:"status_code"`
+			RequestID  string `json:"request_id"`
+			RayID      string `json:"ray_id"`
+			Error      string `json:"error"`
+		} `json:"recent_requests"`
+		Probes []struct {
+			BaseURL   string `json:"base_url"`
+			Reachable bool   `json:"reachable"`
+		} `json:"probes"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("invalid bundle: %v", err)
+	}
+	if bundle.SDK.Version != intasend.Version || bundle.Environment != "custom" || !bundle.Config.SecretKeySet {
+		t.Errorf("unexpected bundle header: %s", data)
+	}
+	if len(bundle.RecentRequests) != 2 {
+		t.Fatalf("expected 2 recent requests, got %d", len(bundle.RecentRequests))
+	}
+	ok, failed := bundle.RecentRequests[0], bundle.RecentRequests[1]
+	if ok.Endpoint != "/wallets/" || ok.StatusCode != 200 || ok.RequestID != "req-ok" || ok.Error != "" {
+		t.Errorf("unexpected first request: %+v", ok)
+	}
+	if failed.Endpoint != "/wallets/:id/" || failed.StatusCode != 404 || failed.RequestID != "req-404" ||
+		failed.RayID != "8a1b2c3d4e5f-NBO" || !strings.Contains(failed.Error, "254******678") {
+		t.Errorf("unexpected second request: %+v", failed)
+	}
+	if len(bundle.Probes) != 1 || bundle.Probes[0].BaseURL != server.URL || !bundle.Probes[0].Reachable {
+		t.Errorf("unexpected probes: %+v", bundle.Probes)
+	}
+
+	for i := 0; i < 60; i++ {
+		if _, err := client.Wallet().List(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	data, _ = client.SupportBundle(ctx)
+	bundle.RecentRequests = nil
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("invalid bundle: %v", err)
+	}
+	if len(bundle.RecentRequests) != 50 || bundle.RecentRequests[0].Endpoint != "/wallets/" {
+		t.Errorf("expected the last 50 requests, got %d", len(bundle.RecentRequests))
+	}
+}
+
+func TestDebug_RedactsQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("email") != "jane@example.com" {
+			t.Errorf("expected the email filter to be sent, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var urls []string
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithDebug(true),
+		intasend.WithStructuredLogger(intasend.StructuredLoggerFunc(func(ctx context.Context, level intasend.LogLevel, msg string, fields map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			if u, ok := fields["url"].(string); ok {
+				urls = append(urls, u)
+			}
+		})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "intasend.har")
+	if err := client.Debug().StartHAR(path); err != nil {
+		t.Fatalf("StartHAR: %v", err)
+	}
+	var out map[string]interface{}
+	route := "/some/new/route/?email=jane%40example.com&phone_number=254712345678&state=COMPLETE"
+	if err := client.Do(context.Background(), http.MethodGet, route, nil, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Debug().StopHAR(); err != nil {
+		t.Fatalf("StopHAR: %v", err)
+	}
+
+	if len(urls) != 2 {
+		t.Fatalf("expected request and response URLs, got %v", urls)
+	}
+	for _, u := range urls {
+		if strings.Contains(u, "jane") || strings.Contains(u, "712345") || !strings.Contains(u, "email=[REDACTED]") || !strings.Contains(u, "state=COMPLETE") {
+			t.Errorf("expected personal data to be redacted from %s", u)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "jane") || strings.Contains(string(data), "712345") {
+		t.Errorf("expected personal data to be redacted from the HAR, got %s", data)
+	}
+}
+
+func TestDebug_HARTokenizer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"invoice":{"invoice_id":"INV-1","account":"254712345678"}}`))
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithTokenizer(intasend.NewHMACTokenizer([]byte("key"))),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "intasend.har")
+	if err := client.Debug().StartHAR(path); err != nil {
+		t.Fatalf("StartHAR: %v", err)
+	}
+	_, err = client.Collection().MPesaSTKPush(context.Background(), &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10, Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Debug().StopHAR(); err != nil {
+		t.Fatalf("StopHAR: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc harDoc
+	if err := json.Unmarshal(data, &doc); err != nil || len(doc.Log.Entries) != 1 {
+		t.Fatalf("expected one entry, got %v: %s", err, data)
+	}
+	entry := doc.Log.Entries[0]
+	if strings.Contains(string(data), "jane") || strings.Contains(string(data), "712345") {
+		t.Errorf("expected personal data to be left out, got %s", data)
+	}
+	if !strings.Contains(entry.Request.PostData.Text, `"phone_number":"tok_phone_number_`) ||
+		!strings.Contains(entry.Response.Content.Text, `"account":"tok_account_`) {
+		t.Errorf("expected personal data to be tokenized, got %s and %s", entry.Request.PostData.Text, entry.Response.Content.Text)
+	}
+}
+
+func TestDebug_HARCustomHeadersAndLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[{"wallet_id":"` + strings.Repeat("W", 1000) + `"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithHeaders(http.Header{"X-Gateway-Token": {"gateway-secret"}}),
+		intasend.WithMaxResponseBytes(100),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "intasend.har")
+	if err := client.Debug().StartHAR(path); err != nil {
+		t.Fatalf("StartHAR: %v", err)
+	}
+	_, err = client.Wallet().List(context.Background(), intasend.WithHeader("X-Tenant-Key", "tenant-secret"))
+	if !errors.Is(err, intasend.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if err := client.Debug().StopHAR(); err != nil {
+		t.Fatalf("StopHAR: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gateway-secret") || strings.Contains(string(data), "tenant-secret") {
+		t.Errorf("expected custom header values to be redacted, got %s", data)
+	}
+	var doc harDoc
+	if err := json.Unmarshal(data, &doc); err != nil || len(doc.Log.Entries) != 1 {
+		t.Fatalf("expected one entry, got %v: %s", err, data)
+	}
+	if n := len(doc.Log.Entries[0].Response.Content.Text); n > 101 {
+		t.Errorf("expected at most 101 body bytes to be read, got %d", n)
+	}
+}
+
+func TestSupportBundle_RedactsQuery(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL
+	dead.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(deadURL),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+	err = client.Do(ctx, http.MethodGet, "/some/new/route/?customer_id=CUS-42&email=john%40example.com", nil, nil)
+	if !intasend.IsNetworkError(err) || !strings.Contains(err.Error(), "john%40example.com") {
+		t.Fatalf("expected a network error quoting the URL, got %v", err)
+	}
+
+	data, err := client.SupportBundle(ctx)
+	if err != nil {
+		t.Fatalf("SupportBundle: %v", err)
+	}
+	if strings.Contains(string(data), "john") || strings.Contains(string(data), "CUS-42") {
+		t.Errorf("expected the query to be redacted from errors, got %s", data)
+	}
+}
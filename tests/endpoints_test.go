@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestEndpoints(t *testing.T) {
+	endpoints := intasend.Endpoints()
+	if len(endpoints) == 0 {
+		t.Fatal("expected endpoints")
+	}
+
+	var stk *intasend.Endpoint
+	for i, e := range endpoints {
+		if e.Method == http.MethodPost && e.Path == "/payment/mpesa-stk-push/" {
+			stk = &endpoints[i]
+		}
+		if len(e.SDKMethods) == 0 {
+			t.Errorf("%s %s has no SDK methods", e.Method, e.Path)
+		}
+	}
+	if stk == nil {
+		t.Fatal("expected STK push endpoint")
+	}
+	found := false
+	for _, m := range stk.SDKMethods {
+		found = found || m == "Collection().MPesaSTKPush"
+	}
+	if !found {
+		t.Errorf("expected Collection().MPesaSTKPush, got %v", stk.SDKMethods)
+	}
+
+	stk.SDKMethods[0] = "changed"
+	for _, e := range intasend.Endpoints() {
+		for _, m := range e.SDKMethods {
+			if m == "changed" {
+				t.Fatal("Endpoints should return a copy")
+			}
+		}
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/new/route/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer ISSecretKey_test_secret" {
+			t.Errorf("expected auth header, got %q", got)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]string{"echo": body["name"]})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	var out map[string]string
+	err := client.Do(context.Background(), "post", "/new/route/", map[string]string{"name": "x"}, &out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out["echo"] != "x" {
+		t.Errorf("expected echoed body, got %v", out)
+	}
+
+	if err := client.Do(context.Background(), http.MethodGet, "new/route/", nil, nil); err == nil {
+		t.Error("expected error for relative path")
+	}
+}
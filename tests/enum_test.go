@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestParseEnums(t *testing.T) {
+	if p, err := intasend.ParseProvider(" mpesa-b2c "); err != nil || p != intasend.ProviderMPesaB2C {
+		t.Errorf("ParseProvider: got %q, %v", p, err)
+	}
+	if _, err := intasend.ParseProvider("MPESA"); !errors.Is(err, intasend.ErrInvalidEnumValue) {
+		t.Errorf("ParseProvider: expected ErrInvalidEnumValue, got %v", err)
+	}
+	if a, err := intasend.ParseApprovalStatus("yes"); err != nil || a != intasend.ApprovalRequired {
+		t.Errorf("ParseApprovalStatus: got %q, %v", a, err)
+	}
+	if a, err := intasend.ParseAccountType("tillnumber"); err != nil || a != intasend.AccountTypeTillNumber {
+		t.Errorf("ParseAccountType: got %q, %v", a, err)
+	}
+	if tr, err := intasend.ParseTariff("Customer-Pays"); err != nil || tr != intasend.TariffCustomerPays {
+		t.Errorf("ParseTariff: got %q, %v", tr, err)
+	}
+	if r, err := intasend.ParseRefundReason("customer_request"); err != nil || r != intasend.RefundReasonCustomerRequest {
+		t.Errorf("ParseRefundReason: got %q, %v", r, err)
+	}
+
+	if !intasend.ProviderAirtime.IsValid() || intasend.Provider("airtime").IsValid() {
+		t.Error("Provider.IsValid should match constants exactly")
+	}
+	if !intasend.AccountTypePayBill.IsValid() || intasend.AccountType("Bank").IsValid() {
+		t.Error("unexpected AccountType.IsValid result")
+	}
+	if intasend.Tariff("").IsValid() || intasend.RefundReason("").IsValid() || intasend.ApprovalStatus("").IsValid() {
+		t.Error("empty values should not be valid")
+	}
+}
+
+func TestEnumValidationBeforeRequest(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	_, err := client.Payout().Initiate(ctx, &intasend.InitiateRequest{Provider: "MPESA", Currency: "KES"})
+	if !errors.Is(err, intasend.ErrInvalidEnumValue) {
+		t.Errorf("Initiate provider: expected ErrInvalidEnumValue, got %v", err)
+	}
+	_, err = client.Payout().Initiate(ctx, &intasend.InitiateRequest{
+		Provider: intasend.ProviderMPesaB2B, Currency: "KES",
+		Transactions: []intasend.Transaction{{Account: "247247", Amount: "10", AccountType: "Paybill"}},
+	})
+	if !errors.Is(err, intasend.ErrInvalidEnumValue) {
+		t.Errorf("Initiate account type: expected ErrInvalidEnumValue, got %v", err)
+	}
+	_, err = client.PaymentLink().Create(ctx, &intasend.CreatePaymentLinkRequest{Title: "x", MobileTariff: "BUSINESS"})
+	if !errors.Is(err, intasend.ErrInvalidEnumValue) {
+		t.Errorf("PaymentLink tariff: expected ErrInvalidEnumValue, got %v", err)
+	}
+	_, err = client.Refund().Create(ctx, &intasend.CreateChargebackRequest{Invoice: "INV-1", Amount: 1, Reason: "customer"})
+	if !errors.Is(err, intasend.ErrInvalidEnumValue) {
+		t.Errorf("Refund reason: expected ErrInvalidEnumValue, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("expected no API calls for invalid requests, got %d", n)
+	}
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// recordingTransport answers every request with an empty JSON object and
+// keeps the requests it saw along with their bodies.
+type recordingTransport struct {
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (t *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body.Close()
+	}
+	t.requests = append(t.requests, r)
+	t.bodies = append(t.bodies, body)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		Request:    r,
+	}, nil
+}
+
+func newProductionClient(t *testing.T, transport http.RoundTripper) *intasend.Client {
+	t.Helper()
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_live_abc123"),
+		intasend.WithSecretKey("ISSecretKey_live_secret"),
+		intasend.WithHTTPClient(&http.Client{Transport: transport}),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestContextWithEnvironment_RoutesToSandbox(t *testing.T) {
+	rt := &recordingTransport{}
+	client := newProductionClient(t, rt)
+
+	ctx := intasend.ContextWithEnvironment(context.Background(), intasend.Sandbox,
+		"ISPubKey_test_canary", "ISSecretKey_test_canary")
+	if _, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{
+		PhoneNumber: "254712345678",
+		Amount:      10,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rt.requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(rt.requests))
+	}
+	for _, r := range rt.requests {
+		if !strings.HasPrefix(r.URL.String(), intasend.SandboxBaseURL) {
+			t.Errorf("expected sandbox URL, got %s", r.URL)
+		}
+		if got := r.Header.Get("X-IntaSend-Public-API-Key"); got != "ISPubKey_test_canary" {
+			t.Errorf("expected override public key header, got %q", got)
+		}
+	}
+	if got := rt.requests[1].Header.Get("Authorization"); got != "Bearer ISSecretKey_test_canary" {
+		t.Errorf("expected override secret key, got %q", got)
+	}
+
+	var body map[string]interface{}
+	json.Unmarshal(rt.bodies[0], &body)
+	if body["public_key"] != "ISPubKey_test_canary" {
+		t.Errorf("expected override public_key in body, got %v", body["public_key"])
+	}
+}
+
+func TestContextWithEnvironment_DefaultUnchanged(t *testing.T) {
+	rt := &recordingTransport{}
+	client := newProductionClient(t, rt)
+
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := rt.requests[0]
+	if !strings.HasPrefix(r.URL.String(), intasend.ProductionBaseURL) {
+		t.Errorf("expected production URL, got %s", r.URL)
+	}
+	if got := r.Header.Get("Authorization"); got != "Bearer ISSecretKey_live_secret" {
+		t.Errorf("expected client secret key, got %q", got)
+	}
+}
+
+func TestContextWithEnvironment_NeverSendsClientKeys(t *testing.T) {
+	rt := &recordingTransport{}
+	client := newProductionClient(t, rt)
+
+	ctx := intasend.ContextWithEnvironment(context.Background(), intasend.Sandbox, "", "")
+	if env, ok := intasend.EnvironmentFromContext(ctx); !ok || env != intasend.Sandbox {
+		t.Errorf("expected sandbox in context, got %q %v", env, ok)
+	}
+	if _, err := client.Wallet().List(ctx); !errors.Is(err, intasend.ErrMissingSecretKey) {
+		t.Errorf("expected ErrMissingSecretKey, got %v", err)
+	}
+	if _, err := client.Collection().Charge(ctx, &intasend.ChargeRequest{Amount: 10, Currency: "KES"}); !errors.Is(err, intasend.ErrMissingPublishableKey) {
+		t.Errorf("expected ErrMissingPublishableKey, got %v", err)
+	}
+
+	// Live keys are refused for the sandbox, and test keys for production.
+	ctx = intasend.ContextWithEnvironment(context.Background(), intasend.Sandbox, "ISPubKey_live_abc123", "ISSecretKey_live_secret")
+	if _, err := client.Wallet().List(ctx); !errors.Is(err, intasend.ErrEnvironmentMismatch) {
+		t.Errorf("expected ErrEnvironmentMismatch, got %v", err)
+	}
+	ctx = intasend.ContextWithEnvironment(context.Background(), intasend.Production, "", "ISSecretKey_test_canary")
+	if _, err := client.Wallet().List(ctx); !errors.Is(err, intasend.ErrEnvironmentMismatch) {
+		t.Errorf("expected ErrEnvironmentMismatch, got %v", err)
+	}
+	if len(rt.requests) != 0 {
+		t.Fatalf("expected no requests, got %d", len(rt.requests))
+	}
+
+	// A secret key alone does not carry the client's publishable key along.
+	ctx = intasend.ContextWithEnvironment(context.Background(), intasend.Sandbox, "", "ISSecretKey_test_canary")
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := rt.requests[0].Header.Get("X-IntaSend-Public-API-Key"); got != "" {
+		t.Errorf("expected no public key header, got %q", got)
+	}
+}
+
+func TestContextWithEnvironment_Unknown(t *testing.T) {
+	rt := &recordingTransport{}
+	client := newProductionClient(t, rt)
+
+	ctx := intasend.ContextWithEnvironment(context.Background(), "staging", "", "")
+	if _, err := client.Wallet().List(ctx); err == nil {
+		t.Fatal("expected error for unknown environment")
+	}
+	if len(rt.requests) != 0 {
+		t.Errorf("expected no requests, got %d", len(rt.requests))
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -215,3 +216,67 @@ func TestNetworkError_ErrorsIs(t *testing.T) {
 		t.Error("expected errors.Is to find sentinel through NetworkError")
 	}
 }
+
+func TestNilRequestGuards(t *testing.T) {
+	client, err := intasend.New(intasend.WithSecretKey("ISSecretKey_test_secret"), intasend.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"Collection.Charge":       func() error { _, err := client.Collection().Charge(ctx, nil); return err },
+		"Collection.MPesaSTKPush": func() error { _, err := client.Collection().MPesaSTKPush(ctx, nil); return err },
+		"Checkout.Create":         func() error { _, err := client.Checkout().Create(ctx, nil); return err },
+		"Payout.Initiate":         func() error { _, err := client.Payout().Initiate(ctx, nil); return err },
+		"Payout.MPesaB2B":         func() error { _, err := client.Payout().MPesaB2B(ctx, nil); return err },
+		"Payout.Approve":          func() error { _, err := client.Payout().Approve(ctx, nil); return err },
+		"Payout.Correlate":        func() error { _, err := client.Payout().Correlate(ctx, nil); return err },
+		"Refund.Create":           func() error { _, err := client.Refund().Create(ctx, nil); return err },
+		"Wallet.IntraTransfer":    func() error { _, err := client.Wallet().IntraTransfer(ctx, nil); return err },
+		"PaymentLink.Create":      func() error { _, err := client.PaymentLink().Create(ctx, nil); return err },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, intasend.ErrNilRequest) {
+			t.Errorf("%s: expected ErrNilRequest, got %v", name, err)
+		}
+	}
+
+	queue := client.Collection().NewQueue(ctx, nil)
+	if err := queue.Enqueue(nil); !errors.Is(err, intasend.ErrNilRequest) {
+		t.Errorf("Enqueue: expected ErrNilRequest, got %v", err)
+	}
+	queue.Close()
+
+	unbound := map[string]func() error{
+		"WalletService.List": func() error { _, err := (&intasend.WalletService{}).List(ctx); return err },
+		"PayoutService.Initiate": func() error {
+			_, err := (&intasend.PayoutService{}).Initiate(ctx, &intasend.InitiateRequest{})
+			return err
+		},
+		"CollectionService.Status": func() error {
+			_, err := (&intasend.CollectionService{}).Status(ctx, "INV-1", nil)
+			return err
+		},
+		"CollectionService.ResendSTKPush": func() error {
+			_, err := (&intasend.CollectionService{}).ResendSTKPush(ctx, "INV-1")
+			return err
+		},
+		"CollectionService.StatusBatch": func() error {
+			_, err := (&intasend.CollectionService{}).StatusBatch(ctx, []string{"INV-1"}, nil)
+			return err
+		},
+	}
+	for name, call := range unbound {
+		if err := call(); !errors.Is(err, intasend.ErrNilClient) {
+			t.Errorf("unbound %s: expected ErrNilClient, got %v", name, err)
+		}
+	}
+
+	if _, err := client.Collection().Status(ctx, "", nil); !errors.Is(err, intasend.ErrIncompleteRequest) {
+		t.Errorf("Status without an invoice ID: expected ErrIncompleteRequest, got %v", err)
+	}
+	if _, err := client.Collection().ResendSTKPush(ctx, ""); !errors.Is(err, intasend.ErrIncompleteRequest) {
+		t.Errorf("ResendSTKPush without an invoice ID: expected ErrIncompleteRequest, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestEvents_Subscribe(t *testing.T) {
+	statusCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/payment/status/":
+			statusCalls++
+			if statusCalls == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"invoice": map[string]interface{}{"invoice_id": "INV-1", "state": "COMPLETE"},
+			})
+		case "/send-money/initiate/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"tracking_id": "TRK-1", "status": "Preview and approve"})
+		case "/send-money/approve/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"tracking_id": "TRK-1", "status": "Processing"})
+		}
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(1, time.Millisecond),
+		intasend.WithLogger(log.New(io.Discard, "", 0)),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var mu sync.Mutex
+	var names []string
+	var completed *intasend.Invoice
+	unsubscribe := client.Events().Subscribe(func(ctx context.Context, e intasend.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		names = append(names, e.EventName())
+		switch e := e.(type) {
+		case intasend.PaymentCompleted:
+			completed = e.Invoice
+		case intasend.RequestFinished:
+			if e.Endpoint == "" || e.Duration <= 0 {
+				t.Errorf("expected request details, got %+v", e.RequestMetrics)
+			}
+		}
+	})
+	// A panicking subscriber must not break requests or other subscribers.
+	client.Events().Subscribe(func(context.Context, intasend.Event) { panic("boom") })
+
+	ctx := context.Background()
+	if _, err := client.Collection().Status(ctx, "INV-1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"request.started", "request.rate_limited", "request.retried", "request.finished", "payment.completed"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+	if completed == nil || completed.InvoiceID != "INV-1" {
+		t.Errorf("unexpected completed invoice: %+v", completed)
+	}
+
+	names = nil
+	resp, err := client.Payout().MPesa(ctx, &intasend.MPesaRequest{
+		Currency:     "KES",
+		Transactions: []intasend.Transaction{{Account: "254712345678", Amount: "100"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Payout().Approve(ctx, &intasend.ApproveRequest{TrackingID: resp.TrackingID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []string{"request.started", "request.finished", "payout.initiated", "request.started", "request.finished", "payout.approved"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	names = nil
+	if _, err := client.Payout().Status(ctx, "TRK-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no events after unsubscribing, got %v", names)
+	}
+}
+
+func TestEvents_Actor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/send-money/initiate/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"tracking_id": "TRK-1", "nonce": "n-1"})
+		case "/send-money/approve/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"tracking_id": "TRK-1", "status": "Processing"})
+		case "/chargebacks/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"chargeback_id": "CB-1"})
+		}
+	}))
+	defer server.Close()
+
+	store := intasend.NewMemoryTrackingStore()
+	var pending *intasend.PendingApproval
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithTrackingStore(store),
+		intasend.WithApprovalNotifier("", func(ctx context.Context, p *intasend.PendingApproval) { pending = p }),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actors := map[string]intasend.Actor{}
+	client.Events().Subscribe(func(ctx context.Context, e intasend.Event) {
+		switch e := e.(type) {
+		case intasend.PayoutInitiated:
+			actors[e.EventName()] = e.Actor
+		case intasend.PayoutApproved:
+			actors[e.EventName()] = e.Actor
+		case intasend.RefundCreated:
+			actors[e.EventName()] = e.Actor
+		}
+	})
+
+	maker := intasend.Actor{ID: "u-1", Email: "maker@example.com"}
+	checker := intasend.Actor{ID: "u-2", Email: "checker@example.com"}
+	ctx := intasend.ContextWithActor(context.Background(), maker)
+	if got, ok := intasend.ActorFromContext(ctx); !ok || got != maker {
+		t.Errorf("expected %+v from context, got %+v", maker, got)
+	}
+	if _, ok := intasend.ActorFromContext(context.Background()); ok {
+		t.Error("expected no actor on a bare context")
+	}
+
+	resp, err := client.Payout().MPesa(ctx, &intasend.MPesaRequest{
+		Currency:     "KES",
+		Transactions: []intasend.Transaction{{Account: "254712345678", Amount: "100"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx = intasend.ContextWithActor(ctx, checker)
+	if _, err := client.Payout().Approve(ctx, &intasend.ApproveRequest{TrackingID: resp.TrackingID, Nonce: resp.Nonce}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Refund().Create(ctx, &intasend.CreateChargebackRequest{Invoice: "INV-1", Amount: 100, Reason: intasend.RefundReasonCustomerRequest}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]intasend.Actor{"payout.initiated": maker, "payout.approved": checker, "refund.created": checker}
+	if !reflect.DeepEqual(actors, want) {
+		t.Errorf("expected actors %+v, got %+v", want, actors)
+	}
+	if pending == nil || pending.Actor != maker {
+		t.Errorf("expected the pending approval to name the maker, got %+v", pending)
+	}
+	tracked, err := store.LoadPayout(context.Background(), "TRK-1")
+	if err != nil || tracked.Actor != maker {
+		t.Errorf("expected the tracked payout to name the maker, got %+v, %v", tracked, err)
+	}
+}
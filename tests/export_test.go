@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/export"
+)
+
+func TestExport_WalletTransactionsCSV(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wallets/W1/transactions/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{Next: "2", Results: []intasend.WalletTransaction{
+				{TransactionID: "T1", WalletID: "W1", TransType: "SALE", Amount: 100.5, CreatedAt: created},
+				{TransactionID: "T2", WalletID: "W1", TransType: "SALE", Amount: 20, Narrative: "order, 2"},
+			}})
+		case "2":
+			// T2 shifted onto this page because a new transaction arrived.
+			json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{Results: []intasend.WalletTransaction{
+				{TransactionID: "T2", WalletID: "W1", TransType: "SALE", Amount: 20, Narrative: "order, 2"},
+				{TransactionID: "T3", WalletID: "W1", TransType: "CHARGE", Amount: -5},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := newTestClient(t, server)
+	n, err := export.New(client).WalletTransactions(context.Background(), export.NewCSVSink(&buf), "W1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 records after dedupe, got %d", n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 rows, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "transaction_id,wallet_id,trans_type,amount") {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if lines[1] != "T1,W1,SALE,100.5,,0,2024-03-01T09:00:00Z" {
+		t.Errorf("unexpected row: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"order, 2"`) {
+		t.Errorf("expected quoted narrative, got %s", lines[2])
+	}
+}
+
+func TestExport_Concurrent(t *testing.T) {
+	const pages = 8
+	var inflight, maxInflight int32
+	var failPage atomic.Value
+	failPage.Store("")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInflight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+				break
+			}
+		}
+		page := 0
+		fmt.Sscan(r.URL.Query().Get("page"), &page)
+		if r.URL.Query().Get("page") == failPage.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"detail":"bad page"}`))
+			return
+		}
+		// Later pages answer first, so they arrive out of order.
+		time.Sleep(time.Duration(pages-page) * 5 * time.Millisecond)
+		// The count predates the last page, added during the export.
+		resp := intasend.WalletTransactionsResponse{Count: 2 * (pages - 1)}
+		if page < pages {
+			resp.Next = "more"
+		}
+		for i := 1; i <= 2; i++ {
+			resp.Results = append(resp.Results, intasend.WalletTransaction{TransactionID: fmt.Sprintf("T-%02d", 2*(page-1)+i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+	exporter := export.New(client).WithConcurrency(4)
+
+	var buf bytes.Buffer
+	n, err := exporter.WalletTransactions(context.Background(), export.NewJSONSink(&buf), "W1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2*pages {
+		t.Errorf("expected %d records, got %d", 2*pages, n)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for i, line := range lines {
+		if want := fmt.Sprintf(`"transaction_id":"T-%02d"`, i+1); !strings.Contains(line, want) {
+			t.Fatalf("expected records in page order, line %d is %s", i, line)
+		}
+	}
+	if m := atomic.LoadInt32(&maxInflight); m < 2 || m > 4 {
+		t.Errorf("expected 2 to 4 pages in flight, got %d", m)
+	}
+
+	failPage.Store("5")
+	_, err = exporter.WalletTransactions(context.Background(), export.NewJSONSink(&buf), "W1", nil)
+	if err == nil || !strings.Contains(err.Error(), "page 5") {
+		t.Errorf("expected an error for page 5, got %v", err)
+	}
+}
+
+func TestExport_WalletTransactionsJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wallets/W1/transactions/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{Results: []intasend.WalletTransaction{
+			{TransactionID: "T1", WalletID: "W1", TransType: "SALE", Amount: 250, Narrative: "Order 1"},
+		}})
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := newTestClient(t, server)
+	n, err := export.New(client).WalletTransactions(context.Background(), export.NewJSONSink(&buf), "W1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 record, got %d", n)
+	}
+	want := `{"transaction_id":"T1","wallet_id":"W1","trans_type":"SALE","amount":250,"narrative":"Order 1","running_balance":0,"created_at":null}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("unexpected JSON line:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestExport_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := export.New(client).WalletTransactions(context.Background(), export.NewCSVSink(&bytes.Buffer{}), "W1", nil)
+	if intasend.AsAPIError(err) == nil {
+		t.Errorf("expected wrapped API error, got %v", err)
+	}
+}
+
+func TestExport_ResumeFromCheckpoint(t *testing.T) {
+	var failPage2 int32 = 1
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		requested = append(requested, page)
+		switch page {
+		case "1":
+			json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{Next: "2", Results: []intasend.WalletTransaction{
+				{TransactionID: "T1"}, {TransactionID: "T2"},
+			}})
+		case "2":
+			if atomic.CompareAndSwapInt32(&failPage2, 1, 0) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"detail":"boom"}`))
+				return
+			}
+			json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{Results: []intasend.WalletTransaction{
+				{TransactionID: "T2"}, {TransactionID: "T3"},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	store, err := export.NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exporter := export.New(newTestClient(t, server)).WithCheckpoints(store, "job-1")
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	if _, err := exporter.WalletTransactions(ctx, export.NewCSVSink(&out), "W1", nil); err == nil {
+		t.Fatal("expected the first run to fail on page 2")
+	}
+	cp, err := store.LoadCheckpoint(ctx, "job-1/wallet_transactions/W1")
+	if err != nil || cp == nil || cp.Page != 1 || cp.Written != 2 {
+		t.Fatalf("expected checkpoint after page 1, got %+v, %v", cp, err)
+	}
+
+	n, err := exporter.WalletTransactions(ctx, export.NewCSVSink(&out), "W1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 records in total, got %d", n)
+	}
+	if fmt.Sprint(requested) != "[1 2 2]" {
+		t.Errorf("expected page 1 not to be fetched again, got %v", requested)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "transaction_id,") || !strings.HasPrefix(lines[3], "T3,") {
+		t.Errorf("expected one header and 3 rows, got:\n%s", out.String())
+	}
+	if cp, _ := store.LoadCheckpoint(ctx, "job-1/wallet_transactions/W1"); cp != nil {
+		t.Errorf("expected checkpoint to be deleted, got %+v", cp)
+	}
+}
@@ -0,0 +1,317 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// flakyTransport fails requests to one host with a configurable error.
+type flakyTransport struct {
+	mu   sync.Mutex
+	host string
+	err  error
+	hits map[string]int
+}
+
+func (f *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.hits[req.URL.Host]++
+	err := f.err
+	f.mu.Unlock()
+	if req.URL.Host == f.host && err != nil {
+		return nil, err
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (f *flakyTransport) set(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+func (f *flakyTransport) count(host string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hits[host]
+}
+
+var errDialRefused = &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+func newFailoverClient(t *testing.T, transport http.RoundTripper, primary, fallback string) *intasend.Client {
+	t.Helper()
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURLs(primary, fallback),
+		intasend.WithFailoverCooldown(50*time.Millisecond),
+		intasend.WithHTTPClient(&http.Client{Transport: transport}),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func failoverServers(t *testing.T) (primary, fallback *httptest.Server, fallbackHits *int32) {
+	t.Helper()
+	handler := func(w http.ResponseWriter, r *http.Request) { stkOK(w) }
+	var hits int32
+	primary = httptest.NewServer(http.HandlerFunc(handler))
+	fallback = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(primary.Close)
+	t.Cleanup(fallback.Close)
+	return primary, fallback, &hits
+}
+
+func TestFailover_UnreachablePrimary(t *testing.T) {
+	primary, fallback, fallbackHits := failoverServers(t)
+	primaryHost := mustHost(t, primary.URL)
+	transport := &flakyTransport{host: primaryHost, err: errDialRefused, hits: map[string]int{}}
+	client := newFailoverClient(t, transport, primary.URL, fallback.URL)
+
+	ctx := context.Background()
+	req := &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10}
+	if _, err := client.Collection().MPesaSTKPush(ctx, req); err != nil {
+		t.Fatalf("expected failover to succeed, got %v", err)
+	}
+	if _, err := client.Collection().MPesaSTKPush(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transport.count(primaryHost) != 1 {
+		t.Errorf("expected primary to be skipped during cooldown, got %d attempts", transport.count(primaryHost))
+	}
+	if *fallbackHits != 2 {
+		t.Errorf("expected 2 fallback requests, got %d", *fallbackHits)
+	}
+
+	// Once the primary recovers and the cooldown passes, it is preferred again.
+	transport.set(nil)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Collection().MPesaSTKPush(ctx, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *fallbackHits != 2 || transport.count(primaryHost) != 2 {
+		t.Errorf("expected request to return to primary, fallback=%d primary=%d", *fallbackHits, transport.count(primaryHost))
+	}
+}
+
+func TestFailover_ResendsBody(t *testing.T) {
+	primary, fallback, _ := failoverServers(t)
+	var got map[string]interface{}
+	fallback.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		stkOK(w)
+	})
+	transport := &flakyTransport{host: mustHost(t, primary.URL), err: errDialRefused, hits: map[string]int{}}
+	client := newFailoverClient(t, transport, primary.URL, fallback.URL)
+
+	req := &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10, APIRef: "order-1"}
+	if _, err := client.Collection().MPesaSTKPush(context.Background(), req); err != nil {
+		t.Fatalf("expected failover to succeed, got %v", err)
+	}
+	if got["phone_number"] != "254712345678" || got["api_ref"] != "order-1" {
+		t.Errorf("expected the fallback to receive the request body, got %v", got)
+	}
+}
+
+func TestFailover_PostNotResentAfterAmbiguousError(t *testing.T) {
+	primary, fallback, fallbackHits := failoverServers(t)
+	transport := &flakyTransport{host: mustHost(t, primary.URL), err: io.ErrUnexpectedEOF, hits: map[string]int{}}
+	client := newFailoverClient(t, transport, primary.URL, fallback.URL)
+
+	_, err := client.Collection().MPesaSTKPush(context.Background(), &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10})
+	if !intasend.IsNetworkError(err) {
+		t.Fatalf("expected network error, got %v", err)
+	}
+	if *fallbackHits != 0 {
+		t.Error("POST must not fail over after a possibly delivered request")
+	}
+
+	// GET requests are safe to repeat elsewhere.
+	fallback.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(fallbackHits, 1)
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	})
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("expected GET to fail over, got %v", err)
+	}
+	if *fallbackHits != 1 {
+		t.Errorf("expected 1 fallback request, got %d", *fallbackHits)
+	}
+}
+
+// sequenceTransport fails requests to one host with errs in turn.
+type sequenceTransport struct {
+	mu   sync.Mutex
+	host string
+	errs []error
+}
+
+func (s *sequenceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	var err error
+	if req.URL.Host == s.host && len(s.errs) > 0 {
+		err, s.errs = s.errs[0], s.errs[1:]
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestFailover_PostNotResentAfterEarlierAmbiguousAttempt(t *testing.T) {
+	primary, fallback, fallbackHits := failoverServers(t)
+	// The first attempt may have reached IntaSend; only the retry fails to
+	// connect.
+	transport := &sequenceTransport{host: mustHost(t, primary.URL), errs: []error{io.ErrUnexpectedEOF, errDialRefused}}
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURLs(primary.URL, fallback.URL),
+		intasend.WithHTTPClient(&http.Client{Transport: transport}),
+		intasend.WithRetry(1, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = client.Collection().MPesaSTKPush(context.Background(), &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10})
+	if !intasend.IsNetworkError(err) {
+		t.Fatalf("expected network error, got %v", err)
+	}
+	if *fallbackHits != 0 {
+		t.Error("POST must not fail over once an earlier attempt may have been delivered")
+	}
+}
+
+func mustHost(t *testing.T, raw string) string {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
+
+func TestProbeEndpoints(t *testing.T) {
+	var fastHits, slowHits int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&slowHits, 1)
+		if r.URL.Path == "/" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fastHits, 1)
+		if r.URL.Path == "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	}))
+	defer fast.Close()
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL
+	dead.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURLs(deadURL, slow.URL, fast.URL),
+		intasend.WithRetry(0, 0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	results, err := client.ProbeEndpoints(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 || results[0].BaseURL != fast.URL || results[1].BaseURL != slow.URL || results[2].Err == nil {
+		t.Fatalf("expected fast, slow, then unreachable, got %+v", results)
+	}
+	if client.BaseURL() != fast.URL {
+		t.Errorf("expected BaseURL to report the fastest URL, got %s", client.BaseURL())
+	}
+	data, err := client.SupportBundle(context.Background())
+	if err != nil {
+		t.Fatalf("SupportBundle: %v", err)
+	}
+	var bundle struct {
+		BaseURL      string   `json:"base_url"`
+		FallbackURLs []string `json:"fallback_urls"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatalf("invalid bundle: %v", err)
+	}
+	if bundle.BaseURL != fast.URL || len(bundle.FallbackURLs) != 2 || bundle.FallbackURLs[0] != slow.URL || bundle.FallbackURLs[1] != deadURL {
+		t.Errorf("expected the bundle to report the probed order, got %s", data)
+	}
+
+	atomic.StoreInt32(&fastHits, 0)
+	atomic.StoreInt32(&slowHits, 0)
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if atomic.LoadInt32(&fastHits) != 1 || atomic.LoadInt32(&slowHits) != 0 {
+		t.Errorf("expected requests to go to the fastest URL, got fast=%d slow=%d", fastHits, slowHits)
+	}
+}
+
+func TestFailover_LogRedactsURL(t *testing.T) {
+	primary, fallback, _ := failoverServers(t)
+	fallback.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	transport := &flakyTransport{host: mustHost(t, primary.URL), err: errDialRefused, hits: map[string]int{}}
+
+	var mu sync.Mutex
+	var errs []string
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURLs(primary.URL, fallback.URL),
+		intasend.WithHTTPClient(&http.Client{Transport: transport}),
+		intasend.WithRetry(0, 0),
+		intasend.WithDebug(true),
+		intasend.WithStructuredLogger(intasend.StructuredLoggerFunc(func(ctx context.Context, level intasend.LogLevel, msg string, fields map[string]interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			if msg == "endpoint unreachable, failing over" {
+				errs = append(errs, fmt.Sprint(fields["error"]))
+			}
+		})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if err := client.Do(context.Background(), http.MethodGet, "/some/new/route/?email=jane%40example.com", nil, nil); err != nil {
+		t.Fatalf("expected failover to succeed, got %v", err)
+	}
+	if len(errs) != 1 || strings.Contains(errs[0], "jane") || !strings.Contains(errs[0], "email=[REDACTED]") {
+		t.Errorf("expected the failover warning to redact the URL, got %v", errs)
+	}
+}
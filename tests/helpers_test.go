@@ -57,6 +57,7 @@ type stkPushRequestBody struct {
 	Name        string  `json:"name"`
 	Email       string  `json:"email"`
 	WalletID    string  `json:"wallet_id"`
+	Narrative   string  `json:"narrative"`
 	Method      string  `json:"method"`
 	Currency    string  `json:"currency"`
 }
@@ -127,4 +128,5 @@ type createCheckoutRequestBody struct {
 	CardTariff   string  `json:"card_tarrif"`
 	MobileTariff string  `json:"mobile_tarrif"`
 	WalletID     string  `json:"wallet_id"`
+	Locale       string  `json:"locale"`
 }
@@ -3,8 +3,11 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -295,6 +298,100 @@ func TestHTTP_RetryOn429(t *testing.T) {
 	}
 }
 
+func TestHTTP_RetryAfterHonored(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/wallets/W-LONG/" {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	}))
+	defer server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(1, 10*time.Second),
+	)
+
+	// Retry-After replaces the 10s backoff.
+	start := time.Now()
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("expected success after 429 retry, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("expected a ~1s wait from Retry-After, took %v", elapsed)
+	}
+
+	// A Retry-After past the deadline returns the 429 instead of waiting.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	start = time.Now()
+	_, err := client.Wallet().Get(ctx, "W-LONG")
+	var apiErr *intasend.APIError
+	if !errors.As(err, &apiErr) || !apiErr.IsRateLimited() || apiErr.RetryAfter() != time.Minute {
+		t.Fatalf("expected a 429 with Retry-After 1m, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("expected no wait for a Retry-After past the deadline")
+	}
+}
+
+func TestHTTP_RateLimit(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	}))
+	defer server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRateLimit(20),
+	)
+
+	// A burst of 20 goes through at once; the next 5 are spaced 50ms apart.
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Wallet().List(context.Background()); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected requests over the limit to wait, took %v", elapsed)
+	}
+	if calls.Load() != 25 {
+		t.Errorf("expected 25 requests, got %d", calls.Load())
+	}
+
+	// A waiting request gives up when its context ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 25; i++ {
+		if _, err := client.Wallet().List(ctx); err != nil {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected a deadline error, got %v", err)
+			}
+			return
+		}
+	}
+	t.Error("expected the rate limit to outlast the context")
+}
+
 func TestHTTP_AllRetriesExhausted(t *testing.T) {
 	var calls int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -364,3 +461,458 @@ func TestHTTP_NonJSONErrorBody(t *testing.T) {
 		t.Errorf("expected plain text in message, got %q", apiErr.Message)
 	}
 }
+
+func TestHTTP_MaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"results":[` + strings.Repeat(`{},`, 100) + `{}]}`))
+	}))
+	defer server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithMaxResponseBytes(64),
+	)
+
+	_, err := client.Wallet().List(context.Background())
+	if !errors.Is(err, intasend.ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestHTTP_MaxResponseBytesWithinLimit(t *testing.T) {
+	body := `{"results":[]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithMaxResponseBytes(int64(len(body))),
+	)
+
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHTTP_NetworkErrorTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpClient := server.Client()
+	httpClient.Timeout = 20 * time.Millisecond
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(httpClient),
+		intasend.WithRetry(0, 0),
+	)
+
+	_, err := client.Wallet().List(context.Background())
+	var netErr *intasend.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("expected NetworkError, got %v", err)
+	}
+	if !netErr.IsTimeout() {
+		t.Error("expected IsTimeout() to be true")
+	}
+	if netErr.IsConnRefused() {
+		t.Error("expected IsConnRefused() to be false")
+	}
+}
+
+func TestHTTP_TimeoutWithCustomClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()), // no Timeout of its own
+		intasend.WithTimeout(20*time.Millisecond),
+		intasend.WithRetry(0, 0),
+	)
+
+	_, err := client.Wallet().List(context.Background())
+	var netErr *intasend.NetworkError
+	if !errors.As(err, &netErr) || !netErr.IsTimeout() {
+		t.Fatalf("expected a timeout NetworkError, got %v", err)
+	}
+
+	// A caller's deadline takes precedence over the client's timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHTTP_TimeoutWithDefaultClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithTimeout(50*time.Millisecond),
+		intasend.WithRetry(0, 0),
+	)
+
+	_, err := client.Wallet().List(context.Background())
+	var netErr *intasend.NetworkError
+	if !errors.As(err, &netErr) || !netErr.IsTimeout() {
+		t.Fatalf("expected a timeout NetworkError, got %v", err)
+	}
+
+	// A longer caller deadline or request timeout is not cut short by the
+	// client's timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Wallet().List(ctx); err != nil {
+		t.Errorf("ctx deadline: unexpected error: %v", err)
+	}
+	if _, err := client.Wallet().List(context.Background(), intasend.WithRequestTimeout(5*time.Second)); err != nil {
+		t.Errorf("WithRequestTimeout: unexpected error: %v", err)
+	}
+}
+
+func TestHTTP_NetworkErrorConnRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(url),
+		intasend.WithRetry(0, 0),
+	)
+
+	_, err := client.Wallet().List(context.Background())
+	var netErr *intasend.NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("expected NetworkError, got %v", err)
+	}
+	if !netErr.IsConnRefused() {
+		t.Errorf("expected IsConnRefused() to be true for %v", netErr.Err)
+	}
+	if netErr.IsTimeout() {
+		t.Error("expected IsTimeout() to be false")
+	}
+}
+
+// closeFirstHandler drops the connection without a response on the first
+// request, like a server that closed an idle keep-alive connection.
+func closeFirstHandler(t *testing.T, calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Fatalf("hijack failed: %v", err)
+			}
+			conn.Close()
+			return
+		}
+		json.NewEncoder(w).Encode(intasend.Wallet{WalletID: "W-1"})
+	}
+}
+
+func TestHTTP_ReplayGetOnStaleConnection(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(closeFirstHandler(t, &calls))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(1, 5*time.Second),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	start := time.Now()
+	wallet, err := client.Wallet().Get(context.Background(), "W-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wallet.WalletID != "W-1" || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("expected replayed request, got %d calls", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("replay should skip backoff, took %v", elapsed)
+	}
+}
+
+func TestHTTP_NoReplayPostOnStaleConnection(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(closeFirstHandler(t, &calls))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := client.Wallet().Create(context.Background(), &intasend.CreateWalletRequest{Label: "Ops", Currency: "KES"})
+	if !intasend.IsNetworkError(err) {
+		t.Fatalf("expected network error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("POST must not be replayed, got %d calls", n)
+	}
+}
+
+func TestHTTP_APIErrorDiagnosticHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("CF-Ray", "8a1b2c3d4e5f-NBO")
+		w.Header().Set("X-Request-ID", "req-123")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<html>Access denied</html>"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := client.Wallet().List(context.Background())
+	apiErr := intasend.AsAPIError(err)
+	if apiErr == nil {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.RayID() != "8a1b2c3d4e5f-NBO" {
+		t.Errorf("expected Ray ID, got %q", apiErr.RayID())
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("expected request ID from header, got %q", apiErr.RequestID)
+	}
+	if apiErr.Headers.Get("Set-Cookie") != "" {
+		t.Error("non-diagnostic headers should not be captured")
+	}
+}
+
+func TestHTTP_WithHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Internal-Client"); got != "billing" {
+			t.Errorf("expected X-Internal-Client header, got %q", got)
+		}
+		if got := r.Header.Values("X-Tag"); len(got) != 2 {
+			t.Errorf("expected accumulated X-Tag values, got %v", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer ISSecretKey_test_secret" {
+			t.Errorf("SDK auth header should win, got %q", got)
+		}
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHeaders(http.Header{
+			"x-internal-client": {"billing"},
+			"X-Tag":             {"a"},
+			"Authorization":     {"Bearer other"},
+		}),
+		intasend.WithHeaders(http.Header{"X-Tag": {"b"}}),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHTTP_RequestOptions(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/wallets/":
+			if got := r.Header.Get("X-Trace"); got != "trace-1" {
+				t.Errorf("expected per-call X-Trace header, got %q", got)
+			}
+			if got := r.Header.Get("X-Internal-Client"); got != "checkout" {
+				t.Errorf("per-call header should replace the client's, got %q", got)
+			}
+			if got := r.Header.Get("Authorization"); got != "Bearer ISSecretKey_test_secret" {
+				t.Errorf("SDK auth header should win, got %q", got)
+			}
+			if got := r.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("SDK content type should win, got %q", got)
+			}
+			if got := r.Header.Get("X-IntaSend-Public-API-Key"); got != "" {
+				t.Errorf("public key header should not be settable, got %q", got)
+			}
+			json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+		case "/slow/":
+			time.Sleep(200 * time.Millisecond)
+			json.NewEncoder(w).Encode(intasend.WalletListResponse{})
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHeaders(http.Header{"X-Internal-Client": {"billing"}}),
+		intasend.WithRetry(0, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = client.Wallet().List(ctx,
+		intasend.WithHeader("X-Trace", "trace-1"),
+		intasend.WithHeader("X-Internal-Client", "checkout"),
+		intasend.WithHeader("Authorization", "Bearer other"),
+		intasend.WithHeader("content-type", "text/plain"),
+		intasend.WithHeader("X-IntaSend-Public-API-Key", "ISPubKey_live_other"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits.Store(0)
+	if _, err := client.Wallet().Get(ctx, "W-1", intasend.WithRequestRetries(2)); err == nil {
+		t.Fatal("expected an error from the failing endpoint")
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("expected 3 attempts with 2 per-call retries, got %d", got)
+	}
+
+	start := time.Now()
+	err = client.Do(ctx, http.MethodGet, "/slow/", nil, nil, intasend.WithRequestTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the per-call timeout to apply, got %v", err)
+	}
+	if time.Since(start) > 150*time.Millisecond {
+		t.Errorf("per-call timeout did not cut the request short")
+	}
+
+	// Multi-request helpers pass their options on to every request.
+	hits.Store(0)
+	_, err = client.Collection().StatusBatch(ctx, []string{"INV-1", "INV-2"}, nil, intasend.WithRequestRetries(1))
+	if err == nil {
+		t.Fatal("expected an error from the failing endpoint")
+	}
+	if got := hits.Load(); got != 4 {
+		t.Errorf("expected 2 attempts for each of 2 invoices, got %d", got)
+	}
+}
+
+// redirectHandler redirects /old/... to /new/... with status and records
+// what reaches the new location.
+type redirectHandler struct {
+	status int
+	hits   atomic.Int32
+	method atomic.Value
+	body   atomic.Value
+	key    atomic.Value
+}
+
+func (h *redirectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/old/") {
+		http.Redirect(w, r, "/new/"+strings.TrimPrefix(r.URL.Path, "/old/"), h.status)
+		return
+	}
+	h.hits.Add(1)
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	h.method.Store(r.Method)
+	h.body.Store(body)
+	h.key.Store(r.Header.Get("Idempotency-Key"))
+	json.NewEncoder(w).Encode(map[string]string{"wallet_id": "W-1"})
+}
+
+func TestHTTP_RedirectFollowedForGet(t *testing.T) {
+	h := &redirectHandler{status: http.StatusFound}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	if err := client.Do(context.Background(), http.MethodGet, "/old/wallets/", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.hits.Load() != 1 || h.method.Load() != http.MethodGet {
+		t.Errorf("expected redirected GET, got %d hits", h.hits.Load())
+	}
+}
+
+func TestHTTP_RedirectRefusedForPost(t *testing.T) {
+	for _, status := range []int{http.StatusFound, http.StatusTemporaryRedirect} {
+		h := &redirectHandler{status: status}
+		server := httptest.NewServer(h)
+
+		client, _ := intasend.New(
+			intasend.WithSecretKey("ISSecretKey_test_secret"),
+			intasend.WithBaseURL(server.URL),
+			intasend.WithRetry(3, time.Millisecond),
+		)
+		err := client.Do(context.Background(), http.MethodPost, "/old/wallets/", map[string]string{"label": "x"}, nil)
+		var redirectErr *intasend.RedirectError
+		if !errors.Is(err, intasend.ErrRedirectRefused) || !errors.As(err, &redirectErr) {
+			t.Errorf("%d: expected ErrRedirectRefused, got %v", status, err)
+		} else if redirectErr.StatusCode != status || !strings.HasSuffix(redirectErr.Location, "/new/wallets/") {
+			t.Errorf("%d: unexpected redirect error: %+v", status, redirectErr)
+		}
+		if h.hits.Load() != 0 {
+			t.Errorf("%d: redirect target should not be reached", status)
+		}
+		server.Close()
+	}
+}
+
+func TestHTTP_RedirectPostWithIdempotencyKey(t *testing.T) {
+	h := &redirectHandler{status: http.StatusPermanentRedirect}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	ctx := intasend.ContextWithIdempotencyKey(context.Background(), "order-1")
+	if err := client.Do(ctx, http.MethodPost, "/old/wallets/", map[string]string{"label": "x"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.method.Load() != http.MethodPost || h.key.Load() != "order-1" {
+		t.Errorf("expected POST with idempotency key, got %v %v", h.method.Load(), h.key.Load())
+	}
+	if body, _ := h.body.Load().(map[string]interface{}); body["label"] != "x" {
+		t.Errorf("expected body to be re-sent, got %v", body)
+	}
+
+	h302 := &redirectHandler{status: http.StatusFound}
+	server302 := httptest.NewServer(h302)
+	defer server302.Close()
+	client302 := newTestClient(t, server302)
+	if err := client302.Do(ctx, http.MethodPost, "/old/wallets/", nil, nil); !errors.Is(err, intasend.ErrRedirectRefused) {
+		t.Errorf("expected 302 POST to be refused even with a key, got %v", err)
+	}
+}
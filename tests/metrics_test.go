@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// recordingCollector stores every observation it receives.
+type recordingCollector struct {
+	mu       sync.Mutex
+	observed []intasend.RequestMetrics
+}
+
+func (r *recordingCollector) ObserveRequest(m intasend.RequestMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observed = append(r.observed, m)
+}
+
+func (r *recordingCollector) last(t *testing.T) intasend.RequestMetrics {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.observed) == 0 {
+		t.Fatal("expected at least one observation")
+	}
+	return r.observed[len(r.observed)-1]
+}
+
+func TestMetrics_ObserveSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+	}))
+	defer server.Close()
+
+	rec := &recordingCollector{}
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithMetricsCollector(rec),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.Wallet().Transactions(context.Background(), "WALLET123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := rec.last(t)
+	if m.Method != http.MethodGet {
+		t.Errorf("expected GET, got %s", m.Method)
+	}
+	if m.Endpoint != "/wallets/:id/transactions/" {
+		t.Errorf("expected normalized endpoint, got %s", m.Endpoint)
+	}
+	if m.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", m.StatusCode)
+	}
+	if m.Err != nil {
+		t.Errorf("expected nil error, got %v", m.Err)
+	}
+}
+
+func TestMetrics_ObserveRetriesAndRateLimits(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []interface{}{}})
+		}
+	}))
+	defer server.Close()
+
+	rec := &recordingCollector{}
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(3, 1*time.Millisecond),
+		intasend.WithMetricsCollector(rec),
+	)
+
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := rec.last(t)
+	if m.Retries != 2 {
+		t.Errorf("expected 2 retries, got %d", m.Retries)
+	}
+	if m.RateLimited != 1 {
+		t.Errorf("expected 1 rate-limited attempt, got %d", m.RateLimited)
+	}
+	if m.StatusCode != http.StatusOK {
+		t.Errorf("expected final status 200, got %d", m.StatusCode)
+	}
+}
+
+func TestMetrics_ObserveError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail":"Not found"}`))
+	}))
+	defer server.Close()
+
+	rec := &recordingCollector{}
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc"),
+		intasend.WithSecretKey("ISSecretKey_test_abc"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithMetricsCollector(rec),
+	)
+
+	_, _ = client.PaymentLink().Get(context.Background(), "LINK-123")
+
+	m := rec.last(t)
+	if m.Endpoint != "/paymentlinks/:id/" {
+		t.Errorf("expected /paymentlinks/:id/, got %s", m.Endpoint)
+	}
+	if !intasend.IsAPIError(m.Err) {
+		t.Errorf("expected APIError in metrics, got %v", m.Err)
+	}
+}
@@ -3,9 +3,15 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	intasend "github.com/emilio-kariuki/intasend-go"
 )
@@ -171,3 +177,313 @@ func TestPaymentLink_GetNotFound(t *testing.T) {
 		t.Error("expected IsNotFound() to be true")
 	}
 }
+
+func TestPaymentLink_CreateWithLocale(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body intasend.CreatePaymentLinkRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Locale != intasend.LocaleEnglish {
+			t.Errorf("expected locale en, got %q", body.Locale)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(intasend.PaymentLink{LinkID: "LINK-1", Locale: body.Locale})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	link, err := client.PaymentLink().Create(context.Background(), &intasend.CreatePaymentLinkRequest{
+		Title:    "Donation",
+		Currency: "KES",
+		Locale:   intasend.LocaleEnglish,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.Locale != intasend.LocaleEnglish {
+		t.Errorf("expected locale en on response, got %q", link.Locale)
+	}
+}
+
+func TestPaymentLink_CreateUnsupportedLocale(t *testing.T) {
+	client, _ := intasend.New(intasend.WithSecretKey("ISSecretKey_test_abc"))
+	_, err := client.PaymentLink().Create(context.Background(), &intasend.CreatePaymentLinkRequest{
+		Title:    "Donation",
+		Currency: "KES",
+		Locale:   "xx",
+	})
+	if !errors.Is(err, intasend.ErrUnsupportedLocale) {
+		t.Errorf("expected ErrUnsupportedLocale, got %v", err)
+	}
+}
+
+// linkServer serves LINK-1 with a title that changes on every request.
+func linkServer(t *testing.T, calls *int32, delay time.Duration) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(intasend.PaymentLink{LinkID: "LINK-1", Title: fmt.Sprintf("v%d", n)})
+	}))
+}
+
+func newCachingClient(t *testing.T, server *httptest.Server, ttl, maxStale time.Duration) *intasend.Client {
+	t.Helper()
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithPaymentLinkCache(ttl, maxStale),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestPaymentLink_GetCachedSharesRequests(t *testing.T) {
+	var calls int32
+	server := linkServer(t, &calls, 20*time.Millisecond)
+	defer server.Close()
+
+	client := newCachingClient(t, server, time.Minute, time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.PaymentLink().GetCached(context.Background(), "LINK-1"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	link, _ := client.PaymentLink().GetCached(context.Background(), "LINK-1")
+	if n := atomic.LoadInt32(&calls); n != 1 || link.Title != "v1" {
+		t.Errorf("expected one shared request, got %d calls and %q", n, link.Title)
+	}
+
+	link.Title = "mutated"
+	if again, _ := client.PaymentLink().GetCached(context.Background(), "LINK-1"); again.Title != "v1" {
+		t.Error("callers must not be able to modify the cache")
+	}
+
+	client.PaymentLink().Invalidate("LINK-1")
+	if link, _ := client.PaymentLink().GetCached(context.Background(), "LINK-1"); link.Title != "v2" {
+		t.Errorf("expected refetch after Invalidate, got %q", link.Title)
+	}
+}
+
+func TestPaymentLink_GetCachedStaleWhileRevalidate(t *testing.T) {
+	var calls int32
+	server := linkServer(t, &calls, 0)
+	defer server.Close()
+
+	client := newCachingClient(t, server, 10*time.Millisecond, time.Minute)
+	ctx := context.Background()
+	client.PaymentLink().GetCached(ctx, "LINK-1")
+	time.Sleep(20 * time.Millisecond)
+
+	link, err := client.PaymentLink().GetCached(ctx, "LINK-1")
+	if err != nil || link.Title != "v1" {
+		t.Fatalf("expected stale v1 to be served, got %v, %v", link, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if link, _ := client.PaymentLink().GetCached(ctx, "LINK-1"); link.Title == "v2" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("expected background refresh to v2")
+}
+
+func TestPaymentLink_GetCachedExpired(t *testing.T) {
+	var calls int32
+	server := linkServer(t, &calls, 0)
+	defer server.Close()
+
+	client := newCachingClient(t, server, 5*time.Millisecond, 5*time.Millisecond)
+	client.PaymentLink().GetCached(context.Background(), "LINK-1")
+	time.Sleep(15 * time.Millisecond)
+
+	link, err := client.PaymentLink().GetCached(context.Background(), "LINK-1")
+	if err != nil || link.Title != "v2" {
+		t.Errorf("expected expired entry to be refetched, got %v, %v", link, err)
+	}
+}
+
+func TestPaymentLink_GetCachedWithoutTimeout(t *testing.T) {
+	var calls int32
+	server := linkServer(t, &calls, 0)
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithTimeout(0),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if _, err := client.PaymentLink().GetCached(context.Background(), "LINK-1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPaymentLink_GetCachedPerEnvironment(t *testing.T) {
+	rt := &recordingTransport{}
+	client := newProductionClient(t, rt)
+
+	live := context.Background()
+	canary := intasend.ContextWithEnvironment(live, intasend.Sandbox, "", "ISSecretKey_test_canary")
+	for _, ctx := range []context.Context{live, canary, live, canary} {
+		if _, err := client.PaymentLink().GetCached(ctx, "LINK-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(rt.requests) != 2 {
+		t.Fatalf("expected one request per environment, got %d", len(rt.requests))
+	}
+	if got := rt.requests[1].Header.Get("Authorization"); got != "Bearer ISSecretKey_test_canary" {
+		t.Errorf("expected the canary fetch to use the sandbox key, got %q", got)
+	}
+}
+
+func TestPaymentLink_GetCachedBounded(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(intasend.PaymentLink{LinkID: path.Base(r.URL.Path)})
+	}))
+	defer server.Close()
+
+	client := newCachingClient(t, server, time.Minute, time.Minute)
+	ctx := context.Background()
+	for i := 0; i <= 1024; i++ {
+		if _, err := client.PaymentLink().GetCached(ctx, fmt.Sprintf("LINK-%d", i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	client.PaymentLink().GetCached(ctx, "LINK-1024")
+	client.PaymentLink().GetCached(ctx, "LINK-0")
+	if n := atomic.LoadInt32(&calls); n != 1026 {
+		t.Errorf("expected the oldest link to be evicted and refetched, got %d calls", n)
+	}
+}
+
+func TestPaymentLink_Send(t *testing.T) {
+	var creates int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			atomic.AddInt32(&creates, 1)
+		}
+		json.NewEncoder(w).Encode(intasend.PaymentLink{LinkID: "LNK-1", Title: "Invoice 31", URL: "https://pay.example/LNK-1"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	err := client.PaymentLink().Send(context.Background(), "LNK-1", intasend.LinkDestination{PhoneNumber: "254712345678"})
+	if !errors.Is(err, intasend.ErrNoLinkNotifier) {
+		t.Fatalf("expected ErrNoLinkNotifier, got %v", err)
+	}
+
+	var sent []string
+	fail := false
+	client, err = intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithLinkNotifier(intasend.LinkNotifierFunc(
+			func(ctx context.Context, link *intasend.PaymentLink, to intasend.LinkDestination) error {
+				if fail {
+					return errors.New("gateway down")
+				}
+				sent = append(sent, to.PhoneNumber+to.Email+" "+link.URL)
+				return nil
+			})),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := client.PaymentLink().Send(ctx, "LNK-1", intasend.LinkDestination{PhoneNumber: "254712345678"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.PaymentLink().Send(ctx, "LNK-1", intasend.LinkDestination{Name: "Jane"}); !errors.Is(err, intasend.ErrIncompleteRequest) {
+		t.Fatalf("expected ErrIncompleteRequest, got %v", err)
+	}
+
+	req := &intasend.CreatePaymentLinkRequest{Title: "Invoice 31", Currency: "KES", Amount: 100, IsActive: true}
+	link, err := client.PaymentLink().CreateAndSend(ctx, req, intasend.LinkDestination{Email: "jane@example.com"})
+	if err != nil || link.LinkID != "LNK-1" {
+		t.Fatalf("unexpected result %+v, %v", link, err)
+	}
+	want := []string{"254712345678 https://pay.example/LNK-1", "jane@example.com https://pay.example/LNK-1"}
+	if fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, sent)
+	}
+
+	fail = true
+	link, err = client.PaymentLink().CreateAndSend(ctx, req, intasend.LinkDestination{Email: "jane@example.com"})
+	if err == nil || link == nil {
+		t.Fatalf("expected created link with delivery error, got %+v, %v", link, err)
+	}
+	if n := atomic.LoadInt32(&creates); n != 2 {
+		t.Errorf("expected 2 creates, got %d", n)
+	}
+}
+
+func TestPaymentLink_PreviewAmounts(t *testing.T) {
+	var asked []string
+	rates := intasend.RateProviderFunc(func(ctx context.Context, from, to string) (intasend.Amount, error) {
+		asked = append(asked, from+"/"+to)
+		switch to {
+		case "USD":
+			return "0.0077", nil
+		case "EUR":
+			return "0.0071", nil
+		}
+		return "", errors.New("no rate")
+	})
+	client, err := intasend.New(intasend.WithSecretKey("ISSecretKey_test_secret"), intasend.WithRateProvider(rates))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	link := &intasend.PaymentLink{LinkID: "LINK-1", Currency: "KES", Amount: 5000}
+
+	previews, err := client.PaymentLink().PreviewAmounts(context.Background(), link, "usd", "EUR", "KES")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []intasend.AmountPreview{
+		{Currency: "USD", Amount: "38.50", Rate: "0.0077"},
+		{Currency: "EUR", Amount: "35.50", Rate: "0.0071"},
+		{Currency: "KES", Amount: "5000.00", Rate: "1"},
+	}
+	if fmt.Sprint(previews) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, previews)
+	}
+	if previews[0].Text() != "USD 38.50" {
+		t.Errorf("unexpected text %q", previews[0].Text())
+	}
+	if fmt.Sprint(asked) != "[KES/USD KES/EUR]" {
+		t.Errorf("expected rates only for other currencies, got %v", asked)
+	}
+
+	if _, err := client.PaymentLink().PreviewAmounts(context.Background(), link, "GBP"); err == nil {
+		t.Error("expected the provider's error")
+	}
+	open := &intasend.PaymentLink{LinkID: "LINK-2", Currency: "KES"}
+	if _, err := client.PaymentLink().PreviewAmounts(context.Background(), open, "USD"); !errors.Is(err, intasend.ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount for a link without an amount, got %v", err)
+	}
+
+	bare, _ := intasend.New(intasend.WithSecretKey("ISSecretKey_test_secret"))
+	if _, err := bare.PaymentLink().PreviewAmounts(context.Background(), link, "USD"); !errors.Is(err, intasend.ErrNoRateProvider) {
+		t.Errorf("expected ErrNoRateProvider, got %v", err)
+	}
+}
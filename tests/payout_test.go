@@ -3,9 +3,12 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	intasend "github.com/emilio-kariuki/intasend-go"
 )
@@ -134,6 +137,59 @@ func TestPayout_MPesaB2B(t *testing.T) {
 	}
 }
 
+func TestPayout_MPesaB2BBank(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body intasend.InitiateRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Provider != intasend.ProviderMPesaB2B {
+			t.Errorf("expected MPESA-B2B, got %s", body.Provider)
+		}
+		tx := body.Transactions[0]
+		if tx.Account != "247247" || tx.AccountType != string(intasend.AccountTypePayBill) || tx.AccountReference != "0170299812345" {
+			t.Errorf("expected Equity's PayBill with the normalized account, got %+v", tx)
+		}
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{TrackingID: "TRK-B2B-BANK"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	req := &intasend.MPesaB2BBankRequest{
+		Currency: "KES",
+		Transactions: []intasend.B2BBankTransaction{{
+			Name:          "Jane Wanjiku",
+			PayBill:       intasend.BankPayBillEquity,
+			AccountNumber: "0170 2998-12345",
+			Amount:        "15000",
+		}},
+	}
+	resp, err := client.Payout().MPesaB2BBank(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.TrackingID != "TRK-B2B-BANK" {
+		t.Errorf("expected TRK-B2B-BANK, got %s", resp.TrackingID)
+	}
+
+	invalid := []intasend.B2BBankTransaction{
+		{PayBill: "2472", AccountNumber: "0170299812345"},
+		{PayBill: "ABC247", AccountNumber: "0170299812345"},
+		{PayBill: intasend.BankPayBillKCB, AccountNumber: "12345"},
+		{PayBill: intasend.BankPayBillKCB, AccountNumber: "1234567890ABC"},
+		{PayBill: intasend.BankPayBillKCB},
+	}
+	for _, tx := range invalid {
+		req.Transactions = []intasend.B2BBankTransaction{tx}
+		if _, err := client.Payout().MPesaB2BBank(context.Background(), req); !errors.Is(err, intasend.ErrInvalidBankAccount) {
+			t.Errorf("%+v: expected ErrInvalidBankAccount, got %v", tx, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected invalid requests not to be sent, got %d calls", calls)
+	}
+}
+
 func TestPayout_Bank(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body intasend.InitiateRequest
@@ -295,3 +351,179 @@ func TestPayout_Status(t *testing.T) {
 		t.Errorf("expected account 254712345678, got %s", resp.Transactions[0].Account)
 	}
 }
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{1e6, "1000000"},
+		{100, "100"},
+		{12.5, "12.5"},
+		{12.345, "12.35"},
+		{0.01, "0.01"},
+		{99.999, "100"},
+		{1234567.89, "1234567.89"},
+	}
+	for _, tt := range tests {
+		got, err := intasend.FormatAmount(tt.in)
+		if err != nil {
+			t.Errorf("FormatAmount(%v) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FormatAmount(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAmount_Invalid(t *testing.T) {
+	for _, in := range []float64{0, -5, 0.001, math.NaN(), math.Inf(1)} {
+		if _, err := intasend.FormatAmount(in); !errors.Is(err, intasend.ErrInvalidAmount) {
+			t.Errorf("FormatAmount(%v) expected ErrInvalidAmount, got %v", in, err)
+		}
+	}
+}
+
+func TestValidateAmount(t *testing.T) {
+	valid := []string{"1", "100", "12.5", "0.01", "1000000.00"}
+	for _, in := range valid {
+		if err := intasend.ValidateAmount(in); err != nil {
+			t.Errorf("ValidateAmount(%q) unexpected error: %v", in, err)
+		}
+	}
+	invalid := []string{"", "0", "0.00", "-1", "1e+06", "12.345", "12.", ".5", "abc", "1,000"}
+	for _, in := range invalid {
+		if err := intasend.ValidateAmount(in); !errors.Is(err, intasend.ErrInvalidAmount) {
+			t.Errorf("ValidateAmount(%q) expected ErrInvalidAmount, got %v", in, err)
+		}
+	}
+}
+
+func TestNewTransaction(t *testing.T) {
+	txn, err := intasend.NewTransaction("254712345678", 1e6, "Salary")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txn.Amount != "1000000" || txn.Account != "254712345678" || txn.Narrative != "Salary" {
+		t.Errorf("unexpected transaction: %+v", txn)
+	}
+
+	if _, err := intasend.NewTransaction("254712345678", -1, ""); !errors.Is(err, intasend.ErrInvalidAmount) {
+		t.Errorf("expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestPayout_EnforceApproval(t *testing.T) {
+	var got []intasend.ApprovalStatus
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body intasend.InitiateRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		got = append(got, body.RequiresApproval)
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{TrackingID: "TRK-1"})
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithEnforceApproval(),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	txns := []intasend.Transaction{{Account: "254712345678", Amount: "100"}}
+	req := &intasend.MPesaRequest{Currency: "KES", Transactions: txns, RequiresApproval: intasend.ApprovalNotRequired}
+	if _, err := client.Payout().MPesa(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Payout().Airtime(context.Background(), &intasend.AirtimeRequest{Currency: "KES", Transactions: txns}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, status := range got {
+		if status != intasend.ApprovalRequired {
+			t.Errorf("request %d: expected approval to be enforced, got %q", i, status)
+		}
+	}
+	if req.RequiresApproval != intasend.ApprovalNotRequired {
+		t.Error("caller's request should not be modified")
+	}
+}
+
+func TestPayout_ApprovalNotifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{TrackingID: "TRK 1", Nonce: "n1"})
+	}))
+	defer server.Close()
+
+	var notified []*intasend.PendingApproval
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithApprovalNotifier("https://admin.example.com/payouts/{tracking_id}/approve",
+			func(ctx context.Context, p *intasend.PendingApproval) { notified = append(notified, p) }),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx := intasend.ContextWithFields(context.Background(), map[string]interface{}{"run": "payroll-03"})
+	txns := []intasend.Transaction{{Account: "254712345678", Amount: "100.10"}, {Account: "254712345679", Amount: "50.20"}}
+	if _, err := client.Payout().MPesa(ctx, &intasend.MPesaRequest{Currency: "KES", Transactions: txns}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Payout().MPesa(ctx, &intasend.MPesaRequest{Currency: "KES", Transactions: txns, RequiresApproval: intasend.ApprovalNotRequired}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(notified) != 1 {
+		t.Fatalf("expected only the batch needing approval to notify, got %d", len(notified))
+	}
+	p := notified[0]
+	if p.Link != "https://admin.example.com/payouts/TRK%201/approve" || p.Count != 2 || p.Total != 150.3 || p.Nonce != "n1" {
+		t.Errorf("unexpected notification: %+v", p)
+	}
+	if p.Provider != intasend.ProviderMPesaB2C || p.Fields["run"] != "payroll-03" {
+		t.Errorf("unexpected provider or fields: %+v", p)
+	}
+}
+
+func TestPayout_WaitForCompletion(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		status := intasend.PayoutStatusProcessing
+		if calls == 2 {
+			w.Header().Set("Retry-After", "0")
+		}
+		if calls >= 3 {
+			status = intasend.PayoutStatusCompleted
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"tracking_id": "TRK-1", "status": status})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	status, err := client.Payout().WaitForCompletion(context.Background(), "TRK-1", &intasend.PollOptions{
+		MinInterval: time.Millisecond,
+		MaxInterval: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Status != intasend.PayoutStatusCompleted || calls != 3 {
+		t.Errorf("expected Completed after 3 checks, got %s after %d", status.Status, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	if _, err := client.Payout().WaitForCompletion(ctx, "TRK-1", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
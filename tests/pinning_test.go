@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func newPinnedClient(t *testing.T, server *httptest.Server, hc *http.Client, pins ...string) (*intasend.Client, error) {
+	t.Helper()
+	return intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(hc),
+		intasend.WithRetry(2, 0),
+		intasend.WithCertificatePinning(pins...),
+	)
+}
+
+func TestCertificatePinning(t *testing.T) {
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	pin := intasend.PinFromCertificate(server.Certificate())
+	client, err := newPinnedClient(t, server, server.Client(), "sha256/Vjs8r4z+80wjNcr1YKepWQboSIRi63WsWXhIMN+eWys=", pin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Wallet().List(context.Background()); err != nil {
+		t.Fatalf("expected pinned request to succeed, got %v", err)
+	}
+
+	atomic.StoreInt32(&conns, 0)
+	client, err = newPinnedClient(t, server, server.Client(), "sha256/Vjs8r4z+80wjNcr1YKepWQboSIRi63WsWXhIMN+eWys=")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = client.Wallet().List(context.Background())
+	if !errors.Is(err, intasend.ErrCertificatePinMismatch) {
+		t.Fatalf("expected ErrCertificatePinMismatch, got %v", err)
+	}
+	if got := atomic.LoadInt32(&conns); got != 1 {
+		t.Errorf("expected a pin mismatch not to be retried, got %d connections", got)
+	}
+	if server.Client().Transport.(*http.Transport).TLSClientConfig.VerifyConnection != nil {
+		t.Error("expected the caller's transport to be left unmodified")
+	}
+}
+
+func TestCertificatePinning_IgnoresUnverifiedCertificates(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "Pinned Intermediate"}, NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinned, _ := x509.ParseCertificate(der)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[]}`))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+	// Send the pinned certificate after a leaf it did not sign.
+	server.TLS.Certificates[0].Certificate = append(server.TLS.Certificates[0].Certificate, der)
+
+	client, err := newPinnedClient(t, server, server.Client(), intasend.PinFromCertificate(pinned))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Wallet().List(context.Background()); !errors.Is(err, intasend.ErrCertificatePinMismatch) {
+		t.Fatalf("expected ErrCertificatePinMismatch, got %v", err)
+	}
+}
+
+func TestCertificatePinning_InvalidConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := newPinnedClient(t, server, server.Client(), "not-a-pin"); err == nil {
+		t.Error("expected an invalid pin to be rejected")
+	}
+	if _, err := newPinnedClient(t, server, server.Client()); err == nil {
+		t.Error("expected an empty pin set to be rejected")
+	}
+	insecure := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	if _, err := newPinnedClient(t, server, insecure, intasend.PinFromCertificate(server.Certificate())); err == nil {
+		t.Error("expected pinning over InsecureSkipVerify to be rejected")
+	}
+	custom := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) { return nil, errors.New("unused") })}
+	if _, err := newPinnedClient(t, server, custom, intasend.PinFromCertificate(server.Certificate())); err == nil {
+		t.Error("expected a non-*http.Transport client to be rejected")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
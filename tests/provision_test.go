@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/provision"
+)
+
+// provisionServer is an in-memory wallet and payment link store.
+type provisionServer struct {
+	mu      sync.Mutex
+	wallets []intasend.Wallet
+	links   []intasend.PaymentLink
+	creates int
+}
+
+func (s *provisionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/wallets/" && r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(intasend.WalletListResponse{Results: s.wallets})
+	case r.URL.Path == "/wallets/" && r.Method == http.MethodPost:
+		var req intasend.CreateWalletRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		wallet := intasend.Wallet{WalletID: "W-NEW", Label: req.Label, Currency: req.Currency, CanDisburse: req.CanDisburse}
+		s.wallets = append(s.wallets, wallet)
+		s.creates++
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(wallet)
+	case r.URL.Path == "/paymentlinks/" && r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(intasend.PaymentLinkListResponse{Results: s.links})
+	case r.URL.Path == "/paymentlinks/" && r.Method == http.MethodPost:
+		var req intasend.CreatePaymentLinkRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		link := intasend.PaymentLink{LinkID: "L-NEW", Title: req.Title, Currency: req.Currency, Amount: req.Amount, IsActive: req.IsActive}
+		s.links = append(s.links, link)
+		s.creates++
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(link)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestProvision_PlanAndApply(t *testing.T) {
+	store := &provisionServer{
+		wallets: []intasend.Wallet{
+			{WalletID: "W-1", Label: "Operations", Currency: "KES", CanDisburse: false},
+			{WalletID: "W-2", Label: "Legacy", Currency: "USD"},
+		},
+		links: []intasend.PaymentLink{
+			{LinkID: "L-1", Title: "Basic Plan", Currency: "KES", Amount: 1000, IsActive: true},
+		},
+	}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	p := provision.New(client)
+	desired := &provision.State{
+		Wallets: []provision.WalletSpec{
+			{Label: "Operations", Currency: "KES", CanDisburse: true},
+			{Label: "Savings", Currency: "KES"},
+		},
+		PaymentLinks: []provision.PaymentLinkSpec{
+			{Title: "Basic Plan", Currency: "KES", Amount: 1000, IsActive: true},
+			{Title: "Premium Plan", Currency: "KES", Amount: 5000, IsActive: true},
+		},
+	}
+
+	plan, err := p.Plan(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kinds := map[provision.ChangeKind][]string{}
+	for _, c := range plan.Changes {
+		kinds[c.Kind] = append(kinds[c.Kind], c.Key)
+	}
+	if got := strings.Join(kinds[provision.ChangeCreate], ","); got != "Savings/KES,Premium Plan" {
+		t.Errorf("unexpected creates: %s", got)
+	}
+	if got := strings.Join(kinds[provision.ChangeDrift], ","); got != "Operations/KES" {
+		t.Errorf("unexpected drift: %s", got)
+	}
+	if got := strings.Join(kinds[provision.ChangeUnmanaged], ","); got != "Legacy/USD" {
+		t.Errorf("unexpected unmanaged: %s", got)
+	}
+	if !plan.HasChanges() || !plan.Drifted() {
+		t.Error("expected plan to have changes and drift")
+	}
+	if !strings.Contains(plan.String(), `+ create payment_link "Premium Plan"`) {
+		t.Errorf("unexpected plan output:\n%s", plan)
+	}
+
+	result, err := p.Apply(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Wallets) != 1 || len(result.PaymentLinks) != 1 {
+		t.Fatalf("expected 1 wallet and 1 link created, got %d and %d", len(result.Wallets), len(result.PaymentLinks))
+	}
+
+	// Applying the same state again creates nothing.
+	plan, err = p.Plan(context.Background(), desired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Errorf("expected no creates on second plan, got:\n%s", plan)
+	}
+	if _, err := p.Apply(context.Background(), plan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.creates != 2 {
+		t.Errorf("expected 2 creates in total, got %d", store.creates)
+	}
+}
+
+func TestProvision_SandboxTagNotApplied(t *testing.T) {
+	store := &provisionServer{}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithSandboxTag("ci"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p := provision.New(client)
+	desired := &provision.State{
+		Wallets:      []provision.WalletSpec{{Label: "Operations", Currency: "KES"}},
+		PaymentLinks: []provision.PaymentLinkSpec{{Title: "Basic Plan", Currency: "KES", Amount: 1000}},
+	}
+
+	for run := 1; run <= 2; run++ {
+		plan, err := p.Plan(context.Background(), desired)
+		if err != nil {
+			t.Fatalf("run %d: unexpected error: %v", run, err)
+		}
+		if _, err := p.Apply(context.Background(), plan); err != nil {
+			t.Fatalf("run %d: unexpected error: %v", run, err)
+		}
+	}
+	if store.creates != 2 {
+		t.Errorf("expected 2 creates in total, got %d", store.creates)
+	}
+	if store.wallets[0].Label != "Operations" {
+		t.Errorf("expected untagged label, got %q", store.wallets[0].Label)
+	}
+}
+
+func TestProvision_PaginatesAndReportsConflicts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page2 := r.URL.Query().Get("page") == "2"
+		switch r.URL.Path {
+		case "/wallets/":
+			if page2 {
+				json.NewEncoder(w).Encode(intasend.WalletListResponse{Results: []intasend.Wallet{
+					{WalletID: "W-2", Label: "Operations", Currency: "KES"},
+					{WalletID: "W-3", Label: "Savings", Currency: "KES"},
+				}})
+				return
+			}
+			json.NewEncoder(w).Encode(intasend.WalletListResponse{Next: "page2", Results: []intasend.Wallet{
+				{WalletID: "W-1", Label: "Operations", Currency: "KES"},
+			}})
+		case "/paymentlinks/":
+			json.NewEncoder(w).Encode(intasend.PaymentLinkListResponse{Results: []intasend.PaymentLink{
+				{LinkID: "L-1", Title: "Basic Plan", Currency: "KES"},
+				{LinkID: "L-2", Title: "Basic Plan", Currency: "KES"},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	plan, err := provision.New(newTestClient(t, server)).Plan(context.Background(), &provision.State{
+		Wallets: []provision.WalletSpec{
+			{Label: "Operations", Currency: "KES"},
+			{Label: "Savings", Currency: "KES"},
+		},
+		PaymentLinks: []provision.PaymentLinkSpec{{Title: "Basic Plan", Currency: "KES"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.HasChanges() || !plan.Conflicted() {
+		t.Errorf("expected conflicts and no creates, got:\n%s", plan)
+	}
+	var conflicts []string
+	for _, c := range plan.Changes {
+		if c.Kind == provision.ChangeConflict {
+			conflicts = append(conflicts, c.Key+"="+strings.Join(c.IDs, "+"))
+		}
+	}
+	if got := strings.Join(conflicts, ","); got != "Operations/KES=W-1+W-2,Basic Plan=L-1+L-2" {
+		t.Errorf("unexpected conflicts: %s", got)
+	}
+}
+
+func TestProvision_DecodeJSON(t *testing.T) {
+	var state provision.State
+	err := json.Unmarshal([]byte(`{
+		"wallets": [{"label": "Operations", "currency": "KES", "can_disburse": true}],
+		"payment_links": [{"title": "Premium", "currency": "KES", "amount": 5000, "is_active": true}]
+	}`), &state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Wallets) != 1 || !state.Wallets[0].CanDisburse {
+		t.Errorf("unexpected wallets: %+v", state.Wallets)
+	}
+	if len(state.PaymentLinks) != 1 || state.PaymentLinks[0].Amount != 5000 {
+		t.Errorf("unexpected payment links: %+v", state.PaymentLinks)
+	}
+}
+
+func TestProvision_DuplicateSpec(t *testing.T) {
+	server := httptest.NewServer(&provisionServer{})
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := provision.New(client).Plan(context.Background(), &provision.State{
+		Wallets: []provision.WalletSpec{
+			{Label: "Operations", Currency: "KES"},
+			{Label: "Operations", Currency: "kes"},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "duplicate wallet spec") {
+		t.Errorf("expected duplicate wallet spec error, got %v", err)
+	}
+}
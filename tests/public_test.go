@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestNewPublic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "" {
+			t.Errorf("expected no Authorization header, got %q", got)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["public_key"] != "ISPubKey_test_abc" {
+			t.Errorf("expected the publishable key in the body, got %v", body["public_key"])
+		}
+		switch r.URL.Path {
+		case "/checkout/":
+			json.NewEncoder(w).Encode(intasend.CreateCheckoutResponse{ID: "CHK-1", URL: "https://pay/1"})
+		case "/payment/status/":
+			json.NewEncoder(w).Encode(intasend.StatusResponse{Invoice: &intasend.Invoice{InvoiceID: "INV-1", State: intasend.StatePending}})
+		}
+	}))
+	defer server.Close()
+
+	pub, err := intasend.NewPublic("ISPubKey_test_abc", intasend.WithBaseURL(server.URL), intasend.WithRetry(0, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := pub.Collection().Charge(ctx, &intasend.ChargeRequest{Email: "a@example.com", Host: "https://shop.example.com", Amount: 100, Currency: "KES"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkout, err := pub.Checkout().Create(ctx, &intasend.CreateCheckoutRequest{Amount: 100, Currency: "KES", Customer: intasend.CheckoutCustomer{Email: "a@example.com"}})
+	if err != nil || checkout.ID != "CHK-1" {
+		t.Fatalf("unexpected checkout %+v, %v", checkout, err)
+	}
+	status, err := pub.Collection().Status(ctx, "INV-1", nil)
+	if err != nil || status.Invoice.State != intasend.StatePending {
+		t.Fatalf("unexpected status %+v, %v", status, err)
+	}
+
+	if _, err := intasend.NewPublic(""); !errors.Is(err, intasend.ErrMissingPublishableKey) {
+		t.Errorf("expected ErrMissingPublishableKey, got %v", err)
+	}
+	if _, err := intasend.NewPublic("ISPubKey_test_abc", intasend.WithSecretKey("ISSecretKey_test_abc")); !errors.Is(err, intasend.ErrSecretKeyNotAllowed) {
+		t.Errorf("expected ErrSecretKeyNotAllowed, got %v", err)
+	}
+
+	// Spare capacity in the caller's slice must not be written to.
+	opts := []intasend.Option{intasend.WithRetry(0, 0), nil}
+	if _, err := intasend.NewPublic("ISPubKey_test_abc", opts[:1]...); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts[1] != nil {
+		t.Error("expected NewPublic to leave the caller's options untouched")
+	}
+}
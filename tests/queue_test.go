@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// collectOutcomes gathers queue outcomes from worker goroutines.
+type collectOutcomes struct {
+	mu       sync.Mutex
+	outcomes []intasend.CollectOutcome
+}
+
+func (c *collectOutcomes) record(o intasend.CollectOutcome) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outcomes = append(c.outcomes, o)
+}
+
+func stkOK(w http.ResponseWriter) {
+	json.NewEncoder(w).Encode(intasend.STKPushResponse{Invoice: &intasend.Invoice{InvoiceID: "INV-1", State: intasend.StatePending}})
+}
+
+func TestCollectQueue_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		stkOK(w)
+	}))
+	defer server.Close()
+
+	var got collectOutcomes
+	client := newTestClient(t, server)
+	queue := client.Collection().NewQueue(context.Background(), &intasend.CollectQueueOptions{
+		Backoff:       10 * time.Millisecond,
+		PhoneCooldown: time.Millisecond,
+		OnOutcome:     got.record,
+	})
+	if err := queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 100}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	queue.Close()
+
+	if len(got.outcomes) != 1 {
+		t.Fatalf("expected 1 outcome, got %d", len(got.outcomes))
+	}
+	o := got.outcomes[0]
+	if o.Err != nil || o.Attempts != 2 || o.Response.Invoice.InvoiceID != "INV-1" {
+		t.Errorf("unexpected outcome: %+v", o)
+	}
+}
+
+func TestCollectQueue_PermanentFailure(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"detail": "Invalid phone number"})
+	}))
+	defer server.Close()
+
+	var got collectOutcomes
+	client := newTestClient(t, server)
+	queue := client.Collection().NewQueue(context.Background(), &intasend.CollectQueueOptions{
+		Backoff:   time.Millisecond,
+		OnOutcome: got.record,
+	})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254700000000", Amount: 100})
+	queue.Close()
+
+	if calls != 1 {
+		t.Errorf("expected no retries for 400, got %d calls", calls)
+	}
+	if apiErr := intasend.AsAPIError(got.outcomes[0].Err); apiErr == nil || apiErr.HTTPStatusCode != 400 {
+		t.Errorf("expected 400 APIError, got %v", got.outcomes[0].Err)
+	}
+}
+
+func TestCollectQueue_NoRetryAfterRequestMayHaveBeenProcessed(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// The response is lost after IntaSend sent the prompt.
+		time.Sleep(100 * time.Millisecond)
+		stkOK(w)
+	}))
+	defer server.Close()
+
+	var got collectOutcomes
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithTimeout(20*time.Millisecond),
+	)
+	queue := client.Collection().NewQueue(context.Background(), &intasend.CollectQueueOptions{
+		Backoff:       time.Millisecond,
+		PhoneCooldown: time.Millisecond,
+		OnOutcome:     got.record,
+	})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 100})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345679", Amount: 100})
+	queue.Close()
+
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("expected one push per request, got %d", n)
+	}
+	for _, o := range got.outcomes {
+		if o.Err == nil || o.Attempts != 1 {
+			t.Errorf("expected a single failed attempt, got %+v", o)
+		}
+	}
+}
+
+func TestCollectQueue_RetriesConnectionFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var got collectOutcomes
+	client, _ := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(url),
+		intasend.WithRetry(0, 0),
+	)
+	queue := client.Collection().NewQueue(context.Background(), &intasend.CollectQueueOptions{
+		MaxAttempts:   2,
+		Backoff:       time.Millisecond,
+		PhoneCooldown: time.Millisecond,
+		OnOutcome:     got.record,
+	})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 100})
+	queue.Close()
+
+	if o := got.outcomes[0]; o.Attempts != 2 || !intasend.IsNetworkError(o.Err) {
+		t.Errorf("expected 2 attempts ending in a network error, got %+v", o)
+	}
+}
+
+func TestCollectQueue_PhoneCooldown(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		stkOK(w)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	cooldown := 100 * time.Millisecond
+	queue := client.Collection().NewQueue(context.Background(), &intasend.CollectQueueOptions{
+		PhoneCooldown: cooldown,
+	})
+	// Both numbers refer to the same phone.
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "0712345678", Amount: 100})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "+254 712 345 678", Amount: 200})
+	queue.Close()
+
+	if len(times) != 2 {
+		t.Fatalf("expected 2 pushes, got %d", len(times))
+	}
+	if gap := times[1].Sub(times[0]); gap < cooldown-5*time.Millisecond {
+		t.Errorf("expected pushes at least %v apart, got %v", cooldown, gap)
+	}
+}
+
+func TestCollectQueue_ContextCancelAbandons(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got collectOutcomes
+	client := newTestClient(t, server)
+	queue := client.Collection().NewQueue(ctx, &intasend.CollectQueueOptions{
+		Backoff:   time.Hour,
+		OnOutcome: got.record,
+	})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 100})
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	queue.Close()
+
+	o := got.outcomes[0]
+	if !errors.Is(o.Err, context.Canceled) || intasend.AsAPIError(o.Err) == nil {
+		t.Errorf("expected cancellation joined with last API error, got %v", o.Err)
+	}
+	if err := queue.Enqueue(&intasend.STKPushRequest{}); !errors.Is(err, intasend.ErrQueueClosed) {
+		t.Errorf("expected ErrQueueClosed, got %v", err)
+	}
+}
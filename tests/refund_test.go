@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -150,3 +151,220 @@ func TestRefund_GetNotFound(t *testing.T) {
 		t.Error("expected IsNotFound() to be true")
 	}
 }
+
+// refundServer serves an invoice status and a chargeback list, and records
+// whether a chargeback was created.
+func refundServer(t *testing.T, invoiceValue float64, chargebacks []intasend.Chargeback, created *bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/payment/status/":
+			json.NewEncoder(w).Encode(intasend.StatusResponse{
+				Invoice: &intasend.Invoice{InvoiceID: "INV-100", State: intasend.StateComplete, Value: invoiceValue},
+			})
+		case r.URL.Path == "/chargebacks/" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(intasend.ChargebackListResponse{Results: chargebacks})
+		case r.URL.Path == "/chargebacks/" && r.Method == http.MethodPost:
+			*created = true
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(intasend.Chargeback{ChargebackID: "CHG-NEW", Invoice: "INV-100"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+func TestRefund_Refundable(t *testing.T) {
+	var created bool
+	server := refundServer(t, 1000, []intasend.Chargeback{
+		{ChargebackID: "CHG-1", Invoice: "INV-100", Amount: 300, Status: intasend.ChargebackStatusComplete},
+		{ChargebackID: "CHG-2", Invoice: "INV-100", Amount: 200, Status: intasend.ChargebackStatusRejected},
+		{ChargebackID: "CHG-3", Invoice: "INV-200", Amount: 900, Status: intasend.ChargebackStatusComplete},
+		{ChargebackID: "CHG-4", Invoice: "INV-100", Amount: 100, Status: intasend.ChargebackStatusPending},
+	}, &created)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	resp, err := client.Refund().Refundable(context.Background(), "INV-100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.InvoiceValue != 1000 {
+		t.Errorf("expected invoice value 1000, got %v", resp.InvoiceValue)
+	}
+	if resp.Refunded != 400 {
+		t.Errorf("expected refunded 400 (rejected excluded), got %v", resp.Refunded)
+	}
+	if resp.Remaining != 600 {
+		t.Errorf("expected remaining 600, got %v", resp.Remaining)
+	}
+}
+
+func TestRefund_RefundablePaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/payment/status/":
+			json.NewEncoder(w).Encode(intasend.StatusResponse{
+				Invoice: &intasend.Invoice{InvoiceID: "INV-100", State: intasend.StateComplete, Value: 1000},
+			})
+		case r.URL.Query().Get("page") == "2":
+			json.NewEncoder(w).Encode(intasend.ChargebackListResponse{Results: []intasend.Chargeback{
+				{ChargebackID: "CHG-2", Invoice: "INV-100", Amount: 250, Status: intasend.ChargebackStatusComplete},
+			}})
+		default:
+			json.NewEncoder(w).Encode(intasend.ChargebackListResponse{Next: "page2", Results: []intasend.Chargeback{
+				{ChargebackID: "CHG-1", Invoice: "INV-100", Amount: 300, Status: intasend.ChargebackStatusComplete},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	resp, err := client.Refund().Refundable(context.Background(), "INV-100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Refunded != 550 || resp.Remaining != 450 {
+		t.Errorf("expected chargebacks on every page to count, got refunded %v and remaining %v", resp.Refunded, resp.Remaining)
+	}
+}
+
+func TestRefund_RefundableRequiresPaidInvoice(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/payment/status/" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(intasend.StatusResponse{
+			Invoice: &intasend.Invoice{InvoiceID: "INV-100", State: intasend.StateFailed, Value: 1000},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	if _, err := client.Refund().Refundable(context.Background(), "INV-100"); !errors.Is(err, intasend.ErrNotPaid) {
+		t.Errorf("expected ErrNotPaid, got %v", err)
+	}
+}
+
+func TestRefund_RefundableExact(t *testing.T) {
+	var created bool
+	server := refundServer(t, 100, []intasend.Chargeback{
+		{ChargebackID: "CHG-1", Invoice: "INV-100", Amount: 33.33, Status: intasend.ChargebackStatusComplete},
+		{ChargebackID: "CHG-2", Invoice: "INV-100", Amount: 33.33, Status: intasend.ChargebackStatusComplete},
+		{ChargebackID: "CHG-3", Invoice: "INV-100", Amount: 33.33, Status: intasend.ChargebackStatusPending},
+	}, &created)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	resp, err := client.Refund().Refundable(context.Background(), "INV-100")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Refunded != 99.99 || resp.Remaining != 0.01 {
+		t.Errorf("expected refunded 99.99 and remaining 0.01 exactly, got %v and %v", resp.Refunded, resp.Remaining)
+	}
+
+	_, err = client.Refund().CreateValidated(context.Background(), &intasend.CreateChargebackRequest{
+		Invoice:       "INV-100",
+		AmountDecimal: "0.01",
+		Reason:        intasend.RefundReasonCustomerRequest,
+	})
+	if err != nil || !created {
+		t.Errorf("expected the last cent to be refundable, got %v", err)
+	}
+}
+
+func TestRefund_CreateValidatedExceeds(t *testing.T) {
+	var created bool
+	server := refundServer(t, 500, []intasend.Chargeback{
+		{ChargebackID: "CHG-1", Invoice: "INV-100", Amount: 400, Status: intasend.ChargebackStatusComplete},
+	}, &created)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	_, err := client.Refund().CreateValidated(context.Background(), &intasend.CreateChargebackRequest{
+		Invoice: "INV-100",
+		Amount:  200,
+		Reason:  intasend.RefundReasonCustomerRequest,
+	})
+	if !errors.Is(err, intasend.ErrRefundExceedsBalance) {
+		t.Fatalf("expected ErrRefundExceedsBalance, got %v", err)
+	}
+	if created {
+		t.Error("chargeback should not be created when amount exceeds refundable")
+	}
+}
+
+func TestRefund_CreateValidatedWithinBalance(t *testing.T) {
+	var created bool
+	server := refundServer(t, 500, nil, &created)
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	resp, err := client.Refund().CreateValidated(context.Background(), &intasend.CreateChargebackRequest{
+		Invoice: "INV-100",
+		Amount:  500,
+		Reason:  intasend.RefundReasonCustomerRequest,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created || resp.ChargebackID != "CHG-NEW" {
+		t.Errorf("expected chargeback to be created, got %+v", resp)
+	}
+}
+
+func TestRefund_CreateValidatedNonPositive(t *testing.T) {
+	client, _ := intasend.New(intasend.WithSecretKey("ISSecretKey_test_abc"))
+	_, err := client.Refund().CreateValidated(context.Background(), &intasend.CreateChargebackRequest{
+		Invoice: "INV-100",
+		Amount:  0,
+	})
+	if !errors.Is(err, intasend.ErrInvalidRefundAmount) {
+		t.Errorf("expected ErrInvalidRefundAmount, got %v", err)
+	}
+}
+
+func TestRefundReasonForDispute(t *testing.T) {
+	tests := []struct {
+		category intasend.DisputeCategory
+		want     intasend.RefundReason
+		details  bool
+	}{
+		{intasend.DisputeDuplicateCharge, intasend.RefundReasonDuplicatePayment, false},
+		{intasend.DisputeUnauthorized, intasend.RefundReasonFraudulent, false},
+		{intasend.DisputeNotDelivered, intasend.RefundReasonServiceUnavailable, false},
+		{intasend.DisputeCancelled, intasend.RefundReasonCustomerRequest, false},
+		{intasend.DisputeNotAsDescribed, intasend.RefundReasonOther, true},
+		{intasend.DisputeOther, intasend.RefundReasonOther, true},
+		{"chargeback_from_bank", intasend.RefundReasonOther, true},
+	}
+	for _, tt := range tests {
+		guide := intasend.RefundReasonForDispute(tt.category)
+		if guide.Reason != tt.want || guide.RequiresDetails != tt.details {
+			t.Errorf("%s: got %s (details %v), want %s (details %v)",
+				tt.category, guide.Reason, guide.RequiresDetails, tt.want, tt.details)
+		}
+		if guide.Guidance == "" {
+			t.Errorf("%s: expected guidance text", tt.category)
+		}
+	}
+}
+
+func TestRefund_CreateOtherRequiresDetails(t *testing.T) {
+	client, _ := intasend.New(intasend.WithSecretKey("ISSecretKey_test_abc"))
+	for _, fn := range []func(context.Context, *intasend.CreateChargebackRequest, ...intasend.RequestOption) (*intasend.Chargeback, error){
+		client.Refund().Create,
+		client.Refund().CreateValidated,
+	} {
+		_, err := fn(context.Background(), &intasend.CreateChargebackRequest{
+			Invoice:       "INV-100",
+			Amount:        100,
+			Reason:        intasend.RefundReasonOther,
+			ReasonDetails: "  ",
+		})
+		if !errors.Is(err, intasend.ErrReasonDetailsRequired) {
+			t.Errorf("expected ErrReasonDetailsRequired, got %v", err)
+		}
+	}
+}
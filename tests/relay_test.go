@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/emilio-kariuki/intasend-go/webhook"
+)
+
+var relaySecret = []byte("relay-secret")
+
+func newTestRelay(deadLetters *[]webhook.Delivery) *webhook.Relay {
+	var mu sync.Mutex
+	return webhook.NewRelay(webhook.RelayOptions{
+		Secret:      relaySecret,
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+		OnDeadLetter: func(d webhook.Delivery, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			*deadLetters = append(*deadLetters, d)
+		},
+	})
+}
+
+func TestRelay_ForwardSignedAfterRetry(t *testing.T) {
+	var calls int32
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ids = append(ids, r.Header.Get(webhook.DeliveryHeader))
+		if err := webhook.VerifySignature(relaySecret, r.Header.Get(webhook.SignatureHeader), body, 0); err != nil {
+			t.Errorf("signature did not verify: %v", err)
+		}
+		if strings.Contains(string(body), "challenge") {
+			t.Errorf("challenge must not be forwarded: %s", body)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	event, err := webhook.Parse([]byte(`{"invoice_id":"INV-1","state":"COMPLETE","challenge":"secret"}`), "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var dead []webhook.Delivery
+	if err := newTestRelay(&dead).Forward(context.Background(), server.URL, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(dead) != 0 {
+		t.Errorf("expected 2 attempts and no dead letters, got %d and %d", calls, len(dead))
+	}
+	if ids[0] != event.ID() || ids[1] != event.ID() {
+		t.Errorf("expected stable delivery ID %s, got %v", event.ID(), ids)
+	}
+}
+
+func TestRelay_DeadLetter(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantCalls int32
+	}{
+		{"exhausted", http.StatusInternalServerError, 3},
+		{"rejected", http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			var dead []webhook.Delivery
+			err := newTestRelay(&dead).Deliver(context.Background(), server.URL, []byte(`{"ok":true}`))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("expected %d attempts, got %d", tt.wantCalls, calls)
+			}
+			if len(dead) != 1 || dead[0].Attempts != int(tt.wantCalls) || dead[0].URL != server.URL {
+				t.Errorf("unexpected dead letters: %+v", dead)
+			}
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"a":1}`)
+	header := webhook.Sign(relaySecret, time.Now(), body)
+	if err := webhook.VerifySignature(relaySecret, header, body, 0); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := webhook.VerifySignature([]byte("other"), header, body, 0); !errors.Is(err, webhook.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+	old := webhook.Sign(relaySecret, time.Now().Add(-time.Hour), body)
+	if err := webhook.VerifySignature(relaySecret, old, body, time.Minute); !errors.Is(err, webhook.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for stale signature, got %v", err)
+	}
+}
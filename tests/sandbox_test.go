@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestSandbox_TagsCreatedResources(t *testing.T) {
+	var label, title string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wallets/":
+			var req intasend.CreateWalletRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			label = req.Label
+			json.NewEncoder(w).Encode(intasend.Wallet{WalletID: "W-1", Label: req.Label})
+		case "/paymentlinks/":
+			var req intasend.CreatePaymentLinkRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			title = req.Title
+			json.NewEncoder(w).Encode(intasend.PaymentLink{LinkID: "L-1", Title: req.Title})
+		}
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithSandboxTag("ci"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := &intasend.CreateWalletRequest{Currency: "KES", Label: "Ops"}
+	if _, err := client.Wallet().Create(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if label != "[ci] Ops" {
+		t.Errorf("expected tagged label, got %q", label)
+	}
+	if req.Label != "Ops" {
+		t.Errorf("request was modified: %q", req.Label)
+	}
+
+	if _, err := client.PaymentLink().Create(context.Background(), &intasend.CreatePaymentLinkRequest{Title: "[ci] Plan"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if title != "[ci] Plan" {
+		t.Errorf("expected title to be tagged once, got %q", title)
+	}
+}
+
+func TestSandbox_LiveKeysNotTagged(t *testing.T) {
+	var label string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req intasend.CreateWalletRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		label = req.Label
+		json.NewEncoder(w).Encode(intasend.Wallet{WalletID: "W-1"})
+	}))
+	defer server.Close()
+
+	client, _ := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_live_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithSandboxTag("ci"),
+	)
+	if _, err := client.Wallet().Create(context.Background(), &intasend.CreateWalletRequest{Label: "Ops"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if label != "Ops" {
+		t.Errorf("expected live wallet to be untagged, got %q", label)
+	}
+	if _, err := client.Sandbox().Leftovers(context.Background(), nil); !errors.Is(err, intasend.ErrNotSandbox) {
+		t.Errorf("expected ErrNotSandbox, got %v", err)
+	}
+}
+
+func TestSandbox_Leftovers(t *testing.T) {
+	cutoff := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	old, recent := cutoff.Add(-time.Hour), cutoff.Add(time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wallets/":
+			if r.URL.Query().Get("page") == "2" {
+				json.NewEncoder(w).Encode(intasend.WalletListResponse{Results: []intasend.Wallet{
+					{WalletID: "W-1", Label: "[ci] run 1", UpdatedAt: old},
+				}})
+				return
+			}
+			json.NewEncoder(w).Encode(intasend.WalletListResponse{Next: "page2", Results: []intasend.Wallet{
+				{WalletID: "W-2", Label: "[ci] run 2", UpdatedAt: recent},
+				{WalletID: "W-3", Label: "Operations", UpdatedAt: old},
+			}})
+		case "/paymentlinks/":
+			json.NewEncoder(w).Encode(intasend.PaymentLinkListResponse{Results: []intasend.PaymentLink{
+				{LinkID: "L-1", Title: "[ci] plan", UpdatedAt: old},
+				{LinkID: "L-2", Title: "[other] plan", UpdatedAt: old},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	if _, err := client.Sandbox().Leftovers(context.Background(), nil); !errors.Is(err, intasend.ErrNoSandboxTag) {
+		t.Errorf("expected ErrNoSandboxTag, got %v", err)
+	}
+
+	left, err := client.Sandbox().Leftovers(context.Background(), &intasend.LeftoverOptions{Tag: "ci", Before: cutoff})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(left.Wallets) != 1 || left.Wallets[0].WalletID != "W-1" {
+		t.Errorf("unexpected wallets: %+v", left.Wallets)
+	}
+	if len(left.PaymentLinks) != 1 || left.PaymentLinks[0].LinkID != "L-1" {
+		t.Errorf("unexpected payment links: %+v", left.PaymentLinks)
+	}
+}
@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// sessionServer serves checkout creation and answers status checks with
+// the invoice state returned by state for the n-th check.
+func sessionServer(t *testing.T, state func(n int32) string) (*httptest.Server, *int32) {
+	t.Helper()
+	var checks int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/checkout/":
+			json.NewEncoder(w).Encode(intasend.CreateCheckoutResponse{ID: "CHK-1", URL: "https://pay.example/CHK-1", Signature: "sig"})
+		case "/payment/status/":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["checkout_id"] != "CHK-1" || body["signature"] != "sig" {
+				t.Errorf("unexpected status request %v", body)
+			}
+			n := atomic.AddInt32(&checks, 1)
+			resp := intasend.CheckoutStatusResponse{}
+			if s := state(n); s != "" {
+				resp.Invoice = &intasend.Invoice{InvoiceID: "INV-1", State: s, APIRef: "order-1"}
+			}
+			json.NewEncoder(w).Encode(resp)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	return server, &checks
+}
+
+func sessionRequest() *intasend.CreateCheckoutRequest {
+	return &intasend.CreateCheckoutRequest{
+		Amount:   500,
+		Currency: "KES",
+		Customer: intasend.CheckoutCustomer{Email: "jane@example.com"},
+		Host:     "https://shop.example.com",
+		APIRef:   "order-1",
+	}
+}
+
+func TestCheckout_CreateAndWaitPaid(t *testing.T) {
+	server, checks := sessionServer(t, func(n int32) string {
+		if n < 2 {
+			return ""
+		}
+		return intasend.StateComplete
+	})
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	var created string
+	result, err := client.Checkout().CreateAndWait(context.Background(), sessionRequest(),
+		&intasend.PollOptions{Interval: 5 * time.Millisecond, Timeout: time.Second},
+		func(c *intasend.CreateCheckoutResponse) { created = c.URL })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Outcome != intasend.PaymentSessionPaid || result.Invoice.InvoiceID != "INV-1" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if created != "https://pay.example/CHK-1" || atomic.LoadInt32(checks) != 2 {
+		t.Errorf("unexpected callback URL %q or %d checks", created, atomic.LoadInt32(checks))
+	}
+}
+
+func TestCheckout_CreateAndWaitExpired(t *testing.T) {
+	server, checks := sessionServer(t, func(int32) string { return intasend.StatePending })
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	result, err := client.Checkout().CreateAndWait(context.Background(), sessionRequest(),
+		&intasend.PollOptions{Interval: time.Hour, Timeout: 20 * time.Millisecond}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Outcome != intasend.PaymentSessionExpired {
+		t.Errorf("expected expired, got %+v", result)
+	}
+	if atomic.LoadInt32(checks) != 1 {
+		t.Errorf("expected one final status check at the deadline, got %d", atomic.LoadInt32(checks))
+	}
+}
+
+func TestCheckout_CreateAndWaitNotify(t *testing.T) {
+	server, _ := sessionServer(t, func(int32) string { return intasend.StatePending })
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	go func() {
+		for !client.Checkout().Notify(&intasend.Invoice{InvoiceID: "INV-1", APIRef: "order-1", State: intasend.StateComplete}) {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	result, err := client.Checkout().CreateAndWait(context.Background(), sessionRequest(),
+		&intasend.PollOptions{Interval: time.Hour, Timeout: 5 * time.Second}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Outcome != intasend.PaymentSessionPaid {
+		t.Errorf("expected paid from webhook update, got %+v", result)
+	}
+	if client.Checkout().Notify(&intasend.Invoice{APIRef: "order-1"}) {
+		t.Error("expected no session to be waiting after CreateAndWait returned")
+	}
+}
+
+func TestCheckout_CreateAndWaitRetryAfterFailure(t *testing.T) {
+	server, checks := sessionServer(t, func(n int32) string {
+		if n < 3 {
+			return intasend.StateFailed
+		}
+		return intasend.StateComplete
+	})
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	result, err := client.Checkout().CreateAndWait(context.Background(), sessionRequest(),
+		&intasend.PollOptions{Interval: 5 * time.Millisecond, Timeout: time.Second}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Outcome != intasend.PaymentSessionPaid {
+		t.Errorf("expected paid after a failed attempt, got %+v", result)
+	}
+	if atomic.LoadInt32(checks) != 3 {
+		t.Errorf("expected 3 checks, got %d", atomic.LoadInt32(checks))
+	}
+}
+
+func TestCheckout_CreateAndWaitFailedAtDeadline(t *testing.T) {
+	server, checks := sessionServer(t, func(int32) string { return intasend.StateFailed })
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	result, err := client.Checkout().CreateAndWait(context.Background(), sessionRequest(),
+		&intasend.PollOptions{Interval: 5 * time.Millisecond, Timeout: 50 * time.Millisecond}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Outcome != intasend.PaymentSessionFailed {
+		t.Errorf("expected failed at the deadline, got %+v", result)
+	}
+	if atomic.LoadInt32(checks) < 2 {
+		t.Errorf("expected polling to continue after a failed attempt, got %d checks", atomic.LoadInt32(checks))
+	}
+}
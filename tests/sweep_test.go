@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/sweep"
+)
+
+// sweepServer serves a wallet with a fixed balance and records payouts.
+type sweepServer struct {
+	mu        sync.Mutex
+	balance   float64
+	initiated []intasend.InitiateRequest
+	approved  []intasend.ApproveRequest
+}
+
+func (s *sweepServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/wallets/W-1/":
+		json.NewEncoder(w).Encode(intasend.Wallet{WalletID: "W-1", Currency: "KES", AvailableBalance: s.balance})
+	case "/send-money/initiate/":
+		var req intasend.InitiateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		s.initiated = append(s.initiated, req)
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{TrackingID: "TRK-1", Nonce: "n1", WalletID: "W-1"})
+	case "/send-money/approve/":
+		var req intasend.ApproveRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		s.approved = append(s.approved, req)
+		json.NewEncoder(w).Encode(intasend.ApproveResponse{TrackingID: req.TrackingID})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func sweepConfig() sweep.Config {
+	return sweep.Config{
+		WalletID: "W-1",
+		Floor:    1000,
+		Destination: sweep.Destination{
+			Provider: intasend.ProviderMPesaB2C,
+			Account:  "254712345678",
+		},
+	}
+}
+
+func TestSweep_PaysOutAboveFloor(t *testing.T) {
+	store := &sweepServer{balance: 3500.756}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	var stages []sweep.Stage
+	cfg := sweepConfig()
+	cfg.Audit = func(ctx context.Context, e sweep.Event) { stages = append(stages, e.Stage) }
+
+	s, err := sweep.New(newTestClient(t, server), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Skipped || result.Plan.Amount != 2500.75 {
+		t.Errorf("expected 2500.75 swept, got %+v", result.Plan)
+	}
+	if len(store.initiated) != 1 {
+		t.Fatalf("expected 1 payout, got %d", len(store.initiated))
+	}
+	req := store.initiated[0]
+	if req.Provider != intasend.ProviderMPesaB2C || req.WalletID != "W-1" || req.RequiresApproval != intasend.ApprovalNotRequired {
+		t.Errorf("unexpected payout request: %+v", req)
+	}
+	if txn := req.Transactions[0]; txn.Amount != "2500.75" || txn.Account != "254712345678" || txn.Narrative != "Settlement sweep" {
+		t.Errorf("unexpected transaction: %+v", txn)
+	}
+	if len(stages) != 1 || stages[0] != sweep.StageInitiated {
+		t.Errorf("expected [initiated] audit, got %v", stages)
+	}
+}
+
+func TestSweep_SkipsBelowMinimum(t *testing.T) {
+	store := &sweepServer{balance: 1050}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	cfg := sweepConfig()
+	cfg.MinAmount = 100
+	s, _ := sweep.New(newTestClient(t, server), cfg)
+	result, err := s.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Skipped || len(store.initiated) != 0 {
+		t.Errorf("expected sweep to be skipped, got %+v with %d payouts", result, len(store.initiated))
+	}
+}
+
+func TestSweep_Approval(t *testing.T) {
+	tests := []struct {
+		name     string
+		approve  bool
+		wantAppr int
+		stage    sweep.Stage
+	}{
+		{"approved", true, 1, sweep.StageApproved},
+		{"declined", false, 0, sweep.StageDeclined},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &sweepServer{balance: 2000}
+			server := httptest.NewServer(store)
+			defer server.Close()
+
+			var last sweep.Event
+			cfg := sweepConfig()
+			cfg.RequireApproval = true
+			cfg.Approver = func(ctx context.Context, p sweep.Plan) (bool, error) { return tt.approve, nil }
+			cfg.Audit = func(ctx context.Context, e sweep.Event) { last = e }
+
+			s, _ := sweep.New(newTestClient(t, server), cfg)
+			result, err := s.Run(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if store.initiated[0].RequiresApproval != intasend.ApprovalRequired {
+				t.Error("expected payout to require approval")
+			}
+			if len(store.approved) != tt.wantAppr || result.Approved != tt.approve {
+				t.Errorf("expected %d approvals, got %d (approved=%v)", tt.wantAppr, len(store.approved), result.Approved)
+			}
+			if last.Stage != tt.stage || last.TrackingID != "TRK-1" {
+				t.Errorf("expected %s audit for TRK-1, got %+v", tt.stage, last)
+			}
+		})
+	}
+}
+
+func TestSweep_ApproverError(t *testing.T) {
+	store := &sweepServer{balance: 2000}
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	boom := errors.New("approval service down")
+	cfg := sweepConfig()
+	cfg.RequireApproval = true
+	cfg.Approver = func(ctx context.Context, p sweep.Plan) (bool, error) { return false, boom }
+
+	s, _ := sweep.New(newTestClient(t, server), cfg)
+	if _, err := s.Run(context.Background()); !errors.Is(err, boom) {
+		t.Errorf("expected approver error, got %v", err)
+	}
+	if len(store.approved) != 0 {
+		t.Error("payout should not be approved")
+	}
+}
+
+func TestSweep_InvalidConfig(t *testing.T) {
+	cfg := sweepConfig()
+	cfg.Destination = sweep.Destination{Provider: intasend.ProviderPesaLink, Account: "0123456789"}
+	if _, err := sweep.New(nil, cfg); err == nil {
+		t.Error("expected error for PesaLink destination without bank code")
+	}
+}
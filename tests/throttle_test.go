@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func newThrottledClient(t *testing.T, server *httptest.Server, interval time.Duration) *intasend.Client {
+	t.Helper()
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithRecipientThrottle(interval),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestThrottle_STKPushSamePhone(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		stkOK(w)
+	}))
+	defer server.Close()
+
+	client := newThrottledClient(t, server, time.Minute)
+	ctx := context.Background()
+	if _, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{PhoneNumber: "0712345678", Amount: 10})
+	if !errors.Is(err, intasend.ErrRecipientThrottled) {
+		t.Fatalf("expected ErrRecipientThrottled, got %v", err)
+	}
+	var throttled *intasend.ThrottledError
+	if !errors.As(err, &throttled) || throttled.Recipient != "254712345678" || throttled.RetryAfter <= 0 {
+		t.Errorf("unexpected throttle error: %+v", throttled)
+	}
+
+	// Wallet funding prompts the same phone.
+	_, err = client.Wallet().FundMPesa(ctx, &intasend.FundMPesaRequest{WalletID: "W1", PhoneNumber: "254712345678", Amount: 10})
+	if !errors.Is(err, intasend.ErrRecipientThrottled) {
+		t.Errorf("expected FundMPesa to be throttled, got %v", err)
+	}
+
+	// Other phones are unaffected.
+	if _, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{PhoneNumber: "254799999999", Amount: 10}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 requests, got %d", calls)
+	}
+}
+
+func TestThrottle_ReleasedOnClientError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"detail": "Invalid amount"})
+			return
+		}
+		stkOK(w)
+	}))
+	defer server.Close()
+
+	client := newThrottledClient(t, server, time.Minute)
+	ctx := context.Background()
+	req := &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10}
+	if _, err := client.Collection().MPesaSTKPush(ctx, req); intasend.AsAPIError(err) == nil {
+		t.Fatalf("expected API error, got %v", err)
+	}
+	if _, err := client.Collection().MPesaSTKPush(ctx, req); err != nil {
+		t.Errorf("expected retry after rejected request to succeed, got %v", err)
+	}
+}
+
+func TestThrottle_ReleasedWhenNotSent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stkOK(w)
+	}))
+	defer server.Close()
+
+	client := newThrottledClient(t, server, time.Minute)
+	req := &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10}
+
+	// The context ends before the request is dispatched.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Collection().MPesaSTKPush(ctx, req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := client.Collection().MPesaSTKPush(context.Background(), req); err != nil {
+		t.Fatalf("expected push after an undispatched request to succeed, got %v", err)
+	}
+
+	// The connection cannot be made.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	client = newThrottledClient(t, closed, time.Minute)
+	if _, err := client.Collection().MPesaSTKPush(context.Background(), req); !intasend.IsNetworkError(err) {
+		t.Fatalf("expected network error, got %v", err)
+	}
+	if _, err := client.Collection().MPesaSTKPush(context.Background(), req); errors.Is(err, intasend.ErrRecipientThrottled) {
+		t.Errorf("expected the failed dial to release the recipient, got %v", err)
+	}
+}
+
+func TestThrottle_KeptWhenCancelledAfterAttempt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := intasend.New(
+		intasend.WithPublishableKey("ISPubKey_test_abc123"),
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(3, time.Second),
+		intasend.WithRecipientThrottle(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// The context ends while waiting to retry a request the server saw.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := &intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10}
+	_, err = client.Collection().MPesaSTKPush(ctx, req)
+	if !errors.Is(err, context.DeadlineExceeded) || intasend.AsAPIError(err) == nil {
+		t.Fatalf("expected deadline error with the last API error, got %v", err)
+	}
+	if _, err := client.Collection().MPesaSTKPush(context.Background(), req); !errors.Is(err, intasend.ErrRecipientThrottled) {
+		t.Errorf("expected the recipient to stay throttled, got %v", err)
+	}
+}
+
+func TestThrottle_AirtimeAndExpiry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{TrackingID: "TRK-1"})
+	}))
+	defer server.Close()
+
+	client := newThrottledClient(t, server, 50*time.Millisecond)
+	ctx := context.Background()
+	airtime := &intasend.AirtimeRequest{
+		Currency:     "KES",
+		Transactions: []intasend.Transaction{{Account: "254712345678", Amount: "100"}},
+	}
+	if _, err := client.Payout().Airtime(ctx, airtime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Payout().Airtime(ctx, airtime); !errors.Is(err, intasend.ErrRecipientThrottled) {
+		t.Fatalf("expected throttled airtime, got %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Payout().Airtime(ctx, airtime); err != nil {
+		t.Errorf("expected airtime after interval to succeed, got %v", err)
+	}
+}
+
+func TestThrottle_CollectQueueWaits(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		stkOK(w)
+	}))
+	defer server.Close()
+
+	client := newThrottledClient(t, server, 50*time.Millisecond)
+	var got collectOutcomes
+	queue := client.Collection().NewQueue(context.Background(), &intasend.CollectQueueOptions{
+		PhoneCooldown: time.Millisecond,
+		OnOutcome:     got.record,
+	})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 10})
+	queue.Enqueue(&intasend.STKPushRequest{PhoneNumber: "254712345678", Amount: 20})
+	queue.Close()
+
+	for _, o := range got.outcomes {
+		if o.Err != nil || o.Attempts != 1 {
+			t.Errorf("expected throttled push to be delayed, not failed: %+v", o)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 pushes, got %d", calls)
+	}
+}
@@ -0,0 +1,261 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/webhook"
+)
+
+func newTrackingClient(t *testing.T, server *httptest.Server, store intasend.TrackingStore) *intasend.Client {
+	t.Helper()
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithHTTPClient(server.Client()),
+		intasend.WithRetry(0, 0),
+		intasend.WithTrackingStore(store),
+		intasend.WithPayoutCallbackURL("https://example.com/hooks/payouts"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestTracking_CorrelateWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req intasend.InitiateRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.CallbackURL != "https://example.com/hooks/payouts" {
+			t.Errorf("expected default callback URL, got %q", req.CallbackURL)
+		}
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{
+			TrackingID: "TRK-1",
+			Status:     "Preview and approve",
+			Transactions: []intasend.TransactionResult{
+				{RequestRefID: "REF-A", Account: "254711111111", Amount: "100"},
+				{RequestRefID: "REF-B", Account: "254722222222", Amount: "250"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	store := intasend.NewMemoryTrackingStore()
+	client := newTrackingClient(t, server, store)
+
+	ctx := intasend.ContextWithFields(context.Background(), map[string]interface{}{"payroll_run": "2024-03"})
+	_, err := client.Payout().MPesa(ctx, &intasend.MPesaRequest{
+		Currency: "KES",
+		Transactions: []intasend.Transaction{
+			{Account: "254711111111", Amount: "100", Narrative: "Alice"},
+			{Account: "254722222222", Amount: "250", Narrative: "Bob"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	event, err := webhook.Parse([]byte(`{
+		"tracking_id": "TRK-1",
+		"status": "Completed",
+		"transactions": [
+			{"request_ref_id": "REF-B", "account": "254722222222", "amount": "250.00", "status": "Successful"},
+			{"request_ref_id": "REF-A", "account": "254711111111", "amount": "100.00", "status": "Failed"}
+		]
+	}`), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update, err := event.Payout()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	corr, err := client.Payout().Correlate(context.Background(), update)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if corr.Status != "Completed" || corr.Batch.Fields["payroll_run"] != "2024-03" {
+		t.Errorf("unexpected correlation: %+v", corr)
+	}
+	if len(corr.Transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(corr.Transactions))
+	}
+	if tx := corr.Transactions[0]; tx.Index != 1 || tx.Original.Narrative != "Bob" || tx.Update.Status != "Successful" {
+		t.Errorf("unexpected first transaction: %+v", tx)
+	}
+	if tx := corr.Transactions[1]; tx.Index != 0 || tx.Original.Narrative != "Alice" {
+		t.Errorf("unexpected second transaction: %+v", tx)
+	}
+}
+
+func TestTracking_FallbackMatchByAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{TrackingID: "TRK-2"})
+	}))
+	defer server.Close()
+
+	client := newTrackingClient(t, server, intasend.NewMemoryTrackingStore())
+	_, err := client.Payout().Airtime(context.Background(), &intasend.AirtimeRequest{
+		Currency:     "KES",
+		Transactions: []intasend.Transaction{{Account: "254711111111", Amount: "50"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	corr, err := client.Payout().Correlate(context.Background(), &intasend.PayoutStatusResponse{
+		TrackingID: "TRK-2",
+		Transactions: []intasend.TransactionResult{
+			{RequestRefID: "REF-X", Account: "254711111111", Amount: "50.00"},
+			{RequestRefID: "REF-Y", Account: "254799999999", Amount: "10"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if corr.Transactions[0].Index != 0 {
+		t.Errorf("expected match by account and amount, got %+v", corr.Transactions[0])
+	}
+	if corr.Transactions[1].Index != -1 || corr.Transactions[1].Original != nil {
+		t.Errorf("expected unmatched transaction, got %+v", corr.Transactions[1])
+	}
+}
+
+func TestTracking_Errors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client := newTrackingClient(t, server, intasend.NewMemoryTrackingStore())
+	_, err := client.Payout().Correlate(context.Background(), &intasend.PayoutStatusResponse{TrackingID: "UNKNOWN"})
+	if !errors.Is(err, intasend.ErrNotTracked) {
+		t.Errorf("expected ErrNotTracked, got %v", err)
+	}
+
+	plain := newTestClient(t, server)
+	_, err = plain.Payout().Correlate(context.Background(), &intasend.PayoutStatusResponse{TrackingID: "TRK-1"})
+	if !errors.Is(err, intasend.ErrNoTrackingStore) {
+		t.Errorf("expected ErrNoTrackingStore, got %v", err)
+	}
+}
+
+func TestTracking_Tokenizer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{
+			TrackingID:   "TRK-3",
+			Transactions: []intasend.TransactionResult{{Name: "Alice", Account: "254711111111", Amount: "50"}},
+		})
+	}))
+	defer server.Close()
+
+	store := intasend.NewMemoryTrackingStore()
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithTrackingStore(store),
+		intasend.WithTokenizer(intasend.NewHMACTokenizer([]byte("key"))),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	_, err = client.Payout().MPesa(context.Background(), &intasend.MPesaRequest{
+		Currency:     "KES",
+		Transactions: []intasend.Transaction{{Name: "Alice", Account: "254711111111", Amount: "50"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	batch, err := store.LoadPayout(context.Background(), "TRK-3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored := batch.Request.Transactions[0]
+	if stored.Account == "254711111111" || stored.Name == "Alice" || batch.Response.Transactions[0].Account == "254711111111" {
+		t.Errorf("expected personal data to be tokenized, got %+v", batch)
+	}
+
+	corr, err := client.Payout().Correlate(context.Background(), &intasend.PayoutStatusResponse{
+		TrackingID:   "TRK-3",
+		Transactions: []intasend.TransactionResult{{Account: "254711111111", Amount: "50"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if corr.Transactions[0].Index != 0 {
+		t.Errorf("expected match by tokenized account, got %+v", corr.Transactions[0])
+	}
+}
+
+func TestTokenizeJSON(t *testing.T) {
+	tok := intasend.TokenizerFunc(func(ctx context.Context, kind intasend.PIIKind, value string) (string, error) {
+		return "<" + string(kind) + ">", nil
+	})
+	body := []byte(`{"email":"a@b.c","amount":100.50,"customer":{"phone_number":"254712345678"},"transactions":[{"account":"0123","name":"Bob"}],"narrative":"x"}`)
+	got, err := intasend.TokenizeJSON(context.Background(), tok, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"amount":100.50,"customer":{"phone_number":"<phone_number>"},"email":"<email>","narrative":"x","transactions":[{"account":"<account>","name":"<name>"}]}`
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	failing := intasend.TokenizerFunc(func(context.Context, intasend.PIIKind, string) (string, error) {
+		return "", errors.New("vault down")
+	})
+	if _, err := intasend.TokenizeJSON(context.Background(), failing, body); err == nil {
+		t.Error("expected tokenizer error")
+	}
+}
+
+// failingTrackingStore is a TrackingStore whose saves always fail.
+type failingTrackingStore struct {
+	intasend.TrackingStore
+}
+
+func (failingTrackingStore) SavePayout(ctx context.Context, payout *intasend.TrackedPayout) error {
+	return errors.New("store unavailable")
+}
+
+func TestTracking_SaveFailureDeadLettered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(intasend.InitiateResponse{TrackingID: "TRK-9"})
+	}))
+	defer server.Close()
+
+	var letters []intasend.DeadLetter
+	client, err := intasend.New(
+		intasend.WithSecretKey("ISSecretKey_test_secret"),
+		intasend.WithBaseURL(server.URL),
+		intasend.WithRetry(0, 0),
+		intasend.WithTrackingStore(failingTrackingStore{}),
+		intasend.WithDeadLetterHandler(intasend.DeadLetterFunc(func(d intasend.DeadLetter) {
+			letters = append(letters, d)
+		})),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	resp, err := client.Payout().MPesa(context.Background(), &intasend.MPesaRequest{
+		Currency:     "KES",
+		Transactions: []intasend.Transaction{{Account: "254711111111", Amount: "100"}},
+	})
+	if err != nil || resp.TrackingID != "TRK-9" {
+		t.Fatalf("expected the payout to succeed despite the store, got %+v, %v", resp, err)
+	}
+	if len(letters) != 1 || letters[0].Source != intasend.DeadLetterPayoutTracking {
+		t.Fatalf("expected one payout tracking dead letter, got %+v", letters)
+	}
+	if record, ok := letters[0].Item.(*intasend.TrackedPayout); !ok || record.TrackingID != "TRK-9" {
+		t.Errorf("expected the TrackedPayout as the item, got %#v", letters[0].Item)
+	}
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+func TestValidation_RejectedBeforeSending(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		call   func() error
+		fields map[string]error
+	}{
+		{
+			name: "STK push",
+			call: func() error {
+				_, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{PhoneNumber: "0812345678", Amount: -5})
+				return err
+			},
+			fields: map[string]error{"phone_number": intasend.ErrInvalidPhoneNumber, "amount": intasend.ErrInvalidAmount},
+		},
+		{
+			name: "STK push missing phone",
+			call: func() error {
+				_, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{Amount: 100})
+				return err
+			},
+			fields: map[string]error{"phone_number": intasend.ErrIncompleteRequest},
+		},
+		{
+			name: "charge",
+			call: func() error {
+				_, err := client.Collection().Charge(ctx, &intasend.ChargeRequest{Email: "a@example.com", Host: "https://example.com", AmountDecimal: "0", Currency: "kes"})
+				return err
+			},
+			fields: map[string]error{"amount": intasend.ErrInvalidAmount, "currency": intasend.ErrInvalidCurrency},
+		},
+		{
+			name: "checkout",
+			call: func() error {
+				_, err := client.Checkout().Create(ctx, &intasend.CreateCheckoutRequest{AmountDecimal: "10.005", Currency: "kes", Host: "https://example.com"})
+				return err
+			},
+			fields: map[string]error{"amount": intasend.ErrInvalidAmount, "currency": intasend.ErrInvalidCurrency},
+		},
+		{
+			name: "payout",
+			call: func() error {
+				_, err := client.Payout().MPesa(ctx, &intasend.MPesaRequest{
+					Currency: "KES",
+					Transactions: []intasend.Transaction{
+						{Account: "254712345678", Amount: "100"},
+						{Account: "12345", Amount: "1.005"},
+					},
+				})
+				return err
+			},
+			fields: map[string]error{"transactions[1].account": intasend.ErrInvalidPhoneNumber, "transactions[1].amount": intasend.ErrInvalidAmount},
+		},
+		{
+			name: "payout without transactions",
+			call: func() error {
+				_, err := client.Payout().Initiate(ctx, &intasend.InitiateRequest{Provider: intasend.ProviderPesaLink})
+				return err
+			},
+			fields: map[string]error{"currency": intasend.ErrIncompleteRequest, "transactions": intasend.ErrIncompleteRequest},
+		},
+		{
+			name: "STK push with too many decimals",
+			call: func() error {
+				_, err := client.Collection().MPesaSTKPush(ctx, &intasend.STKPushRequest{PhoneNumber: "254712345678", AmountDecimal: "100.005"})
+				return err
+			},
+			fields: map[string]error{"amount": intasend.ErrInvalidAmount},
+		},
+		{
+			name: "intra-wallet transfer with too many decimals",
+			call: func() error {
+				_, err := client.Wallet().IntraTransfer(ctx, &intasend.IntraTransferRequest{SourceID: "W-1", DestinationID: "W-2", AmountDecimal: "10.999"})
+				return err
+			},
+			fields: map[string]error{"amount": intasend.ErrInvalidAmount},
+		},
+		{
+			name: "refund with too many decimals",
+			call: func() error {
+				_, err := client.Refund().Create(ctx, &intasend.CreateChargebackRequest{Invoice: "INV-1", AmountDecimal: "1.001", Reason: intasend.RefundReasonCustomerRequest})
+				return err
+			},
+			fields: map[string]error{"amount": intasend.ErrInvalidAmount},
+		},
+		{
+			name: "wallet funding",
+			call: func() error {
+				_, err := client.Wallet().FundMPesa(ctx, &intasend.FundMPesaRequest{PhoneNumber: "254712345678", Amount: 100})
+				return err
+			},
+			fields: map[string]error{"wallet_id": intasend.ErrIncompleteRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			verr := intasend.AsValidationError(err)
+			if verr == nil {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if len(verr.Fields) != len(tt.fields) {
+				t.Errorf("got fields %+v, want %d", verr.Fields, len(tt.fields))
+			}
+			for field, sentinel := range tt.fields {
+				f := verr.Field(field)
+				if f == nil {
+					t.Errorf("missing field error for %s in %v", field, err)
+					continue
+				}
+				if !errors.Is(f, sentinel) || !errors.Is(err, sentinel) {
+					t.Errorf("%s: expected errors.Is %v", field, sentinel)
+				}
+				if !strings.Contains(err.Error(), field) {
+					t.Errorf("error %q does not name %s", err, field)
+				}
+			}
+		})
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("expected no requests, got %d", n)
+	}
+}
+
+func TestValidation_AcceptsPhoneFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"invoice":{"invoice_id":"INV-1"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	for _, phone := range []string{"254712345678", "254112345678", "0712345678", "+254 712 345 678"} {
+		_, err := client.Collection().MPesaSTKPush(context.Background(), &intasend.STKPushRequest{PhoneNumber: phone, Amount: 10})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", phone, err)
+		}
+	}
+	for _, phone := range []string{"25471234567", "2547123456789", "254812345678", "254-7l2-345-678"} {
+		_, err := client.Collection().MPesaSTKPush(context.Background(), &intasend.STKPushRequest{PhoneNumber: phone, Amount: 10})
+		if !errors.Is(err, intasend.ErrInvalidPhoneNumber) {
+			t.Errorf("%q: expected ErrInvalidPhoneNumber, got %v", phone, err)
+		}
+	}
+}
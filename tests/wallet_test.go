@@ -3,9 +3,13 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	intasend "github.com/emilio-kariuki/intasend-go"
 )
@@ -297,3 +301,456 @@ func TestWallet_FundCheckout(t *testing.T) {
 		t.Errorf("expected CHK-FUND, got %s", resp.ID)
 	}
 }
+
+func TestWallet_ListTransactionsQuery(t *testing.T) {
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wallets/W-001/transactions/" {
+			t.Errorf("expected /wallets/W-001/transactions/, got %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("updated_since"); got != "2024-01-02T03:04:05Z" {
+			t.Errorf("expected updated_since 2024-01-02T03:04:05Z, got %q", got)
+		}
+		if got := r.URL.Query().Get("page"); got != "2" {
+			t.Errorf("expected page 2, got %q", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{
+			Count:   3,
+			Results: []intasend.WalletTransaction{{TransactionID: "T-3"}},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	resp, err := client.Wallet().ListTransactions(context.Background(), "W-001", &intasend.WalletTransactionListOptions{
+		Since: since,
+		Page:  2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Count != 3 || len(resp.Results) != 1 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestWallet_StreamTransactions(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var results []intasend.WalletTransaction
+		switch atomic.AddInt32(&polls, 1) {
+		case 1:
+			results = []intasend.WalletTransaction{
+				{TransactionID: "T-2", CreatedAt: base.Add(2 * time.Second)},
+				{TransactionID: "T-1", CreatedAt: base.Add(time.Second)},
+			}
+		default:
+			// The boundary transaction is returned again and must be deduplicated.
+			results = []intasend.WalletTransaction{
+				{TransactionID: "T-2", CreatedAt: base.Add(2 * time.Second)},
+				{TransactionID: "T-3", CreatedAt: base.Add(3 * time.Second)},
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{Results: results})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := client.Wallet().StreamTransactions(ctx, "W-001", base, &intasend.StreamOptions{Interval: time.Millisecond})
+
+	var got []string
+	for txn := range stream.C {
+		got = append(got, txn.TransactionID)
+		if len(got) == 3 {
+			cancel()
+		}
+	}
+
+	want := []string{"T-1", "T-2", "T-3"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			break
+		}
+	}
+	if stream.Err() != nil {
+		t.Errorf("expected nil error after cancellation, got %v", stream.Err())
+	}
+	if !stream.Cursor().Equal(base.Add(3 * time.Second)) {
+		t.Errorf("expected cursor at T-3, got %v", stream.Cursor())
+	}
+}
+
+func TestWallet_StreamTransactionsUpdates(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server ignores updated_since and always returns every
+		// transaction; T-1 is updated after the first poll.
+		results := []intasend.WalletTransaction{
+			{TransactionID: "T-0", CreatedAt: base.Add(-time.Second)},
+			{TransactionID: "T-1", CreatedAt: base.Add(time.Second)},
+			{TransactionID: "T-2", CreatedAt: base.Add(2 * time.Second)},
+		}
+		if atomic.AddInt32(&polls, 1) > 1 {
+			results[1].UpdatedAt = base.Add(5 * time.Second)
+		}
+		json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{Results: results})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := client.Wallet().StreamTransactions(ctx, "W-001", base, &intasend.StreamOptions{Interval: time.Millisecond})
+
+	var got []string
+	for txn := range stream.C {
+		got = append(got, txn.TransactionID)
+		if len(got) == 3 {
+			// Let a few more polls run to catch redeliveries.
+			time.AfterFunc(20*time.Millisecond, cancel)
+		}
+	}
+
+	if strings.Join(got, ",") != "T-1,T-2,T-1" {
+		t.Errorf("expected T-1,T-2,T-1, got %v", got)
+	}
+	if !stream.Cursor().Equal(base.Add(5 * time.Second)) {
+		t.Errorf("expected cursor at the T-1 update, got %v", stream.Cursor())
+	}
+}
+
+func TestWallet_StreamTransactionsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"detail": "Invalid token"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	stream := client.Wallet().StreamTransactions(context.Background(), "W-001", time.Time{}, nil)
+	for range stream.C {
+		t.Error("expected no transactions")
+	}
+	if !intasend.IsAPIError(stream.Err()) {
+		t.Errorf("expected APIError, got %v", stream.Err())
+	}
+}
+
+func TestWallet_Summary(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2024, 3, day, 12, 0, 0, 0, time.UTC) }
+	pages := map[string]intasend.WalletTransactionsResponse{
+		"1": {Next: "page2", Results: []intasend.WalletTransaction{
+			{TransactionID: "T0", TransType: "SALE", Amount: 999, CreatedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+			{TransactionID: "T1", TransType: "SALE", Amount: 1000, CreatedAt: at(2)},
+			{TransactionID: "T2", TransType: "CHARGE", Amount: -30, CreatedAt: at(2)},
+			{TransactionID: "T3", TransType: "PAYOUT", Amount: -400, CreatedAt: at(5)},
+		}},
+		"2": {Results: []intasend.WalletTransaction{
+			{TransactionID: "T4", TransType: "SALE", Amount: 2500, CreatedAt: at(10)},
+			{TransactionID: "T5", TransType: "PAYOUT", Amount: -100, CreatedAt: at(11)},
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wallets/W1/transactions/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("updated_since"); got != "2024-03-01T00:00:00Z" {
+			t.Errorf("expected updated_since at period start, got %q", got)
+		}
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("page")])
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	period := intasend.MonthOf(at(15))
+	summary, err := client.Wallet().Summary(context.Background(), "W1", period)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Count != 5 {
+		t.Errorf("expected 5 transactions in March, got %d", summary.Count)
+	}
+	if summary.TotalIn != 3500 || summary.TotalOut != 500 || summary.Fees != 30 || summary.Net != 2970 {
+		t.Errorf("unexpected totals: in=%v out=%v fees=%v net=%v", summary.TotalIn, summary.TotalOut, summary.Fees, summary.Net)
+	}
+	if len(summary.LargestIn) != 2 || summary.LargestIn[0].TransactionID != "T4" {
+		t.Errorf("unexpected largest in: %+v", summary.LargestIn)
+	}
+	if len(summary.LargestOut) != 2 || summary.LargestOut[0].TransactionID != "T3" {
+		t.Errorf("unexpected largest out: %+v", summary.LargestOut)
+	}
+}
+
+func TestWallet_SearchTransactions(t *testing.T) {
+	at := func(day int) time.Time { return time.Date(2024, 3, day, 12, 0, 0, 0, time.UTC) }
+	pages := map[string]intasend.WalletTransactionsResponse{
+		"1": {Next: "page2", Results: []intasend.WalletTransaction{
+			{TransactionID: "T1", TransType: "PAYOUT", Narrative: "Refund to Jane Wanjiku", CreatedAt: at(2)},
+			{TransactionID: "T2", TransType: "SALE", Narrative: "Payment from Jane", CreatedAt: at(3)},
+		}},
+		"2": {Results: []intasend.WalletTransaction{
+			{TransactionID: "T3", TransType: "PAYOUT", Narrative: "REFUND order 42 - jane", CreatedAt: at(10)},
+			{TransactionID: "T4", TransType: "PAYOUT", Narrative: "Refund to John", CreatedAt: at(11)},
+			{TransactionID: "T5", TransType: "PAYOUT", Narrative: "Refund to Jane", CreatedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("page")])
+	}))
+	defer server.Close()
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	txns, err := client.Wallet().SearchTransactions(ctx, "W1", "  jane  Refund", &intasend.TransactionSearchOptions{
+		Period: intasend.MonthOf(at(1)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txns) != 2 || txns[0].TransactionID != "T1" || txns[1].TransactionID != "T3" {
+		t.Errorf("expected T1 and T3, got %+v", txns)
+	}
+
+	txns, err = client.Wallet().SearchTransactions(ctx, "W1", "jane", &intasend.TransactionSearchOptions{
+		TransTypes: []intasend.TransType{intasend.TransTypeSale},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txns) != 1 || txns[0].TransactionID != "T2" {
+		t.Errorf("expected T2, got %+v", txns)
+	}
+
+	if _, err := client.Wallet().SearchTransactions(ctx, "W1", " ", nil); !errors.Is(err, intasend.ErrIncompleteRequest) {
+		t.Errorf("expected ErrIncompleteRequest for an empty query, got %v", err)
+	}
+}
+
+func TestWallet_SyncTransactions(t *testing.T) {
+	t1 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+	var since []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since = append(since, r.URL.Query().Get("updated_since"))
+		json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{
+			Results: []intasend.WalletTransaction{
+				{TransactionID: "T-2", CreatedAt: t2},
+				{TransactionID: "T-1", CreatedAt: t1, UpdatedAt: t3},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	first, err := client.Wallet().SyncTransactions(context.Background(), "W-001", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Transactions) != 2 || first.Transactions[0].TransactionID != "T-1" {
+		t.Fatalf("expected transactions oldest first, got %+v", first.Transactions)
+	}
+	if since[0] != "" {
+		t.Errorf("expected full sync without updated_since, got %q", since[0])
+	}
+
+	if _, err := client.Wallet().SyncTransactions(context.Background(), "W-001", first.Token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if since[1] != t3.Format(time.RFC3339Nano) {
+		t.Errorf("expected updated_since at the latest update %s, got %q", t3.Format(time.RFC3339Nano), since[1])
+	}
+}
+
+func TestWallet_SyncTransactionsFiltersSince(t *testing.T) {
+	t1 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Minute)
+	t3 := t2.Add(time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server ignores updated_since and always returns every
+		// transaction.
+		json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{
+			Results: []intasend.WalletTransaction{
+				{TransactionID: "T-1", CreatedAt: t1},
+				{TransactionID: "T-2", CreatedAt: t1, UpdatedAt: t3},
+				{TransactionID: "T-3", CreatedAt: t2},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	first, err := client.Wallet().SyncTransactions(context.Background(), "W-001", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Transactions) != 3 {
+		t.Fatalf("expected a full first sync, got %+v", first.Transactions)
+	}
+
+	next, err := client.Wallet().SyncTransactions(context.Background(), "W-001", first.Token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.Transactions) != 1 || next.Transactions[0].TransactionID != "T-2" {
+		t.Errorf("expected only T-2 updated at the token's time, got %+v", next.Transactions)
+	}
+}
+
+func TestWallet_IntraTransferBatch(t *testing.T) {
+	var transfers int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			WalletID string  `json:"wallet_id"`
+			Amount   float64 `json:"amount"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		atomic.AddInt32(&transfers, 1)
+		if body.WalletID == "BAD" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"detail": "unknown wallet"})
+			return
+		}
+		json.NewEncoder(w).Encode(intasend.IntraTransferResponse{Status: "COMPLETE", TargetID: body.WalletID, Amount: body.Amount})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	results, err := client.Wallet().IntraTransferBatch(context.Background(), []intasend.IntraTransferRequest{
+		{SourceID: "MAIN", DestinationID: "M-1", Amount: 100},
+		{SourceID: "MAIN", DestinationID: "BAD", Amount: 50},
+		{SourceID: "MAIN", DestinationID: "M-3", Amount: 25},
+	}, &intasend.IntraTransferBatchOptions{BatchOptions: intasend.BatchOptions{Concurrency: 2}})
+
+	var batchErr *intasend.BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors["1:MAIN->BAD"] == nil {
+		t.Fatalf("expected one failure for item 1, got %v", err)
+	}
+	if len(results) != 3 || results[0].Response.TargetID != "M-1" || results[2].Response.TargetID != "M-3" || results[1].Err == nil {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if n := atomic.LoadInt32(&transfers); n != 3 {
+		t.Errorf("expected 3 transfers, got %d", n)
+	}
+}
+
+func TestWallet_IntraTransferBatchCheckBalances(t *testing.T) {
+	var transfers int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(intasend.Wallet{WalletID: "MAIN", AvailableBalance: 100.2})
+			return
+		}
+		atomic.AddInt32(&transfers, 1)
+		json.NewEncoder(w).Encode(intasend.IntraTransferResponse{Status: "COMPLETE"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	opts := &intasend.IntraTransferBatchOptions{CheckBalances: true}
+	_, err := client.Wallet().IntraTransferBatch(context.Background(), []intasend.IntraTransferRequest{
+		{SourceID: "MAIN", DestinationID: "M-1", Amount: 100.1},
+		{SourceID: "MAIN", DestinationID: "M-2", Amount: 0.2},
+	}, opts)
+	if !errors.Is(err, intasend.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+	if n := atomic.LoadInt32(&transfers); n != 0 {
+		t.Errorf("expected no transfers, got %d", n)
+	}
+
+	_, err = client.Wallet().IntraTransferBatch(context.Background(), []intasend.IntraTransferRequest{
+		{SourceID: "MAIN", DestinationID: "M-1", AmountDecimal: "100.11"},
+		{SourceID: "MAIN", DestinationID: "M-2", AmountDecimal: "0.10"},
+	}, opts)
+	if !errors.Is(err, intasend.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance for exact amounts, got %v", err)
+	}
+	if n := atomic.LoadInt32(&transfers); n != 0 {
+		t.Errorf("expected no transfers, got %d", n)
+	}
+
+	if _, err := client.Wallet().IntraTransferBatch(context.Background(), []intasend.IntraTransferRequest{
+		{SourceID: "MAIN", DestinationID: "M-1", Amount: 100.1},
+		{SourceID: "MAIN", DestinationID: "M-2", Amount: 0.1},
+	}, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(&transfers); n != 2 {
+		t.Errorf("expected 2 transfers, got %d", n)
+	}
+}
+
+func TestWallet_SyncTransactionsOrdering(t *testing.T) {
+	t1 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("ordering"); got != "created_at" {
+			t.Errorf("expected ordering=created_at, got %q", got)
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{
+				Next:    "next",
+				Results: []intasend.WalletTransaction{{TransactionID: "T-B", CreatedAt: t1}, {TransactionID: "T-C", CreatedAt: t1.Add(time.Second)}},
+			})
+		default:
+			// T-C is served again, as if the page boundary shifted.
+			json.NewEncoder(w).Encode(intasend.WalletTransactionsResponse{
+				Results: []intasend.WalletTransaction{{TransactionID: "T-C", CreatedAt: t1.Add(time.Second)}, {TransactionID: "T-A", CreatedAt: t1}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server)
+	sync, err := client.Wallet().SyncTransactions(context.Background(), "W-001", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, txn := range sync.Transactions {
+		ids = append(ids, txn.TransactionID)
+	}
+	if strings.Join(ids, ",") != "T-A,T-B,T-C" {
+		t.Errorf("expected T-A,T-B,T-C, got %v", ids)
+	}
+}
+
+func TestWalletTransaction_Direction(t *testing.T) {
+	tests := []struct {
+		tx        intasend.WalletTransaction
+		direction intasend.Direction
+		signed    float64
+	}{
+		{intasend.WalletTransaction{TransType: intasend.TransTypeSale, Amount: 1000}, intasend.DirectionIn, 1000},
+		{intasend.WalletTransaction{TransType: "credit", Amount: -50}, intasend.DirectionIn, 50},
+		{intasend.WalletTransaction{TransType: intasend.TransTypePayout, Amount: 400}, intasend.DirectionOut, -400},
+		{intasend.WalletTransaction{TransType: intasend.TransTypeCharge, Amount: -30}, intasend.DirectionOut, -30},
+		{intasend.WalletTransaction{TransType: "AIRTIME", Amount: -20}, intasend.DirectionOut, -20},
+		{intasend.WalletTransaction{TransType: "", Amount: 0}, intasend.DirectionUnknown, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.tx.Direction(); got != tt.direction {
+			t.Errorf("%s %v: expected direction %q, got %q", tt.tx.TransType, tt.tx.Amount, tt.direction, got)
+		}
+		if got := tt.tx.SignedAmount(); got != tt.signed {
+			t.Errorf("%s %v: expected signed amount %v, got %v", tt.tx.TransType, tt.tx.Amount, tt.signed, got)
+		}
+	}
+	if !intasend.TransType("fees").IsFee() || intasend.TransTypePayout.IsFee() {
+		t.Error("unexpected IsFee result")
+	}
+}
@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/webhook"
+)
+
+func TestWebhook_ParseInvoice(t *testing.T) {
+	body := `{
+		"invoice_id": "INV-1",
+		"state": "COMPLETE",
+		"provider": "M-PESA",
+		"value": "1500.50",
+		"account": "254712345678",
+		"api_ref": "order-1",
+		"created_at": "2024-01-01T10:00:00.123456+03:00",
+		"updated_at": "2024-01-01T10:01:00.123456+03:00",
+		"challenge": "secret"
+	}`
+	event, err := webhook.Parse([]byte(body), "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Type != webhook.EventInvoice || event.ResourceID != "INV-1" || event.State != intasend.StateComplete {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	inv, err := event.Invoice()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inv.Value != 1500.50 || inv.APIRef != "order-1" || inv.UpdatedAt.IsZero() {
+		t.Errorf("unexpected invoice: %+v", inv)
+	}
+	if _, err := event.Payout(); err == nil {
+		t.Error("expected error decoding invoice event as payout")
+	}
+}
+
+func TestWebhook_ParseClassifies(t *testing.T) {
+	tests := []struct {
+		body string
+		want webhook.EventType
+		id   string
+	}{
+		{`{"tracking_id": "TRK-1", "status": "Completed", "transactions": []}`, webhook.EventPayout, "TRK-1"},
+		{`{"chargeback_id": "CHG-1", "invoice": "INV-1", "amount": 10, "status": "COMPLETE"}`, webhook.EventChargeback, "CHG-1"},
+		{`{"something": "else"}`, webhook.EventUnknown, ""},
+	}
+	for _, tt := range tests {
+		event, err := webhook.Parse([]byte(tt.body), "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if event.Type != tt.want || event.ResourceID != tt.id {
+			t.Errorf("expected %s %q, got %s %q", tt.want, tt.id, event.Type, event.ResourceID)
+		}
+	}
+}
+
+func TestWebhook_ChallengeAndStatusCode(t *testing.T) {
+	_, err := webhook.Parse([]byte(`{"invoice_id": "INV-1", "challenge": "wrong"}`), "secret")
+	if !errors.Is(err, webhook.ErrInvalidChallenge) {
+		t.Fatalf("expected ErrInvalidChallenge, got %v", err)
+	}
+	if webhook.StatusCode(err) != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", webhook.StatusCode(err))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not json"))
+	_, err = webhook.ParseRequest(req, "secret")
+	if !errors.Is(err, webhook.ErrInvalidPayload) || webhook.StatusCode(err) != http.StatusBadRequest {
+		t.Errorf("expected ErrInvalidPayload with 400, got %v", err)
+	}
+}
+
+func TestWebhook_EventID(t *testing.T) {
+	a, _ := webhook.Parse([]byte(`{"invoice_id": "INV-1", "state": "PENDING", "updated_at": "t1"}`), "")
+	b, _ := webhook.Parse([]byte(`{"invoice_id": "INV-1", "state": "PENDING", "updated_at": "t1", "extra": 1}`), "")
+	c, _ := webhook.Parse([]byte(`{"invoice_id": "INV-1", "state": "COMPLETE", "updated_at": "t2"}`), "")
+	if a.ID() != b.ID() {
+		t.Error("redeliveries of the same update should share an ID")
+	}
+	if a.ID() == c.ID() {
+		t.Error("different updates should have different IDs")
+	}
+}
+
+func TestWebhook_ParseWithProvider(t *testing.T) {
+	body := []byte(`{"invoice_id": "INV-1", "state": "COMPLETE", "challenge": "old"}`)
+	calls := 0
+	provider := webhook.NewCachedSecretProvider(webhook.SecretProviderFunc(func(ctx context.Context) ([]string, error) {
+		calls++
+		if calls > 1 {
+			return nil, errors.New("kms unreachable")
+		}
+		return []string{"new", "old"}, nil
+	}), time.Nanosecond)
+
+	for i := 0; i < 2; i++ {
+		if _, err := webhook.ParseWithProvider(context.Background(), body, provider); err != nil {
+			t.Fatalf("call %d: expected the previous challenge to be accepted, got %v", i, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected a refresh after the TTL, got %d provider calls", calls)
+	}
+
+	_, err := webhook.ParseWithProvider(context.Background(), body, webhook.StaticSecret("new"))
+	if !errors.Is(err, webhook.ErrInvalidChallenge) {
+		t.Errorf("expected ErrInvalidChallenge, got %v", err)
+	}
+}
+
+func TestWebhook_ParseWithProviderUnavailable(t *testing.T) {
+	body := []byte(`{"invoice_id": "INV-1", "challenge": ""}`)
+	for _, p := range []webhook.SecretProvider{
+		webhook.StaticSecret(""),
+		webhook.SecretProviderFunc(func(ctx context.Context) ([]string, error) { return nil, errors.New("denied") }),
+	} {
+		_, err := webhook.ParseWithProvider(context.Background(), body, p)
+		if !errors.Is(err, webhook.ErrSecretUnavailable) || webhook.StatusCode(err) != http.StatusServiceUnavailable {
+			t.Errorf("expected ErrSecretUnavailable and 503, got %v", err)
+		}
+	}
+}
+
+func TestWebhook_Handler(t *testing.T) {
+	var paid []string
+	var payouts int
+	h := webhook.Handler(webhook.HandlerOptions{
+		Secrets: webhook.StaticSecret("secret"),
+		OnInvoice: func(ctx context.Context, e *webhook.InvoiceEvent) error {
+			if e.Invoice.APIRef == "fail" {
+				return errors.New("database down")
+			}
+			if e.Paid() {
+				paid = append(paid, e.Invoice.InvoiceID)
+			}
+			return nil
+		},
+		OnPayout: func(ctx context.Context, e *webhook.PayoutEvent) error {
+			payouts++
+			if e.Payout.TrackingID != "TRK-1" || e.ResourceID != "TRK-1" {
+				t.Errorf("unexpected payout event: %+v", e.Payout)
+			}
+			return nil
+		},
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{"invoice", http.MethodPost, `{"invoice_id": "INV-1", "state": "COMPLETE", "api_ref": "ok", "challenge": "secret"}`, http.StatusOK},
+		{"payout", http.MethodPost, `{"tracking_id": "TRK-1", "status": "Completed", "challenge": "secret"}`, http.StatusOK},
+		{"no callback", http.MethodPost, `{"chargeback_id": "CHG-1", "challenge": "secret"}`, http.StatusOK},
+		{"callback error", http.MethodPost, `{"invoice_id": "INV-2", "state": "COMPLETE", "api_ref": "fail", "challenge": "secret"}`, http.StatusInternalServerError},
+		{"bad challenge", http.MethodPost, `{"invoice_id": "INV-3", "challenge": "wrong"}`, http.StatusUnauthorized},
+		{"bad payload", http.MethodPost, `{"invoice_id": "INV-4", "value": [], "challenge": "secret"}`, http.StatusBadRequest},
+		{"wrong method", http.MethodGet, ``, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/webhooks", strings.NewReader(tt.body)))
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, rec.Code)
+		}
+	}
+	if len(paid) != 1 || paid[0] != "INV-1" {
+		t.Errorf("expected INV-1 to be marked paid, got %v", paid)
+	}
+	if payouts != 1 {
+		t.Errorf("expected 1 payout callback, got %d", payouts)
+	}
+}
@@ -0,0 +1,141 @@
+package intasend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThrottledError is returned when a request targets a phone number that was
+// prompted or topped up less than the configured interval ago.
+// See WithRecipientThrottle.
+type ThrottledError struct {
+	// Recipient is the normalized phone number.
+	Recipient string
+
+	// RetryAfter is how long until the recipient may be targeted again.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("intasend: recipient %s throttled, retry after %v", e.Recipient, e.RetryAfter.Round(time.Millisecond))
+}
+
+// Is reports whether target is ErrRecipientThrottled.
+func (e *ThrottledError) Is(target error) bool {
+	return target == ErrRecipientThrottled
+}
+
+// throttleSweepSize is the map size above which expired entries are pruned.
+const throttleSweepSize = 1024
+
+// recipientThrottle enforces a minimum interval between requests to the same
+// phone number.
+type recipientThrottle struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func newRecipientThrottle(interval time.Duration) *recipientThrottle {
+	return &recipientThrottle{interval: interval, last: make(map[string]time.Time)}
+}
+
+// reserve claims all recipients at once, or none if any is throttled.
+// It returns the normalized recipients and the reservation time for release.
+func (t *recipientThrottle) reserve(phones ...string) ([]string, time.Time, error) {
+	now := time.Now()
+	keys := make([]string, 0, len(phones))
+	for _, p := range phones {
+		keys = append(keys, normalizePhone(p))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.last) > throttleSweepSize {
+		for k, at := range t.last {
+			if now.Sub(at) >= t.interval {
+				delete(t.last, k)
+			}
+		}
+	}
+	for _, k := range keys {
+		if at, ok := t.last[k]; ok {
+			if wait := t.interval - now.Sub(at); wait > 0 {
+				return nil, now, &ThrottledError{Recipient: k, RetryAfter: wait}
+			}
+		}
+	}
+	for _, k := range keys {
+		t.last[k] = now
+	}
+	return keys, now, nil
+}
+
+// release undoes a reservation made at the given time, unless a newer one
+// has since replaced it.
+func (t *recipientThrottle) release(keys []string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, k := range keys {
+		if t.last[k].Equal(at) {
+			delete(t.last, k)
+		}
+	}
+}
+
+// throttled runs fn after reserving the recipients. The reservation is
+// released when no prompt can have reached the phone: the API rejected the
+// request with a client error, refused it unprocessed (see notSent), or it
+// was never dispatched.
+func (c *Client) throttled(phones []string, fn func() error) error {
+	if c.throttle == nil {
+		return fn()
+	}
+	keys, at, err := c.throttle.reserve(phones...)
+	if err != nil {
+		return err
+	}
+	err = fn()
+	if apiErr := AsAPIError(err); (apiErr != nil && apiErr.HTTPStatusCode < 500) || notSent(err) || notDispatched(err) {
+		c.throttle.release(keys, at)
+	}
+	return err
+}
+
+// notDispatched reports whether err shows that no attempt of the request got
+// past connecting: a network error before anything was sent, or a context
+// error on its own. Context errors after an attempt carry that attempt's
+// error too, so they do not count.
+func notDispatched(err error) bool {
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return !netErr.sent
+	}
+	if AsAPIError(err) != nil {
+		return false
+	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// normalizePhone reduces a phone number to its digits, rewriting a leading
+// local "0" to the Kenyan country code so "0712..." and "254712..." match.
+func normalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if strings.HasPrefix(digits, "0") {
+		return "254" + digits[1:]
+	}
+	return digits
+}
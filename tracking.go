@@ -0,0 +1,208 @@
+package intasend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TrackedPayout is the record kept for an initiated payout batch.
+type TrackedPayout struct {
+	TrackingID string
+
+	// Request is the batch as submitted, after defaults were applied.
+	Request InitiateRequest
+
+	// Response is IntaSend's reply, including each transaction's request_ref_id.
+	Response InitiateResponse
+
+	// Fields are the context fields (see ContextWithFields) present when the
+	// batch was initiated, such as order or payroll run IDs.
+	Fields map[string]interface{}
+
+	// Actor is who initiated the batch; see ContextWithActor.
+	Actor Actor
+
+	CreatedAt time.Time
+}
+
+// TrackingStore persists initiated payouts so later status updates and
+// webhooks can be correlated with the original request. Implementations must
+// be safe for concurrent use and return ErrNotTracked for unknown IDs.
+type TrackingStore interface {
+	SavePayout(ctx context.Context, payout *TrackedPayout) error
+	LoadPayout(ctx context.Context, trackingID string) (*TrackedPayout, error)
+}
+
+// MemoryTrackingStore is an in-process TrackingStore. Records are lost on
+// restart, so production services should use a persistent implementation.
+type MemoryTrackingStore struct {
+	mu      sync.RWMutex
+	payouts map[string]*TrackedPayout
+}
+
+// NewMemoryTrackingStore creates an empty MemoryTrackingStore.
+func NewMemoryTrackingStore() *MemoryTrackingStore {
+	return &MemoryTrackingStore{payouts: make(map[string]*TrackedPayout)}
+}
+
+// SavePayout stores the payout, replacing any record with the same tracking ID.
+func (m *MemoryTrackingStore) SavePayout(_ context.Context, payout *TrackedPayout) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.payouts[payout.TrackingID] = payout
+	return nil
+}
+
+// LoadPayout returns the payout with the given tracking ID.
+func (m *MemoryTrackingStore) LoadPayout(_ context.Context, trackingID string) (*TrackedPayout, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if p, ok := m.payouts[trackingID]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotTracked, trackingID)
+}
+
+// CorrelatedTransaction pairs a transaction update with the transaction
+// originally submitted.
+type CorrelatedTransaction struct {
+	// Update is the transaction as reported by the status update or webhook.
+	Update TransactionResult
+
+	// Original is the submitted transaction, or nil if it could not be matched.
+	Original *Transaction
+
+	// Index is the position of Original in the submitted batch, or -1.
+	Index int
+}
+
+// PayoutCorrelation is a payout update enriched with the original batch.
+type PayoutCorrelation struct {
+	Batch        *TrackedPayout
+	Status       string
+	Transactions []CorrelatedTransaction
+}
+
+// trackPayout records an initiated payout. Failures are logged and sent to
+// the dead-letter handler rather than returned: the payout has already been
+// submitted and the caller needs the tracking ID regardless.
+func (s *PayoutService) trackPayout(ctx context.Context, req *InitiateRequest, resp *InitiateResponse) {
+	store := s.client.trackingStore
+	if store == nil {
+		return
+	}
+	record := &TrackedPayout{
+		TrackingID: resp.TrackingID,
+		Request:    *req,
+		Response:   *resp,
+		Fields:     FieldsFromContext(ctx),
+		CreatedAt:  time.Now(),
+	}
+	record.Actor, _ = ActorFromContext(ctx)
+	record.Request.Transactions = append([]Transaction(nil), req.Transactions...)
+	record.Response.Transactions = append([]TransactionResult(nil), resp.Transactions...)
+	if t := s.client.tokenizer; t != nil {
+		if err := tokenizePayout(ctx, t, record); err != nil {
+			s.client.log(ctx, LogLevelError, "not tracking payout", map[string]interface{}{"tracking_id": resp.TrackingID, "error": err})
+			s.client.deadLetter(DeadLetterPayoutTracking, record, 1, err)
+			return
+		}
+	}
+
+	if err := store.SavePayout(ctx, record); err != nil {
+		s.client.log(ctx, LogLevelError, "failed to track payout", map[string]interface{}{"tracking_id": resp.TrackingID, "error": err})
+		s.client.deadLetter(DeadLetterPayoutTracking, record, 1, err)
+	}
+}
+
+// Correlate matches a payout status update, such as a webhook delivered to
+// the batch's CallbackURL or a Status response, with the batch recorded in
+// the client's TrackingStore. Transactions are matched by request_ref_id,
+// falling back to account and amount when IntaSend did not return reference
+// IDs at initiation. With WithTokenizer, the update's accounts are tokenized
+// before that comparison, which only matches if the tokenizer is
+// deterministic.
+//
+// Example:
+//
+//	event, _ := webhook.ParseRequest(r, challenge)
+//	update, _ := event.Payout()
+//	corr, err := client.Payout().Correlate(ctx, update)
+//	for _, tx := range corr.Transactions {
+//	    log.Printf("%s -> %s (run %v)", tx.Original.Account, tx.Update.Status, corr.Batch.Fields["payroll_run"])
+//	}
+func (s *PayoutService) Correlate(ctx context.Context, update *PayoutStatusResponse, reqOpts ...RequestOption) (*PayoutCorrelation, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(update == nil); err != nil {
+		return nil, err
+	}
+	store := s.client.trackingStore
+	if store == nil {
+		return nil, ErrNoTrackingStore
+	}
+	batch, err := store.LoadPayout(ctx, update.TrackingID)
+	if err != nil {
+		return nil, err
+	}
+
+	byRef := make(map[string]int, len(batch.Response.Transactions))
+	for i, tx := range batch.Response.Transactions {
+		if tx.RequestRefID != "" && i < len(batch.Request.Transactions) {
+			byRef[tx.RequestRefID] = i
+		}
+	}
+
+	matched := make([]bool, len(batch.Request.Transactions))
+	corr := &PayoutCorrelation{Batch: batch, Status: update.Status}
+	for _, tx := range update.Transactions {
+		idx, ok := byRef[tx.RequestRefID]
+		if !ok || matched[idx] {
+			lookup := tx
+			if t := s.client.tokenizer; t != nil {
+				if lookup.Account, err = tokenize(ctx, t, PIIAccount, tx.Account); err != nil {
+					return nil, err
+				}
+			}
+			idx = matchByAccount(batch.Request.Transactions, matched, lookup)
+		}
+		ct := CorrelatedTransaction{Update: tx, Index: idx}
+		if idx >= 0 {
+			matched[idx] = true
+			ct.Original = &batch.Request.Transactions[idx]
+		}
+		corr.Transactions = append(corr.Transactions, ct)
+	}
+	return corr, nil
+}
+
+// matchByAccount finds the first unmatched original with the same account
+// and amount as tx, returning -1 if none does.
+func matchByAccount(originals []Transaction, matched []bool, tx TransactionResult) int {
+	amount := fmt.Sprint(tx.Amount)
+	for i, o := range originals {
+		if matched[i] || o.Account != tx.Account {
+			continue
+		}
+		if tx.Amount == nil || amountsEqual(o.Amount, amount) {
+			return i
+		}
+	}
+	return -1
+}
+
+// amountsEqual compares two decimal amount strings numerically.
+func amountsEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var x, y float64
+	if _, err := fmt.Sscan(a, &x); err != nil {
+		return false
+	}
+	if _, err := fmt.Sscan(b, &y); err != nil {
+		return false
+	}
+	return x == y
+}
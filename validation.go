@@ -0,0 +1,285 @@
+package intasend
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError describes one invalid field of a request.
+type FieldError struct {
+	// Field is the field's JSON name, such as "phone_number" or
+	// "transactions[0].amount".
+	Field string
+
+	// Message says what is wrong with the field, such as "is required".
+	Message string
+
+	// err is the sentinel the field error matches with errors.Is.
+	err error
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// Unwrap returns the sentinel error for the kind of problem:
+// ErrIncompleteRequest, ErrInvalidPhoneNumber, ErrInvalidCurrency, or
+// ErrInvalidAmount.
+func (e FieldError) Unwrap() error {
+	return e.err
+}
+
+// ValidationError is returned, before anything is sent, for a request the
+// API would reject: a missing required field, a phone number that is not a
+// Kenyan mobile number, a malformed currency code, or an amount that is
+// not positive. It lists every invalid field, not just the first.
+//
+// It matches the sentinel of each of its fields with errors.Is, so
+// errors.Is(err, intasend.ErrInvalidAmount) still reports a bad amount.
+//
+// Example:
+//
+//	_, err := client.Collection().MPesaSTKPush(ctx, req)
+//	if verr := intasend.AsValidationError(err); verr != nil {
+//	    for _, f := range verr.Fields {
+//	        form.SetError(f.Field, f.Message)
+//	    }
+//	}
+type ValidationError struct {
+	// Request names the request that failed validation, such as
+	// "STK push".
+	Request string
+
+	// Fields are the invalid fields, in the order they were checked.
+	Fields []FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("intasend: invalid %s request: %s", e.Request, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the field errors.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f
+	}
+	return errs
+}
+
+// Field returns the error for the named field, or nil if it is valid.
+func (e *ValidationError) Field(name string) *FieldError {
+	for i := range e.Fields {
+		if e.Fields[i].Field == name {
+			return &e.Fields[i]
+		}
+	}
+	return nil
+}
+
+// AsValidationError attempts to extract a ValidationError from the given
+// error. Returns nil if the error is not a ValidationError.
+func AsValidationError(err error) *ValidationError {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr
+	}
+	return nil
+}
+
+// validator collects the field errors of one request.
+type validator struct {
+	request string
+	fields  []FieldError
+}
+
+// add records an invalid field.
+func (v *validator) add(field, message string, err error) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: message, err: err})
+}
+
+// required records field as missing if value is blank.
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required", ErrIncompleteRequest)
+		return false
+	}
+	return true
+}
+
+// phone checks that a required phone number is a Kenyan mobile number.
+// Spaces, dashes, a leading "+", and the local "07..." and "01..." forms
+// are accepted, as elsewhere in the SDK.
+func (v *validator) phone(field, value string) {
+	if !v.required(field, value) {
+		return
+	}
+	if !isMobileNumber(value) {
+		v.add(field, "must be a Kenyan mobile number such as 254712345678", ErrInvalidPhoneNumber)
+	}
+}
+
+// currency checks that a required currency is a three-letter code such as
+// "KES".
+func (v *validator) currency(field, value string) {
+	if !v.required(field, value) {
+		return
+	}
+	if len(value) != 3 || strings.ToUpper(value) != value || !isLetters(value) {
+		v.add(field, "must be a three-letter currency code such as KES", ErrInvalidCurrency)
+	}
+}
+
+// amount checks that a is a positive decimal with at most two decimal
+// places.
+func (v *validator) amount(field string, a Amount) {
+	n, ok := normalizeDecimal(string(a))
+	if !ok || Amount(n).Cmp("0") <= 0 || Amount(n).scale() > maxAmountDecimals {
+		v.add(field, fmt.Sprintf("must be a positive amount with at most 2 decimal places, got %q", string(a)), ErrInvalidAmount)
+	}
+}
+
+// decimals checks that an optional exact amount has at most two decimal
+// places. Float amounts need no check, as they are rounded to cents.
+func (v *validator) decimals(field string, a Amount) {
+	if a != "" && a.scale() > maxAmountDecimals {
+		v.add(field, fmt.Sprintf("must have at most 2 decimal places, got %q", string(a)), ErrInvalidAmount)
+	}
+}
+
+// err returns the collected fields as a *ValidationError, or nil if there
+// are none.
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Request: v.request, Fields: v.fields}
+}
+
+// isMobileNumber reports whether phone is a Kenyan mobile number: 2547 or
+// 2541 followed by eight digits, once formatting is removed.
+func isMobileNumber(phone string) bool {
+	for i, r := range phone {
+		if !(r >= '0' && r <= '9') && r != ' ' && r != '-' && !(r == '+' && i == 0) {
+			return false
+		}
+	}
+	digits := normalizePhone(phone)
+	return len(digits) == 12 && (strings.HasPrefix(digits, "2547") || strings.HasPrefix(digits, "2541"))
+}
+
+// isLetters reports whether s consists only of ASCII letters.
+func isLetters(s string) bool {
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks the fields the STK push endpoint requires.
+func (r *STKPushRequest) validate() error {
+	v := validator{request: "STK push"}
+	v.phone("phone_number", r.PhoneNumber)
+	v.amount("amount", requestAmount(r.AmountDecimal, r.Amount))
+	return v.err()
+}
+
+// validate checks the fields the checkout endpoint requires, with currency
+// being the request's currency after the client default is applied.
+func (r *ChargeRequest) validate(currency string) error {
+	v := validator{request: "charge"}
+	v.amount("amount", requestAmount(r.AmountDecimal, r.Amount))
+	v.currency("currency", currency)
+	return v.err()
+}
+
+// validate checks the fields the hosted checkout endpoint requires, with
+// currency being the request's currency after the client default is
+// applied.
+func (r *CreateCheckoutRequest) validate(currency string) error {
+	v := validator{request: "checkout"}
+	r.check(&v, currency)
+	return v.err()
+}
+
+// check adds the checkout fields validate checks to v, so CheckoutBuilder
+// can report them with its own.
+func (r *CreateCheckoutRequest) check(v *validator, currency string) {
+	v.amount("amount", requestAmount(r.AmountDecimal, r.Amount))
+	v.currency("currency", currency)
+}
+
+// validate checks the fields the M-Pesa funding endpoint requires.
+func (r *FundMPesaRequest) validate() error {
+	v := validator{request: "M-Pesa funding"}
+	v.required("wallet_id", r.WalletID)
+	v.phone("phone_number", r.PhoneNumber)
+	v.amount("amount", requestAmount(r.AmountDecimal, r.Amount))
+	return v.err()
+}
+
+// validate checks the fields the payout endpoint requires, once the client
+// defaults are applied. M-Pesa B2C and airtime accounts must be mobile
+// numbers.
+func (r *InitiateRequest) validate() error {
+	v := validator{request: "payout"}
+	r.check(&v)
+	return v.err()
+}
+
+// check adds the payout fields validate checks to v, so PayoutBuilder can
+// report them with its own.
+func (r *InitiateRequest) check(v *validator) {
+	v.currency("currency", r.Currency)
+	if len(r.Transactions) == 0 {
+		v.add("transactions", "must have at least one transaction", ErrIncompleteRequest)
+	}
+	for i, tx := range r.Transactions {
+		prefix := fmt.Sprintf("transactions[%d].", i)
+		if r.Provider == ProviderMPesaB2C || r.Provider == ProviderAirtime {
+			v.phone(prefix+"account", tx.Account)
+		} else {
+			v.required(prefix+"account", tx.Account)
+		}
+		if v.required(prefix+"amount", tx.Amount) && ValidateAmount(tx.Amount) != nil {
+			v.add(prefix+"amount", fmt.Sprintf("must be a positive amount with at most 2 decimal places, got %q", tx.Amount), ErrInvalidAmount)
+		}
+	}
+}
+
+// validate checks the amount of an intra-wallet transfer.
+func (r *IntraTransferRequest) validate() error {
+	v := validator{request: "intra-wallet transfer"}
+	v.decimals("amount", r.AmountDecimal)
+	return v.err()
+}
+
+// validate checks the amount of a wallet checkout funding request.
+func (r *FundCheckoutRequest) validate() error {
+	v := validator{request: "wallet checkout funding"}
+	v.decimals("amount", r.AmountDecimal)
+	return v.err()
+}
+
+// validate checks the amount of a payment link.
+func (r *CreatePaymentLinkRequest) validate() error {
+	v := validator{request: "payment link"}
+	v.decimals("amount", r.AmountDecimal)
+	return v.err()
+}
+
+// validate checks the amount of a refund.
+func (r *CreateChargebackRequest) validate() error {
+	v := validator{request: "refund"}
+	v.decimals("amount", r.AmountDecimal)
+	return v.err()
+}
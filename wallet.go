@@ -3,6 +3,12 @@ package intasend
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -31,9 +37,18 @@ type Wallet struct {
 	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
-// WalletListResponse represents the response from listing wallets.
+// CurrentBalanceDecimal returns CurrentBalance as an exact decimal.
+func (w *Wallet) CurrentBalanceDecimal() Amount { return AmountFromFloat(w.CurrentBalance) }
+
+// AvailableBalanceDecimal returns AvailableBalance as an exact decimal.
+func (w *Wallet) AvailableBalanceDecimal() Amount { return AmountFromFloat(w.AvailableBalance) }
+
+// WalletListResponse represents a page of wallets.
 type WalletListResponse struct {
-	Results []Wallet `json:"results"`
+	Count    int      `json:"count,omitempty"`
+	Next     string   `json:"next,omitempty"`
+	Previous string   `json:"previous,omitempty"`
+	Results  []Wallet `json:"results"`
 }
 
 // CreateWalletRequest represents a request to create a wallet.
@@ -48,31 +63,285 @@ type CreateWalletRequest struct {
 type WalletTransaction struct {
 	TransactionID  string    `json:"transaction_id"`
 	WalletID       string    `json:"wallet_id"`
-	TransType      string    `json:"trans_type"`
+	TransType      TransType `json:"trans_type"`
 	Amount         float64   `json:"amount"`
 	Narrative      string    `json:"narrative"`
 	RunningBalance float64   `json:"running_balance"`
 	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// changedAt returns when the transaction was last updated, or its creation
+// time if the API did not report an update time.
+func (t *WalletTransaction) changedAt() time.Time {
+	if t.UpdatedAt.IsZero() {
+		return t.CreatedAt
+	}
+	return t.UpdatedAt
+}
+
+// TransType is the type of a wallet transaction as reported by IntaSend.
+// Values not listed below may appear; Direction falls back to the sign of
+// the amount for them.
+type TransType string
+
+const (
+	// TransTypeCredit is a deposit into the wallet.
+	TransTypeCredit TransType = "CREDIT"
+
+	// TransTypeDebit is a withdrawal from the wallet.
+	TransTypeDebit TransType = "DEBIT"
+
+	// TransTypeSale is a collected payment.
+	TransTypeSale TransType = "SALE"
+
+	// TransTypePayout is a disbursement sent from the wallet.
+	TransTypePayout TransType = "PAYOUT"
+
+	// TransTypeCharge is a transaction fee.
+	TransTypeCharge TransType = "CHARGE"
+
+	// TransTypeUnmark releases funds previously held on the wallet back
+	// to its available balance.
+	TransTypeUnmark TransType = "UNMARK"
+)
+
+// Direction is whether a transaction moved funds into or out of a wallet.
+type Direction string
+
+const (
+	// DirectionIn is money received by the wallet.
+	DirectionIn Direction = "IN"
+
+	// DirectionOut is money leaving the wallet, including fees.
+	DirectionOut Direction = "OUT"
+
+	// DirectionUnknown is returned when the direction cannot be told.
+	DirectionUnknown Direction = ""
+)
+
+// Direction returns the direction implied by the transaction type, or
+// DirectionUnknown for types the SDK does not know. The comparison ignores
+// case.
+func (t TransType) Direction() Direction {
+	switch TransType(strings.ToUpper(string(t))) {
+	case TransTypeCredit, TransTypeSale, TransTypeUnmark:
+		return DirectionIn
+	case TransTypeDebit, TransTypePayout, TransTypeCharge, "CHARGES", "FEE", "FEES":
+		return DirectionOut
+	}
+	return DirectionUnknown
+}
+
+// IsFee reports whether the type is a transaction charge.
+func (t TransType) IsFee() bool {
+	switch TransType(strings.ToUpper(string(t))) {
+	case TransTypeCharge, "CHARGES", "FEE", "FEES":
+		return true
+	}
+	return false
 }
 
+// Direction returns whether tx moved funds into or out of the wallet. It
+// uses the transaction type, falling back to the sign of Amount for
+// unknown types.
+//
+// Example:
+//
+//	for _, tx := range txns.Results {
+//	    switch tx.Direction() {
+//	    case intasend.DirectionIn:
+//	        ledger.Credit(tx.TransactionID, tx.SignedAmount())
+//	    case intasend.DirectionOut:
+//	        ledger.Debit(tx.TransactionID, -tx.SignedAmount())
+//	    }
+//	}
+func (tx WalletTransaction) Direction() Direction {
+	if d := tx.TransType.Direction(); d != DirectionUnknown {
+		return d
+	}
+	switch {
+	case tx.Amount > 0:
+		return DirectionIn
+	case tx.Amount < 0:
+		return DirectionOut
+	}
+	return DirectionUnknown
+}
+
+// SignedAmount returns Amount signed by Direction: positive for money in,
+// negative for money out, whatever sign the API reported. Amount is
+// returned unchanged when the direction is unknown.
+func (tx WalletTransaction) SignedAmount() float64 {
+	switch tx.Direction() {
+	case DirectionIn:
+		return math.Abs(tx.Amount)
+	case DirectionOut:
+		return -math.Abs(tx.Amount)
+	}
+	return tx.Amount
+}
+
+// AmountDecimal returns Amount as an exact decimal.
+func (tx WalletTransaction) AmountDecimal() Amount { return AmountFromFloat(tx.Amount) }
+
+// RunningBalanceDecimal returns RunningBalance as an exact decimal.
+func (tx WalletTransaction) RunningBalanceDecimal() Amount { return AmountFromFloat(tx.RunningBalance) }
+
 // WalletTransactionsResponse represents the response from listing wallet transactions.
 type WalletTransactionsResponse struct {
-	Results []WalletTransaction `json:"results"`
+	Count    int                 `json:"count,omitempty"`
+	Next     string              `json:"next,omitempty"`
+	Previous string              `json:"previous,omitempty"`
+	Results  []WalletTransaction `json:"results"`
+}
+
+// Ordering selects the sort order of a listing.
+type Ordering string
+
+const (
+	// OrderingCreatedAsc lists oldest first. New records land on the last
+	// page, so paging through a listing while it grows does not skip any.
+	OrderingCreatedAsc Ordering = "created_at"
+
+	// OrderingCreatedDesc lists newest first. Records created while paging
+	// push earlier ones onto later pages, where they are seen twice.
+	OrderingCreatedDesc Ordering = "-created_at"
+)
+
+// WalletTransactionListOptions contains optional filters for listing wallet transactions.
+type WalletTransactionListOptions struct {
+	// Since limits results to transactions created or updated at or after
+	// this time.
+	Since time.Time
+
+	// Ordering sets the sort order. The API default applies when empty.
+	Ordering Ordering
+
+	// Page selects the results page, starting at 1.
+	Page int
+}
+
+// query encodes the options as URL query parameters.
+func (o *WalletTransactionListOptions) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if !o.Since.IsZero() {
+		q.Set("updated_since", o.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if o.Ordering != "" {
+		q.Set("ordering", string(o.Ordering))
+	}
+	if o.Page > 0 {
+		q.Set("page", strconv.Itoa(o.Page))
+	}
+	return q
+}
+
+// DefaultStreamInterval is the default polling interval for StreamTransactions.
+const DefaultStreamInterval = 30 * time.Second
+
+// StreamOptions configures StreamTransactions.
+type StreamOptions struct {
+	// Interval is the time between polls. Defaults to DefaultStreamInterval.
+	Interval time.Duration
+
+	// Buffer is the capacity of the transaction channel. Defaults to 0.
+	Buffer int
+}
+
+// TransactionStream delivers new wallet transactions as they appear.
+type TransactionStream struct {
+	// C receives transactions in creation order. It is closed when the
+	// stream's context is done or a poll fails.
+	C <-chan WalletTransaction
+
+	mu     sync.Mutex
+	err    error
+	cursor time.Time
+}
+
+// Err returns the error that stopped the stream, if any.
+// It should be called after C has been closed.
+func (t *TransactionStream) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Cursor returns the latest update time of the delivered transactions.
+// Pass it as since to StreamTransactions to resume the stream; transactions
+// sharing exactly that timestamp may be delivered again.
+func (t *TransactionStream) Cursor() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cursor
+}
+
+// Period is a half-open time range [Start, End).
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// MonthOf returns the calendar month containing t, in t's location.
+func MonthOf(t time.Time) Period {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// Contains reports whether t falls within the period. A zero End is open-ended.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && (p.End.IsZero() || t.Before(p.End))
+}
+
+// summaryTopN is the number of largest transactions kept in a WalletSummary.
+const summaryTopN = 5
+
+// WalletSummary aggregates a wallet's transactions over a period.
+// Amounts are in the wallet's currency; outflows and fees are positive.
+type WalletSummary struct {
+	WalletID string
+	Period   Period
+
+	// Count is the number of transactions in the period.
+	Count int
+
+	// TotalIn is the sum of credits.
+	TotalIn float64
+
+	// TotalOut is the sum of debits, excluding fees.
+	TotalOut float64
+
+	// Fees is the sum of transaction charges.
+	Fees float64
+
+	// Net is TotalIn minus TotalOut and Fees.
+	Net float64
+
+	// LargestIn and LargestOut hold the five largest credits and debits,
+	// largest first. Fees are excluded.
+	LargestIn  []WalletTransaction
+	LargestOut []WalletTransaction
 }
 
 // IntraTransferRequest represents a request to transfer between wallets.
+// AmountDecimal, if set, is sent instead of Amount, exactly as given.
 type IntraTransferRequest struct {
 	SourceID      string
 	DestinationID string
 	Amount        float64
+	AmountDecimal Amount
 	Narrative     string
 }
 
 // intraTransferBody is the internal request body.
 type intraTransferBody struct {
-	WalletID  string  `json:"wallet_id"`
-	Amount    float64 `json:"amount"`
-	Narrative string  `json:"narrative"`
+	WalletID  string `json:"wallet_id"`
+	Amount    Amount `json:"amount"`
+	Narrative string `json:"narrative"`
 }
 
 // IntraTransferResponse represents the response from an intra-wallet transfer.
@@ -84,6 +353,9 @@ type IntraTransferResponse struct {
 	Narrative string  `json:"narrative"`
 }
 
+// AmountDecimal returns Amount as an exact decimal.
+func (r *IntraTransferResponse) AmountDecimal() Amount { return AmountFromFloat(r.Amount) }
+
 // WalletCustomer represents customer information for wallet funding.
 type WalletCustomer struct {
 	FirstName   string
@@ -98,24 +370,26 @@ type WalletCustomer struct {
 }
 
 // FundMPesaRequest represents a request to fund a wallet via M-Pesa.
+// AmountDecimal, if set, is sent instead of Amount, exactly as given.
 type FundMPesaRequest struct {
-	WalletID    string
-	PhoneNumber string
-	Amount      float64
-	Email       string
-	APIRef      string
+	WalletID      string
+	PhoneNumber   string
+	Amount        float64
+	AmountDecimal Amount
+	Email         string
+	APIRef        string
 }
 
 // fundMPesaBody is the internal request body.
 type fundMPesaBody struct {
-	PublicKey   string  `json:"public_key,omitempty"`
-	WalletID    string  `json:"wallet_id"`
-	PhoneNumber string  `json:"phone_number"`
-	Amount      float64 `json:"amount"`
-	Email       string  `json:"email,omitempty"`
-	APIRef      string  `json:"api_ref,omitempty"`
-	Method      string  `json:"method"`
-	Currency    string  `json:"currency"`
+	PublicKey   string `json:"public_key,omitempty"`
+	WalletID    string `json:"wallet_id"`
+	PhoneNumber string `json:"phone_number"`
+	Amount      Amount `json:"amount"`
+	Email       string `json:"email,omitempty"`
+	APIRef      string `json:"api_ref,omitempty"`
+	Method      string `json:"method"`
+	Currency    string `json:"currency"`
 }
 
 // FundMPesaResponse represents the response from funding via M-Pesa.
@@ -125,34 +399,36 @@ type FundMPesaResponse struct {
 }
 
 // FundCheckoutRequest represents a request to fund a wallet via checkout.
+// AmountDecimal, if set, is sent instead of Amount, exactly as given.
 type FundCheckoutRequest struct {
-	WalletID     string
-	Amount       float64
-	Currency     string
-	Customer     WalletCustomer
-	Host         string
-	RedirectURL  string
-	APIRef       string
-	CardTariff   string
-	MobileTariff string
+	WalletID      string
+	Amount        float64
+	AmountDecimal Amount
+	Currency      string
+	Customer      WalletCustomer
+	Host          string
+	RedirectURL   string
+	APIRef        string
+	CardTariff    string
+	MobileTariff  string
 }
 
 // fundCheckoutBody is the internal request body.
 type fundCheckoutBody struct {
-	PublicKey    string  `json:"public_key,omitempty"`
-	WalletID     string  `json:"wallet_id"`
-	Amount       float64 `json:"amount"`
-	Currency     string  `json:"currency"`
-	Email        string  `json:"email"`
-	FirstName    string  `json:"first_name,omitempty"`
-	LastName     string  `json:"last_name,omitempty"`
-	PhoneNumber  string  `json:"phone_number,omitempty"`
-	Country      string  `json:"country,omitempty"`
-	Host         string  `json:"host"`
-	RedirectURL  string  `json:"redirect_url,omitempty"`
-	APIRef       string  `json:"api_ref,omitempty"`
-	CardTariff   string  `json:"card_tarrif,omitempty"`
-	MobileTariff string  `json:"mobile_tarrif,omitempty"`
+	PublicKey    string `json:"public_key,omitempty"`
+	WalletID     string `json:"wallet_id"`
+	Amount       Amount `json:"amount"`
+	Currency     string `json:"currency"`
+	Email        string `json:"email"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	PhoneNumber  string `json:"phone_number,omitempty"`
+	Country      string `json:"country,omitempty"`
+	Host         string `json:"host"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+	APIRef       string `json:"api_ref,omitempty"`
+	CardTariff   string `json:"card_tarrif,omitempty"`
+	MobileTariff string `json:"mobile_tarrif,omitempty"`
 }
 
 // FundCheckoutResponse represents the response from creating a checkout.
@@ -162,12 +438,14 @@ type FundCheckoutResponse struct {
 	Signature string `json:"signature"`
 }
 
-// List returns all wallets in the account.
+// List returns the first page of wallets in the account. Use ListAll for
+// accounts with more wallets than fit on a page.
 //
 // Example:
 //
 //	wallets, err := client.Wallet().List(ctx)
-func (s *WalletService) List(ctx context.Context) (*WalletListResponse, error) {
+func (s *WalletService) List(ctx context.Context, reqOpts ...RequestOption) (*WalletListResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	var resp WalletListResponse
 	if err := s.client.get(ctx, "/wallets/", &resp); err != nil {
 		return nil, err
@@ -175,6 +453,26 @@ func (s *WalletService) List(ctx context.Context) (*WalletListResponse, error) {
 	return &resp, nil
 }
 
+// ListAll returns every wallet in the account, following pagination.
+//
+// Example:
+//
+//	wallets, err := client.Wallet().ListAll(ctx)
+func (s *WalletService) ListAll(ctx context.Context, reqOpts ...RequestOption) ([]Wallet, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var all []Wallet
+	for page := 1; ; page++ {
+		var resp WalletListResponse
+		if err := s.client.get(ctx, withQuery("/wallets/", pageQuery(page)), &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Results...)
+		if resp.Next == "" || len(resp.Results) == 0 {
+			return all, nil
+		}
+	}
+}
+
 // Create creates a new wallet.
 //
 // Example:
@@ -184,13 +482,20 @@ func (s *WalletService) List(ctx context.Context) (*WalletListResponse, error) {
 //	    Label:       "Operations Wallet",
 //	    CanDisburse: true,
 //	})
-func (s *WalletService) Create(ctx context.Context, req *CreateWalletRequest) (*Wallet, error) {
-	if req.WalletType == "" {
-		req.WalletType = WalletTypeWorking
+func (s *WalletService) Create(ctx context.Context, req *CreateWalletRequest, reqOpts ...RequestOption) (*Wallet, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
 	}
+	// Copy the request so the caller's value is never modified.
+	body := *req
+	if body.WalletType == "" {
+		body.WalletType = WalletTypeWorking
+	}
+	body.Label = s.client.sandboxTagged(ctx, body.Label)
 
 	var resp Wallet
-	if err := s.client.post(ctx, "/wallets/", req, &resp); err != nil {
+	if err := s.client.post(ctx, "/wallets/", &body, &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -201,7 +506,8 @@ func (s *WalletService) Create(ctx context.Context, req *CreateWalletRequest) (*
 // Example:
 //
 //	wallet, err := client.Wallet().Get(ctx, "WALLET123")
-func (s *WalletService) Get(ctx context.Context, walletID string) (*Wallet, error) {
+func (s *WalletService) Get(ctx context.Context, walletID string, reqOpts ...RequestOption) (*Wallet, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	var resp Wallet
 	if err := s.client.get(ctx, fmt.Sprintf("/wallets/%s/", walletID), &resp); err != nil {
 		return nil, err
@@ -214,7 +520,8 @@ func (s *WalletService) Get(ctx context.Context, walletID string) (*Wallet, erro
 // Example:
 //
 //	txns, err := client.Wallet().Transactions(ctx, "WALLET123")
-func (s *WalletService) Transactions(ctx context.Context, walletID string) (*WalletTransactionsResponse, error) {
+func (s *WalletService) Transactions(ctx context.Context, walletID string, reqOpts ...RequestOption) (*WalletTransactionsResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	var resp WalletTransactionsResponse
 	if err := s.client.get(ctx, fmt.Sprintf("/wallets/%s/transactions/", walletID), &resp); err != nil {
 		return nil, err
@@ -222,6 +529,348 @@ func (s *WalletService) Transactions(ctx context.Context, walletID string) (*Wal
 	return &resp, nil
 }
 
+// ListTransactions retrieves a page of transactions for a wallet, filtered by opts.
+// When paging through a listing that may grow, use OrderingCreatedAsc so no
+// transaction is skipped; SyncTransactions and StreamTransactions do this
+// for you.
+//
+// Example:
+//
+//	txns, err := client.Wallet().ListTransactions(ctx, "WALLET123", &intasend.WalletTransactionListOptions{
+//	    Since:    time.Now().Add(-24 * time.Hour),
+//	    Ordering: intasend.OrderingCreatedAsc,
+//	})
+func (s *WalletService) ListTransactions(ctx context.Context, walletID string, opts *WalletTransactionListOptions, reqOpts ...RequestOption) (*WalletTransactionsResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var resp WalletTransactionsResponse
+	path := withQuery(fmt.Sprintf("/wallets/%s/transactions/", walletID), opts.query())
+	if err := s.client.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// StreamTransactions polls a wallet for transactions created or updated at
+// or after since and delivers each version of a transaction once on the
+// returned stream's channel, in creation order within each poll. A
+// transaction updated after it was delivered is delivered again with its
+// new UpdatedAt, so apply deliveries as upserts keyed by TransactionID.
+// The stream runs until ctx is done or a poll fails; inspect Err afterwards
+// and use Cursor to resume. A failed poll is also reported to the client's
+// dead-letter handler.
+//
+// Example:
+//
+//	stream := client.Wallet().StreamTransactions(ctx, "WALLET123", lastSeen, nil)
+//	for txn := range stream.C {
+//	    ledger.Record(txn)
+//	}
+//	if err := stream.Err(); err != nil {
+//	    log.Printf("stream stopped at %v: %v", stream.Cursor(), err)
+//	}
+func (s *WalletService) StreamTransactions(ctx context.Context, walletID string, since time.Time, opts *StreamOptions, reqOpts ...RequestOption) *TransactionStream {
+	ctx = withRequestOptions(ctx, reqOpts)
+	interval := DefaultStreamInterval
+	buffer := 0
+	if opts != nil {
+		if opts.Interval > 0 {
+			interval = opts.Interval
+		}
+		buffer = opts.Buffer
+	}
+
+	ch := make(chan WalletTransaction, buffer)
+	stream := &TransactionStream{C: ch, cursor: since}
+
+	go func() {
+		defer close(ch)
+
+		// seen holds the versions delivered at or after the cursor, for
+		// deduplication of the inclusive since boundary. Older versions
+		// are pruned; polls never return them again.
+		seen := make(map[txnVersion]bool)
+		cursor := since
+
+		for {
+			txns, err := s.fetchTransactionsSince(ctx, walletID, cursor)
+			if err != nil {
+				if ctx.Err() == nil {
+					stream.mu.Lock()
+					stream.err = err
+					stream.mu.Unlock()
+					s.client.deadLetter(DeadLetterTransactionStream, StreamPosition{WalletID: walletID, Cursor: cursor}, 0, err)
+				}
+				return
+			}
+
+			for _, txn := range txns {
+				v := txnVersion{id: txn.TransactionID, at: txn.changedAt().UTC()}
+				if seen[v] {
+					continue
+				}
+				select {
+				case ch <- txn:
+				case <-ctx.Done():
+					return
+				}
+				seen[v] = true
+				if v.at.After(cursor) {
+					cursor = v.at
+				}
+				stream.mu.Lock()
+				stream.cursor = cursor
+				stream.mu.Unlock()
+			}
+
+			for v := range seen {
+				if v.at.Before(cursor) {
+					delete(seen, v)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+
+	return stream
+}
+
+// txnVersion identifies one version of a wallet transaction by its ID and
+// the time it last changed.
+type txnVersion struct {
+	id string
+	at time.Time
+}
+
+// WalletTransactionSync is the result of an incremental wallet transaction
+// sync.
+type WalletTransactionSync struct {
+	// Transactions were created or updated since the previous token,
+	// oldest first.
+	Transactions []WalletTransaction
+
+	// Token is passed to the next SyncTransactions call. It is unchanged
+	// when there were no new transactions.
+	Token SyncToken
+}
+
+// SyncTransactions fetches the wallet transactions created or updated since
+// token, following pagination, so nightly jobs fetch only what changed
+// instead of a full export. Pass an empty token for the first run and store
+// the returned token for the next.
+//
+// The filter is inclusive, so transactions updated at exactly the token's
+// time may be returned again, and a transaction updated after an earlier
+// sync is returned again with its new UpdatedAt; apply results as upserts
+// keyed by TransactionID.
+//
+// Example:
+//
+//	sync, err := client.Wallet().SyncTransactions(ctx, "WALLET123", lastToken)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, txn := range sync.Transactions {
+//	    ledger.Record(txn)
+//	}
+//	lastToken = sync.Token
+func (s *WalletService) SyncTransactions(ctx context.Context, walletID string, token SyncToken, reqOpts ...RequestOption) (*WalletTransactionSync, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	since, err := token.since()
+	if err != nil {
+		return nil, err
+	}
+	txns, err := s.fetchTransactionsSince(ctx, walletID, since)
+	if err != nil {
+		return nil, err
+	}
+	mark := since
+	for i := range txns {
+		if t := txns[i].changedAt(); t.After(mark) {
+			mark = t
+		}
+	}
+	return &WalletTransactionSync{Transactions: txns, Token: token.advance(since, mark)}, nil
+}
+
+// fetchTransactionsSince retrieves every page of transactions created or
+// updated at or after since, oldest first. Pages are requested in ascending
+// order so that transactions recorded mid-pagination are appended rather
+// than shifting earlier pages. A transaction served twice is kept once, in
+// its latest version. Transactions with the same creation time are ordered
+// by ID, so the order is stable.
+//
+// The since filter is applied again to each result, so callers get the
+// same transactions whether or not the API honours updated_since.
+func (s *WalletService) fetchTransactionsSince(ctx context.Context, walletID string, since time.Time) ([]WalletTransaction, error) {
+	var all []WalletTransaction
+	index := make(map[string]int)
+	for page := 1; ; page++ {
+		resp, err := s.ListTransactions(ctx, walletID, &WalletTransactionListOptions{
+			Since:    since,
+			Ordering: OrderingCreatedAsc,
+			Page:     page,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, txn := range resp.Results {
+			if txn.changedAt().Before(since) {
+				continue
+			}
+			if txn.TransactionID != "" {
+				if i, ok := index[txn.TransactionID]; ok {
+					if txn.changedAt().After(all[i].changedAt()) {
+						all[i] = txn
+					}
+					continue
+				}
+				index[txn.TransactionID] = len(all)
+			}
+			all = append(all, txn)
+		}
+		if resp.Next == "" || len(resp.Results) == 0 {
+			break
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if !all[i].CreatedAt.Equal(all[j].CreatedAt) {
+			return all[i].CreatedAt.Before(all[j].CreatedAt)
+		}
+		return all[i].TransactionID < all[j].TransactionID
+	})
+	return all, nil
+}
+
+// Summary computes totals in and out, fees, and the largest transactions
+// for a wallet over a period. IntaSend has no aggregation endpoint, so every
+// transaction page since period.Start is fetched and summarized client-side.
+//
+// Example:
+//
+//	summary, err := client.Wallet().Summary(ctx, "WALLET123", intasend.MonthOf(time.Now()))
+//	fmt.Printf("in=%.2f out=%.2f fees=%.2f\n", summary.TotalIn, summary.TotalOut, summary.Fees)
+func (s *WalletService) Summary(ctx context.Context, walletID string, period Period, reqOpts ...RequestOption) (*WalletSummary, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	txns, err := s.fetchTransactionsSince(ctx, walletID, period.Start)
+	if err != nil {
+		return nil, err
+	}
+	summary := summarizeTransactions(txns, period)
+	summary.WalletID = walletID
+	return summary, nil
+}
+
+// TransactionSearchOptions narrows SearchTransactions.
+type TransactionSearchOptions struct {
+	// Period limits results to transactions created within it. A zero
+	// Start searches the wallet's whole history.
+	Period Period
+
+	// TransTypes limits results to these transaction types; all types
+	// match when empty.
+	TransTypes []TransType
+}
+
+// SearchTransactions returns a wallet's transactions whose narrative
+// contains every word of query, ignoring case, oldest first. IntaSend has
+// no transaction search endpoint, so every transaction page since
+// opts.Period.Start is fetched and filtered client-side; set a period to
+// keep searches of busy wallets fast.
+//
+// Example:
+//
+//	txns, err := client.Wallet().SearchTransactions(ctx, "WALLET123", "refund jane", &intasend.TransactionSearchOptions{
+//	    Period: intasend.MonthOf(time.Now()),
+//	})
+func (s *WalletService) SearchTransactions(ctx context.Context, walletID, query string, opts *TransactionSearchOptions, reqOpts ...RequestOption) ([]WalletTransaction, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: query", ErrIncompleteRequest)
+	}
+	if opts == nil {
+		opts = &TransactionSearchOptions{}
+	}
+	txns, err := s.fetchTransactionsSince(ctx, walletID, opts.Period.Start)
+	if err != nil {
+		return nil, err
+	}
+	var matches []WalletTransaction
+	for _, tx := range txns {
+		if opts.Period.Contains(tx.CreatedAt) && hasTransType(opts.TransTypes, tx.TransType) && narrativeMatches(tx.Narrative, terms) {
+			matches = append(matches, tx)
+		}
+	}
+	return matches, nil
+}
+
+// hasTransType reports whether t is one of types, or types is empty.
+func hasTransType(types []TransType, t TransType) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, want := range types {
+		if strings.EqualFold(string(want), string(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// narrativeMatches reports whether narrative contains every one of the
+// lowercase terms.
+func narrativeMatches(narrative string, terms []string) bool {
+	narrative = strings.ToLower(narrative)
+	for _, term := range terms {
+		if !strings.Contains(narrative, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// summarizeTransactions aggregates the transactions that fall within period.
+func summarizeTransactions(txns []WalletTransaction, period Period) *WalletSummary {
+	summary := &WalletSummary{Period: period}
+	for _, tx := range txns {
+		if !period.Contains(tx.CreatedAt) {
+			continue
+		}
+		summary.Count++
+		amount := tx.SignedAmount()
+		switch {
+		case tx.TransType.IsFee():
+			summary.Fees += math.Abs(amount)
+		case amount >= 0:
+			summary.TotalIn += amount
+			summary.LargestIn = append(summary.LargestIn, tx)
+		default:
+			summary.TotalOut += -amount
+			summary.LargestOut = append(summary.LargestOut, tx)
+		}
+	}
+	summary.Net = summary.TotalIn - summary.TotalOut - summary.Fees
+	summary.LargestIn = largestTransactions(summary.LargestIn)
+	summary.LargestOut = largestTransactions(summary.LargestOut)
+	return summary
+}
+
+// largestTransactions returns up to summaryTopN transactions by absolute amount.
+func largestTransactions(txns []WalletTransaction) []WalletTransaction {
+	sort.SliceStable(txns, func(i, j int) bool {
+		return math.Abs(txns[i].Amount) > math.Abs(txns[j].Amount)
+	})
+	if len(txns) > summaryTopN {
+		txns = txns[:summaryTopN]
+	}
+	return txns
+}
+
 // IntraTransfer transfers funds between two wallets in the same account.
 //
 // Example:
@@ -232,10 +881,17 @@ func (s *WalletService) Transactions(ctx context.Context, walletID string) (*Wal
 //	    Amount:        1000,
 //	    Narrative:     "Commission transfer",
 //	})
-func (s *WalletService) IntraTransfer(ctx context.Context, req *IntraTransferRequest) (*IntraTransferResponse, error) {
+func (s *WalletService) IntraTransfer(ctx context.Context, req *IntraTransferRequest, reqOpts ...RequestOption) (*IntraTransferResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
 	body := &intraTransferBody{
 		WalletID:  req.DestinationID,
-		Amount:    req.Amount,
+		Amount:    requestAmount(req.AmountDecimal, req.Amount),
 		Narrative: req.Narrative,
 	}
 
@@ -247,6 +903,93 @@ func (s *WalletService) IntraTransfer(ctx context.Context, req *IntraTransferReq
 	return &resp, nil
 }
 
+// IntraTransferBatchOptions configures IntraTransferBatch.
+type IntraTransferBatchOptions struct {
+	BatchOptions
+
+	// CheckBalances verifies, before any transfer is made, that each
+	// source wallet's available balance covers the total it sends, and
+	// rejects the whole batch with ErrInsufficientBalance otherwise.
+	// IntaSend has no atomic multi-transfer, so once started, transfers
+	// can still fail individually.
+	CheckBalances bool
+}
+
+// IntraTransferResult is the outcome of one transfer in a batch.
+type IntraTransferResult struct {
+	Request  IntraTransferRequest
+	Response *IntraTransferResponse
+	Err      error
+}
+
+// IntraTransferBatch makes several intra-account transfers concurrently,
+// such as redistributing collected funds to sub-wallets. Results are
+// returned in request order. If any transfers fail, a *BatchError keyed by
+// "<index>:<source>-><destination>" is returned alongside the results.
+//
+// Example:
+//
+//	results, err := client.Wallet().IntraTransferBatch(ctx, []intasend.IntraTransferRequest{
+//	    {SourceID: "MAIN", DestinationID: "MERCHANT-1", Amount: 1500, Narrative: "Settlement"},
+//	    {SourceID: "MAIN", DestinationID: "MERCHANT-2", Amount: 900, Narrative: "Settlement"},
+//	}, &intasend.IntraTransferBatchOptions{CheckBalances: true})
+func (s *WalletService) IntraTransferBatch(ctx context.Context, reqs []IntraTransferRequest, opts *IntraTransferBatchOptions, reqOpts ...RequestOption) ([]IntraTransferResult, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var o IntraTransferBatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.CheckBalances {
+		if err := s.checkTransferBalances(ctx, reqs); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]IntraTransferResult, len(reqs))
+	var mu sync.Mutex
+	errs := make(map[string]error)
+
+	runBatch(len(reqs), o.concurrency(), func(i int) {
+		req := reqs[i]
+		resp, err := s.IntraTransfer(ctx, &req)
+		results[i] = IntraTransferResult{Request: req, Response: resp, Err: err}
+		if err != nil {
+			mu.Lock()
+			errs[fmt.Sprintf("%d:%s->%s", i, req.SourceID, req.DestinationID)] = err
+			mu.Unlock()
+		}
+	})
+
+	if len(errs) > 0 {
+		return results, &BatchError{Total: len(reqs), Errors: errs}
+	}
+	return results, nil
+}
+
+// checkTransferBalances returns ErrInsufficientBalance if any source wallet
+// cannot cover the transfers it sends.
+func (s *WalletService) checkTransferBalances(ctx context.Context, reqs []IntraTransferRequest) error {
+	totals := make(map[string]Amount)
+	var sources []string
+	for _, r := range reqs {
+		if _, ok := totals[r.SourceID]; !ok {
+			sources = append(sources, r.SourceID)
+		}
+		totals[r.SourceID] = totals[r.SourceID].Add(requestAmount(r.AmountDecimal, r.Amount))
+	}
+	for _, id := range sources {
+		wallet, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if available := wallet.AvailableBalanceDecimal(); totals[id].Cmp(available) > 0 {
+			return fmt.Errorf("%w: wallet %s sends %s but has %s available",
+				ErrInsufficientBalance, id, totals[id], available)
+		}
+	}
+	return nil
+}
+
 // FundMPesa initiates an M-Pesa STK Push to fund a wallet.
 //
 // Example:
@@ -258,12 +1001,19 @@ func (s *WalletService) IntraTransfer(ctx context.Context, req *IntraTransferReq
 //	    Email:       "customer@example.com",
 //	    APIRef:      "fund-wallet-001",
 //	})
-func (s *WalletService) FundMPesa(ctx context.Context, req *FundMPesaRequest) (*FundMPesaResponse, error) {
+func (s *WalletService) FundMPesa(ctx context.Context, req *FundMPesaRequest, reqOpts ...RequestOption) (*FundMPesaResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
 	body := &fundMPesaBody{
-		PublicKey:   s.client.publishableKey,
+		PublicKey:   s.client.publicKey(ctx),
 		WalletID:    req.WalletID,
 		PhoneNumber: req.PhoneNumber,
-		Amount:      req.Amount,
+		Amount:      requestAmount(req.AmountDecimal, req.Amount),
 		Email:       req.Email,
 		APIRef:      req.APIRef,
 		Method:      "M-PESA",
@@ -271,7 +1021,10 @@ func (s *WalletService) FundMPesa(ctx context.Context, req *FundMPesaRequest) (*
 	}
 
 	var resp FundMPesaResponse
-	if err := s.client.post(ctx, "/payment/mpesa-stk-push/", body, &resp); err != nil {
+	err := s.client.throttled([]string{req.PhoneNumber}, func() error {
+		return s.client.post(ctx, "/payment/mpesa-stk-push/", body, &resp)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -293,11 +1046,18 @@ func (s *WalletService) FundMPesa(ctx context.Context, req *FundMPesaRequest) (*
 //	    Host:        "https://yoursite.com",
 //	    RedirectURL: "https://yoursite.com/callback",
 //	})
-func (s *WalletService) FundCheckout(ctx context.Context, req *FundCheckoutRequest) (*FundCheckoutResponse, error) {
+func (s *WalletService) FundCheckout(ctx context.Context, req *FundCheckoutRequest, reqOpts ...RequestOption) (*FundCheckoutResponse, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := s.client.checkCall(req == nil); err != nil {
+		return nil, err
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
 	body := &fundCheckoutBody{
-		PublicKey:    s.client.publishableKey,
+		PublicKey:    s.client.publicKey(ctx),
 		WalletID:     req.WalletID,
-		Amount:       req.Amount,
+		Amount:       requestAmount(req.AmountDecimal, req.Amount),
 		Currency:     req.Currency,
 		Email:        req.Customer.Email,
 		FirstName:    req.Customer.FirstName,
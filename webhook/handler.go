@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// InvoiceEvent is a collection update: an invoice changed state, for
+// example when a payment completed or failed.
+type InvoiceEvent struct {
+	*Event
+	Invoice *intasend.Invoice
+}
+
+// Paid reports whether the invoice is complete.
+func (e *InvoiceEvent) Paid() bool {
+	return e.Invoice.State == intasend.StateComplete
+}
+
+// PayoutEvent is a payout batch update, such as a batch completing.
+type PayoutEvent struct {
+	*Event
+	Payout *intasend.PayoutStatusResponse
+}
+
+// ChargebackEvent is a chargeback (refund) update.
+type ChargebackEvent struct {
+	*Event
+	Chargeback *intasend.Chargeback
+}
+
+// HandlerOptions configures Handler. Callbacks left nil acknowledge their
+// events without doing anything.
+type HandlerOptions struct {
+	// Secrets supplies the challenges deliveries are verified against;
+	// see StaticSecret. Without it every delivery is refused with 503.
+	Secrets SecretProvider
+
+	OnInvoice    func(ctx context.Context, e *InvoiceEvent) error
+	OnPayout     func(ctx context.Context, e *PayoutEvent) error
+	OnChargeback func(ctx context.Context, e *ChargebackEvent) error
+
+	// OnUnknown receives deliveries that could not be classified.
+	OnUnknown func(ctx context.Context, e *Event) error
+
+	// OnError, if set, is called with every delivery rejected or failed,
+	// for logging. The event is nil when the delivery could not be parsed.
+	OnError func(ctx context.Context, e *Event, err error)
+}
+
+// Handler returns an http.HandlerFunc that verifies webhook deliveries,
+// decodes them into typed events, and routes them to the callbacks in
+// opts. Deliveries are acknowledged with 200 once their callback returns
+// nil. A callback error responds 500 so IntaSend retries the delivery;
+// invalid deliveries get the status from StatusCode.
+//
+// Example:
+//
+//	http.Handle("/webhooks/intasend", webhook.Handler(webhook.HandlerOptions{
+//	    Secrets: webhook.StaticSecret(client.WebhookChallenge()),
+//	    OnInvoice: func(ctx context.Context, e *webhook.InvoiceEvent) error {
+//	        if e.Paid() {
+//	            return orders.MarkPaid(ctx, e.Invoice.APIRef)
+//	        }
+//	        return nil
+//	    },
+//	    OnPayout: func(ctx context.Context, e *webhook.PayoutEvent) error {
+//	        return payouts.Update(ctx, e.Payout)
+//	    },
+//	}))
+func Handler(opts HandlerOptions) http.HandlerFunc {
+	if opts.Secrets == nil {
+		opts.Secrets = StaticSecret("")
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := ParseRequestWithProvider(r, opts.Secrets)
+		if err == nil {
+			err = opts.dispatch(ctx, event)
+		}
+		if err != nil {
+			if opts.OnError != nil {
+				opts.OnError(ctx, event, err)
+			}
+			http.Error(w, http.StatusText(StatusCode(err)), StatusCode(err))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// dispatch decodes e and calls its callback.
+func (o *HandlerOptions) dispatch(ctx context.Context, e *Event) error {
+	switch e.Type {
+	case EventInvoice:
+		if o.OnInvoice == nil {
+			return nil
+		}
+		inv, err := e.Invoice()
+		if err != nil {
+			return err
+		}
+		return o.OnInvoice(ctx, &InvoiceEvent{Event: e, Invoice: inv})
+	case EventPayout:
+		if o.OnPayout == nil {
+			return nil
+		}
+		p, err := e.Payout()
+		if err != nil {
+			return err
+		}
+		return o.OnPayout(ctx, &PayoutEvent{Event: e, Payout: p})
+	case EventChargeback:
+		if o.OnChargeback == nil {
+			return nil
+		}
+		cb, err := e.Chargeback()
+		if err != nil {
+			return err
+		}
+		return o.OnChargeback(ctx, &ChargebackEvent{Event: e, Chargeback: cb})
+	default:
+		if o.OnUnknown == nil {
+			return nil
+		}
+		return o.OnUnknown(ctx, e)
+	}
+}
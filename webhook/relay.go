@@ -0,0 +1,264 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+	"github.com/emilio-kariuki/intasend-go/backoff"
+)
+
+// Headers set on relayed deliveries.
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>" over
+	// "<t>.<body>", keyed with the relay secret.
+	SignatureHeader = "X-Webhook-Signature"
+
+	// DeliveryHeader carries an ID that is stable across retries of the
+	// same delivery, so receivers can deduplicate.
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+// Defaults for Relay.
+const (
+	DefaultRelayMaxAttempts = 5
+	DefaultRelayBackoff     = time.Second
+	DefaultRelayMaxBackoff  = 5 * time.Minute
+	DefaultSignatureMaxAge  = 5 * time.Minute
+)
+
+// ErrInvalidSignature is returned by VerifySignature.
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Delivery is a payload relayed to a downstream URL.
+type Delivery struct {
+	// ID is sent in DeliveryHeader and is the same for every attempt.
+	ID string
+
+	// URL is the downstream endpoint.
+	URL string
+
+	// Body is the JSON payload.
+	Body []byte
+
+	// Attempts is the number of delivery attempts made.
+	Attempts int
+}
+
+// RelayOptions configures a Relay.
+type RelayOptions struct {
+	// Secret signs every delivery. Required.
+	Secret []byte
+
+	// HTTPClient sends deliveries. Defaults to a client with a 10s timeout.
+	HTTPClient *http.Client
+
+	// MaxAttempts bounds delivery attempts, including the first.
+	// Defaults to DefaultRelayMaxAttempts.
+	MaxAttempts int
+
+	// Backoff is the wait before the first retry; it doubles on each
+	// further attempt up to MaxBackoff. Defaults to DefaultRelayBackoff
+	// and DefaultRelayMaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// OnDeadLetter, if set, receives deliveries that could not be made:
+	// attempts ran out, the receiver rejected the payload with a 4xx
+	// status, or ctx ended. Store them for replay.
+	OnDeadLetter func(d Delivery, err error)
+
+	// DeadLetterHandler, if set, also receives those deliveries, as
+	// intasend.DeadLetter values with Source DeadLetterWebhookRelay, so
+	// one handler can collect dead letters from every component.
+	DeadLetterHandler intasend.DeadLetterHandler
+}
+
+// Relay re-delivers payloads, typically IntaSend events, to downstream
+// webhooks with signatures and retries.
+//
+//	relay := webhook.NewRelay(webhook.RelayOptions{
+//	    Secret: []byte(os.Getenv("DOWNSTREAM_WEBHOOK_SECRET")),
+//	    OnDeadLetter: func(d webhook.Delivery, err error) {
+//	        log.Printf("dead letter %s to %s: %v", d.ID, d.URL, err)
+//	    },
+//	})
+//	go relay.Forward(context.Background(), "https://orders.internal/hooks/payments", event)
+type Relay struct {
+	opts RelayOptions
+}
+
+// NewRelay returns a Relay configured by opts.
+func NewRelay(opts RelayOptions) *Relay {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultRelayMaxAttempts
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultRelayBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultRelayMaxBackoff
+	}
+	return &Relay{opts: opts}
+}
+
+// Forward relays an IntaSend event's payload to url, using the event ID as
+// the delivery ID. The IntaSend challenge is removed from the payload so it
+// is never shared with downstream services.
+func (r *Relay) Forward(ctx context.Context, url string, e *Event) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(e.Payload, &fields); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	delete(fields, "challenge")
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return r.deliver(ctx, Delivery{ID: e.ID(), URL: url, Body: body})
+}
+
+// Deliver posts body to url, retrying network errors, 408, 429, and 5xx
+// responses with exponential backoff. It blocks until the delivery
+// succeeds or is dead-lettered.
+func (r *Relay) Deliver(ctx context.Context, url string, body []byte) error {
+	return r.deliver(ctx, Delivery{ID: newDeliveryID(), URL: url, Body: body})
+}
+
+func (r *Relay) deliver(ctx context.Context, d Delivery) error {
+	if len(r.opts.Secret) == 0 {
+		return errors.New("webhook: relay secret is required")
+	}
+
+	var err error
+	for d.Attempts < r.opts.MaxAttempts {
+		if d.Attempts > 0 {
+			timer := time.NewTimer(backoff.Exponential(r.opts.Backoff, r.opts.MaxBackoff, d.Attempts))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return r.deadLetter(d, errors.Join(ctx.Err(), err))
+			case <-timer.C:
+			}
+		}
+		d.Attempts++
+
+		var retry bool
+		retry, err = r.attempt(ctx, d)
+		if err == nil {
+			return nil
+		}
+		if !retry {
+			break
+		}
+	}
+	return r.deadLetter(d, err)
+}
+
+// attempt makes one delivery and reports whether a failure may be retried.
+func (r *Relay) attempt(ctx context.Context, d Delivery) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, d.ID)
+	req.Header.Set(SignatureHeader, Sign(r.opts.Secret, time.Now(), d.Body))
+
+	resp, err := r.opts.HTTPClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("webhook: %s responded %s", d.URL, resp.Status)
+	retry = resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500
+	return retry, err
+}
+
+func (r *Relay) deadLetter(d Delivery, err error) error {
+	if r.opts.OnDeadLetter != nil {
+		r.opts.OnDeadLetter(d, err)
+	}
+	if r.opts.DeadLetterHandler != nil {
+		r.opts.DeadLetterHandler.HandleDeadLetter(intasend.DeadLetter{
+			Source:   intasend.DeadLetterWebhookRelay,
+			Item:     d,
+			Attempts: d.Attempts,
+			Err:      err,
+			Time:     time.Now(),
+		})
+	}
+	return err
+}
+
+// Sign returns the SignatureHeader value for body signed at t.
+func Sign(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, body)
+}
+
+// VerifySignature checks a SignatureHeader value against body, rejecting
+// signatures older than maxAge (DefaultSignatureMaxAge if zero).
+// Downstream services use it to authenticate relayed deliveries.
+func VerifySignature(secret []byte, header string, body []byte, maxAge time.Duration) error {
+	if maxAge <= 0 {
+		maxAge = DefaultSignatureMaxAge
+	}
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	if age := time.Since(time.Unix(sec, 0)); age > maxAge || age < -maxAge {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, ts, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signature(secret []byte, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newDeliveryID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrSecretUnavailable is returned when a SecretProvider cannot supply a
+// challenge. StatusCode maps it to 503 so IntaSend retries the delivery.
+var ErrSecretUnavailable = errors.New("webhook: challenge secret unavailable")
+
+// SecretProvider supplies the webhook challenges accepted at verification
+// time, so the challenge can live in a secrets manager or KMS and rotate
+// without a redeploy. Implementations must be safe for concurrent use.
+type SecretProvider interface {
+	// Secrets returns the accepted challenges. While a challenge is being
+	// rotated, return both the new and the previous one so deliveries
+	// signed with either are accepted.
+	Secrets(ctx context.Context) ([]string, error)
+}
+
+// StaticSecret is a SecretProvider for a fixed challenge.
+type StaticSecret string
+
+// Secrets implements SecretProvider.
+func (s StaticSecret) Secrets(context.Context) ([]string, error) {
+	return []string{string(s)}, nil
+}
+
+// SecretProviderFunc adapts a function to a SecretProvider.
+//
+//	provider := webhook.SecretProviderFunc(func(ctx context.Context) ([]string, error) {
+//	    out, err := sm.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("intasend/webhook")})
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return []string{aws.ToString(out.SecretString)}, nil
+//	})
+type SecretProviderFunc func(ctx context.Context) ([]string, error)
+
+// Secrets implements SecretProvider.
+func (f SecretProviderFunc) Secrets(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// CachedSecretProvider caches another provider's challenges for a TTL so
+// a busy webhook endpoint does not call the secrets manager on every
+// delivery. If a refresh fails, the last challenges are kept in use until
+// the refresh succeeds.
+type CachedSecretProvider struct {
+	provider SecretProvider
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	secrets []string
+	fetched time.Time
+}
+
+// NewCachedSecretProvider returns a provider caching p's challenges for ttl.
+func NewCachedSecretProvider(p SecretProvider, ttl time.Duration) *CachedSecretProvider {
+	return &CachedSecretProvider{provider: p, ttl: ttl}
+}
+
+// Secrets implements SecretProvider.
+func (c *CachedSecretProvider) Secrets(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.secrets != nil && time.Since(c.fetched) < c.ttl {
+		return c.secrets, nil
+	}
+	secrets, err := c.provider.Secrets(ctx)
+	if err != nil || len(secrets) == 0 {
+		if c.secrets != nil {
+			return c.secrets, nil
+		}
+		return nil, err
+	}
+	c.secrets, c.fetched = secrets, time.Now()
+	return secrets, nil
+}
+
+// Invalidate drops the cached challenges so the next call fetches fresh
+// ones, for example after a rotation.
+func (c *CachedSecretProvider) Invalidate() {
+	c.mu.Lock()
+	c.secrets = nil
+	c.mu.Unlock()
+}
+
+// ParseWithProvider verifies body against the challenges supplied by p and
+// classifies it. Unlike Parse, verification is never skipped: a provider
+// that returns no challenges fails with ErrSecretUnavailable.
+func ParseWithProvider(ctx context.Context, body []byte, p SecretProvider) (*Event, error) {
+	secrets, err := p.Secrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSecretUnavailable, err)
+	}
+	var accepted []string
+	for _, s := range secrets {
+		if s != "" {
+			accepted = append(accepted, s)
+		}
+	}
+	if len(accepted) == 0 {
+		return nil, fmt.Errorf("%w: provider returned no challenges", ErrSecretUnavailable)
+	}
+	return parse(body, accepted)
+}
+
+// ParseRequestWithProvider reads a webhook request body and parses it with
+// ParseWithProvider, using the request's context.
+//
+//	provider := webhook.NewCachedSecretProvider(kmsProvider, 5*time.Minute)
+//	http.HandleFunc("/webhooks/intasend", func(w http.ResponseWriter, r *http.Request) {
+//	    event, err := webhook.ParseRequestWithProvider(r, provider)
+//	    if err != nil {
+//	        http.Error(w, err.Error(), webhook.StatusCode(err))
+//	        return
+//	    }
+//	    // handle event
+//	})
+func ParseRequestWithProvider(r *http.Request, p SecretProvider) (*Event, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return ParseWithProvider(r.Context(), body, p)
+}
@@ -0,0 +1,250 @@
+// Package webhook parses and verifies IntaSend webhook deliveries.
+//
+// IntaSend posts a JSON payload for collection (invoice), payout, and
+// chargeback updates. Each payload carries the challenge string configured in
+// the dashboard, which Parse verifies before returning the event:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    event, err := webhook.ParseRequest(r, client.WebhookChallenge())
+//	    if err != nil {
+//	        http.Error(w, err.Error(), webhook.StatusCode(err))
+//	        return
+//	    }
+//	    if event.Type == webhook.EventInvoice {
+//	        invoice, _ := event.Invoice()
+//	        log.Printf("invoice %s is %s", invoice.InvoiceID, invoice.State)
+//	    }
+//	}
+//
+// Handler does the same and routes each event, decoded into InvoiceEvent,
+// PayoutEvent, or ChargebackEvent, to a callback.
+//
+// IntaSend retries deliveries that are not acknowledged with a 2xx status,
+// so handlers should be idempotent; Event.ID identifies repeated deliveries.
+package webhook
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	intasend "github.com/emilio-kariuki/intasend-go"
+)
+
+// MaxBodyBytes limits the size of webhook bodies read by ParseRequest.
+const MaxBodyBytes = 1 << 20
+
+// Sentinel errors returned by Parse.
+var (
+	ErrInvalidChallenge = errors.New("webhook: challenge does not match")
+	ErrInvalidPayload   = errors.New("webhook: invalid payload")
+)
+
+// EventType identifies the resource a webhook describes.
+type EventType string
+
+const (
+	// EventInvoice is a collection (invoice state) update.
+	EventInvoice EventType = "invoice"
+
+	// EventPayout is a payout batch update.
+	EventPayout EventType = "payout"
+
+	// EventChargeback is a chargeback (refund) update.
+	EventChargeback EventType = "chargeback"
+
+	// EventUnknown is a payload that could not be classified.
+	EventUnknown EventType = "unknown"
+)
+
+// Event is a verified webhook delivery.
+type Event struct {
+	// Type is the kind of resource the payload describes.
+	Type EventType
+
+	// ResourceID is the invoice, tracking, or chargeback ID.
+	ResourceID string
+
+	// State is the resource state reported by the delivery.
+	State string
+
+	// UpdatedAt is the resource's last update time as sent by IntaSend.
+	UpdatedAt string
+
+	// Payload is the raw JSON body.
+	Payload json.RawMessage
+}
+
+// envelope holds the fields used to classify and verify a payload.
+type envelope struct {
+	Challenge    string `json:"challenge"`
+	InvoiceID    string `json:"invoice_id"`
+	TrackingID   string `json:"tracking_id"`
+	ChargebackID string `json:"chargeback_id"`
+	State        string `json:"state"`
+	Status       string `json:"status"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// Parse verifies and classifies a webhook body. An empty challenge skips
+// verification, which is only appropriate in tests. To fetch the challenge
+// from a secrets manager, use ParseWithProvider.
+func Parse(body []byte, challenge string) (*Event, error) {
+	if challenge == "" {
+		return parse(body, nil)
+	}
+	return parse(body, []string{challenge})
+}
+
+// parse verifies body against any of challenges, skipping verification when
+// there are none, and classifies it.
+func parse(body []byte, challenges []string) (*Event, error) {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if len(challenges) > 0 && !matchesChallenge(env.Challenge, challenges) {
+		return nil, ErrInvalidChallenge
+	}
+
+	event := &Event{Type: EventUnknown, UpdatedAt: env.UpdatedAt, Payload: json.RawMessage(body)}
+	switch {
+	case env.ChargebackID != "":
+		event.Type, event.ResourceID, event.State = EventChargeback, env.ChargebackID, env.Status
+	case env.TrackingID != "":
+		event.Type, event.ResourceID, event.State = EventPayout, env.TrackingID, env.Status
+	case env.InvoiceID != "":
+		event.Type, event.ResourceID, event.State = EventInvoice, env.InvoiceID, env.State
+	}
+	return event, nil
+}
+
+// matchesChallenge reports whether got equals any of challenges, comparing
+// in constant time.
+func matchesChallenge(got string, challenges []string) bool {
+	ok := 0
+	for _, c := range challenges {
+		ok |= subtle.ConstantTimeCompare([]byte(got), []byte(c))
+	}
+	return ok == 1
+}
+
+// ParseRequest reads and parses a webhook request body.
+func ParseRequest(r *http.Request, challenge string) (*Event, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return Parse(body, challenge)
+}
+
+// StatusCode maps a Parse error to the HTTP status a handler should return.
+// Challenge failures return 401 and malformed payloads 400; both tell
+// IntaSend not to expect a different outcome on retry. An unavailable
+// challenge secret returns 503 so the delivery is retried.
+func StatusCode(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrInvalidChallenge):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrInvalidPayload):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSecretUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ID returns a stable identifier for the delivery, derived from the event
+// type, resource ID, state, and update time. Retried deliveries of the same
+// update share an ID; later state changes get a new one.
+func (e *Event) ID() string {
+	sum := sha256.Sum256([]byte(string(e.Type) + "|" + e.ResourceID + "|" + e.State + "|" + e.UpdatedAt))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Invoice decodes an EventInvoice payload.
+func (e *Event) Invoice() (*intasend.Invoice, error) {
+	if e.Type != EventInvoice {
+		return nil, fmt.Errorf("webhook: %s event is not an invoice", e.Type)
+	}
+	var inv intasend.Invoice
+	if err := json.Unmarshal(e.Payload, &inv); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return &inv, nil
+}
+
+// Payout decodes an EventPayout payload.
+func (e *Event) Payout() (*intasend.PayoutStatusResponse, error) {
+	if e.Type != EventPayout {
+		return nil, fmt.Errorf("webhook: %s event is not a payout", e.Type)
+	}
+	var p intasend.PayoutStatusResponse
+	if err := json.Unmarshal(e.Payload, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return &p, nil
+}
+
+// Chargeback decodes an EventChargeback payload.
+func (e *Event) Chargeback() (*intasend.Chargeback, error) {
+	if e.Type != EventChargeback {
+		return nil, fmt.Errorf("webhook: %s event is not a chargeback", e.Type)
+	}
+	var p struct {
+		ChargebackID  string                `json:"chargeback_id"`
+		Invoice       string                `json:"invoice"`
+		Amount        amount                `json:"amount"`
+		Status        string                `json:"status"`
+		Reason        intasend.RefundReason `json:"reason"`
+		ReasonDetails string                `json:"reason_details"`
+		CreatedAt     time.Time             `json:"created_at"`
+		UpdatedAt     time.Time             `json:"updated_at"`
+	}
+	if err := json.Unmarshal(e.Payload, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return &intasend.Chargeback{
+		ChargebackID:  p.ChargebackID,
+		Invoice:       p.Invoice,
+		Amount:        float64(p.Amount),
+		Status:        p.Status,
+		Reason:        p.Reason,
+		ReasonDetails: p.ReasonDetails,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}, nil
+}
+
+// amount decodes webhook amounts, which IntaSend sends as either strings
+// ("100.00") or numbers.
+type amount float64
+
+func (a *amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if len(s) >= 2 && s[0] == '"' {
+		s = s[1 : len(s)-1]
+		if s == "" {
+			return nil
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("webhook: invalid amount %s", data)
+	}
+	*a = amount(v)
+	return nil
+}